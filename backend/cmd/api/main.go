@@ -3,6 +3,7 @@ package main
 import (
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/config"
 	"github.com/Hitomiblood/StockStream/internal/database"
@@ -31,14 +32,78 @@ func main() {
 	// Crear servicios
 	apiClient := services.NewAPIClient(cfg)
 	stockRepo := gormrepo.NewStockRepository(database.GetDB())
-	stockService := services.NewStockService(apiClient, stockRepo)
-	recommendationService := services.NewRecommendationService(stockRepo)
+	brokerageRepo := gormrepo.NewBrokerageRepository(database.GetDB())
+	tickerRepo := gormrepo.NewTickerRepository(database.GetDB())
+	anomalyRepo := gormrepo.NewAnomalyRepository(database.GetDB())
+	watchlistRepo := gormrepo.NewWatchlistRepository(database.GetDB())
+	alertRepo := gormrepo.NewAlertRepository(database.GetDB())
+	webhookRepo := gormrepo.NewWebhookRepository(database.GetDB())
+	ratingRepo := gormrepo.NewRatingRepository(database.GetDB())
+	ratingNormalizer := services.NewRatingNormalizer(ratingRepo)
+	if err := ratingNormalizer.Load(); err != nil {
+		log.Printf("⚠️  Failed to load rating mappings: %v", err)
+	}
+	stockService := services.NewStockService(apiClient, stockRepo, ratingNormalizer, services.NewActionClassifier(), services.NewTargetPriceParser())
+	eventBus := services.NewEventBus(1000)
+	stockService.SetEventPublisher(eventBus)
+	if err := stockService.RefreshSearchCandidates(); err != nil {
+		log.Printf("⚠️  Failed to load search candidates: %v", err)
+	}
+	reliabilityService := services.NewReliabilityService(stockRepo, brokerageRepo)
+	if err := reliabilityService.Load(); err != nil {
+		log.Printf("⚠️  Failed to load brokerage reliability: %v", err)
+	}
+	recommendationService := services.NewRecommendationServiceWithReliability(stockRepo, reliabilityService)
+	consensusService := services.NewConsensusService(stockRepo)
+	tickerIndex := services.NewTickerIndex(stockRepo)
+	if err := tickerIndex.Refresh(); err != nil {
+		log.Printf("⚠️  Failed to build ticker index: %v", err)
+	}
+	stockService.AddSyncListener(tickerIndex)
+	tickerCatalog := services.NewTickerCatalog(stockRepo, tickerRepo)
+	stockService.AddSyncListener(tickerCatalog)
+	anomalyDetector := services.NewAnomalyDetector(stockRepo, anomalyRepo)
+	stockService.AddSyncListener(anomalyDetector)
+	alertEngine := services.NewAlertEngine(alertRepo, watchlistRepo)
+	stockService.AddSyncListener(alertEngine)
+	recommendationTracker := services.NewRecommendationTracker(recommendationService, 10)
+	recommendationTracker.SetEventPublisher(eventBus)
+	if err := recommendationTracker.Load(); err != nil {
+		log.Printf("⚠️  Failed to load recommendation ranking: %v", err)
+	}
+	stockService.AddSyncResultListener(recommendationTracker)
+	webhookDispatcher := services.NewWebhookDispatcher(webhookRepo)
+	webhookDispatcher.SetAllowPrivateTargets(cfg.WebhookAllowPrivateTargets)
+	webhookDispatcher.ConsumeEvents(eventBus, nil)
+	stockService.AddSyncResultListener(webhookDispatcher)
+	brokerageDirectory := services.NewBrokerageDirectory(stockRepo)
+	analyticsService := services.NewAnalyticsService(stockRepo)
+	watchlistService := services.NewWatchlistService(watchlistRepo, stockRepo, recommendationService, consensusService)
 	log.Println("✅ Services initialized")
 
+	// Recalcular periódicamente la credibilidad de los brokerages
+	go reliabilityService.StartPeriodicRefresh(time.Duration(cfg.ReliabilityRefreshInterval)*time.Second, nil)
+
+	// Entregar la cola de webhooks y sus reintentos
+	go webhookDispatcher.StartDeliveryWorker(time.Duration(cfg.WebhookDeliveryInterval)*time.Second, nil)
+
 	// Crear handlers
-	stockHandler := handlers.NewStockHandler(stockService, recommendationService)
+	h := apiHandlers{
+		stock:     handlers.NewStockHandler(stockService, recommendationService),
+		brokerage: handlers.NewBrokerageHandler(reliabilityService, brokerageDirectory),
+		consensus: handlers.NewConsensusHandler(consensusService),
+		rating:    handlers.NewRatingHandler(ratingNormalizer),
+		ticker:    handlers.NewTickerHandler(tickerIndex, tickerCatalog),
+		analytics: handlers.NewAnalyticsHandler(analyticsService),
+		anomaly:   handlers.NewAnomalyHandler(anomalyDetector),
+		watchlist: handlers.NewWatchlistHandler(watchlistService),
+		alert:     handlers.NewAlertHandler(alertEngine),
+		webhook:   handlers.NewWebhookHandler(webhookDispatcher),
+		stream:    handlers.NewStreamHandler(eventBus),
+		websocket: handlers.NewWebSocketHandler(eventBus),
+	}
 
-	r := setupRouter(cfg, stockHandler)
+	r := setupRouter(cfg, h)
 
 	// Información de rutas disponibles
 	addr := cfg.APIHost + ":" + cfg.APIPort
@@ -51,7 +116,23 @@ func main() {
 	}
 }
 
-func setupRouter(cfg *config.Config, stockHandler *handlers.StockHandler) *gin.Engine {
+// apiHandlers agrupa los handlers HTTP registrados en el router
+type apiHandlers struct {
+	stock     *handlers.StockHandler
+	brokerage *handlers.BrokerageHandler
+	consensus *handlers.ConsensusHandler
+	rating    *handlers.RatingHandler
+	ticker    *handlers.TickerHandler
+	analytics *handlers.AnalyticsHandler
+	anomaly   *handlers.AnomalyHandler
+	watchlist *handlers.WatchlistHandler
+	alert     *handlers.AlertHandler
+	webhook   *handlers.WebhookHandler
+	stream    *handlers.StreamHandler
+	websocket *handlers.WebSocketHandler
+}
+
+func setupRouter(cfg *config.Config, h apiHandlers) *gin.Engine {
 	if cfg.LogLevel != "debug" {
 		gin.SetMode(gin.ReleaseMode)
 	}
@@ -64,19 +145,67 @@ func setupRouter(cfg *config.Config, stockHandler *handlers.StockHandler) *gin.E
 	})
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	r.GET("/health", stockHandler.HealthCheck)
+	r.GET("/health", h.stock.HealthCheck)
 
 	v1 := r.Group("/api/v1")
 	{
-		v1.GET("/stocks", stockHandler.GetAllStocks)
-		v1.GET("/stocks/latest", stockHandler.GetLatestStocks)
-		v1.GET("/stocks/search", stockHandler.SearchStocks)
-		v1.GET("/stocks/filter", stockHandler.FilterStocks)
-		v1.GET("/stocks/ticker/:ticker", stockHandler.GetStocksByTicker)
-		v1.GET("/stocks/:id", stockHandler.GetStockByID)
-		v1.POST("/stocks/fetch", stockHandler.FetchStocks)
-		v1.GET("/recommendations", stockHandler.GetRecommendations)
-		v1.GET("/metadata", stockHandler.GetMetadata)
+		v1.GET("/stocks", h.stock.GetAllStocks)
+		v1.GET("/stocks/latest", h.stock.GetLatestStocks)
+		v1.GET("/stocks/search", h.stock.SearchStocks)
+		v1.GET("/stocks/filter", h.stock.FilterStocks)
+		v1.GET("/stocks/ticker/:ticker", h.stock.GetStocksByTicker)
+		v1.GET("/stocks/ticker/:ticker/consensus", h.consensus.GetConsensus)
+		v1.GET("/stocks/ticker/:ticker/timeline", h.stock.GetTickerTimeline)
+		v1.GET("/stocks/:id", h.stock.GetStockByID)
+		v1.POST("/stocks/fetch", h.stock.FetchStocks)
+		v1.GET("/recommendations", h.stock.GetRecommendations)
+		v1.GET("/recommendations/bearish", h.stock.GetBearishRecommendations)
+		v1.GET("/recommendations/:ticker/explain", h.stock.ExplainRecommendation)
+		v1.GET("/metadata", h.stock.GetMetadata)
+		v1.GET("/anomalies", h.anomaly.ListAnomalies)
+		v1.GET("/analytics/sentiment", h.analytics.GetSentiment)
+		v1.GET("/analytics/rating-transitions", h.analytics.GetRatingTransitions)
+		v1.GET("/analytics/rating-distribution", h.analytics.GetRatingDistribution)
+		v1.GET("/brokerages", h.brokerage.ListBrokerages)
+		v1.GET("/brokerages/reliability", h.brokerage.GetReliability)
+		v1.GET("/brokerages/:name/calls", h.brokerage.GetBrokerageCalls)
+		v1.GET("/tickers", h.ticker.ListTickers)
+		v1.GET("/tickers/suggest", h.ticker.SuggestTickers)
+		v1.GET("/tickers/:ticker", h.ticker.GetTicker)
+		v1.GET("/admin/ratings/unmapped", h.rating.GetUnmapped)
+		v1.GET("/admin/ratings/mappings", h.rating.GetMappings)
+		v1.POST("/admin/ratings/mappings", h.rating.CreateMapping)
+		v1.GET("/stream", h.stream.Stream)
+		v1.GET("/ws", h.websocket.WebSocket)
+
+		// Recursos del usuario: el dueño sale de X-API-Key o X-User-ID
+		watchlists := v1.Group("/watchlists", middleware.RequireOwner())
+		watchlists.GET("", h.watchlist.ListWatchlists)
+		watchlists.POST("", h.watchlist.CreateWatchlist)
+		watchlists.GET("/:id", h.watchlist.GetWatchlist)
+		watchlists.PUT("/:id", h.watchlist.UpdateWatchlist)
+		watchlists.DELETE("/:id", h.watchlist.DeleteWatchlist)
+		watchlists.POST("/:id/tickers", h.watchlist.AddTickers)
+		watchlists.DELETE("/:id/tickers/:ticker", h.watchlist.RemoveTicker)
+		watchlists.GET("/:id/stocks", h.watchlist.GetWatchlistStocks)
+		watchlists.GET("/:id/recommendations", h.watchlist.GetWatchlistRecommendations)
+		watchlists.GET("/:id/consensus", h.watchlist.GetWatchlistConsensus)
+
+		alerts := v1.Group("/alerts", middleware.RequireOwner())
+		alerts.GET("", h.alert.ListAlerts)
+		alerts.GET("/rules", h.alert.ListRules)
+		alerts.POST("/rules", h.alert.CreateRule)
+		alerts.GET("/rules/:id", h.alert.GetRule)
+		alerts.PUT("/rules/:id", h.alert.UpdateRule)
+		alerts.DELETE("/rules/:id", h.alert.DeleteRule)
+
+		webhooks := v1.Group("/webhooks", middleware.RequireOwner())
+		webhooks.GET("", h.webhook.ListWebhooks)
+		webhooks.POST("", h.webhook.CreateWebhook)
+		webhooks.GET("/:id", h.webhook.GetWebhook)
+		webhooks.PUT("/:id", h.webhook.UpdateWebhook)
+		webhooks.DELETE("/:id", h.webhook.DeleteWebhook)
+		webhooks.GET("/:id/deliveries", h.webhook.ListDeliveries)
 	}
 
 	return r
@@ -12,7 +12,7 @@ import (
 
 func TestSetupRouter_RootRedirect(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	r := setupRouter(&config.Config{LogLevel: "debug"}, &handlers.StockHandler{})
+	r := setupRouter(&config.Config{LogLevel: "debug"}, testHandlers())
 
 	req := httptest.NewRequest(http.MethodGet, "/", nil)
 	w := httptest.NewRecorder()
@@ -28,10 +28,40 @@ func TestSetupRouter_RootRedirect(t *testing.T) {
 
 func TestSetupRouter_RegistersExpectedRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
-	r := setupRouter(&config.Config{LogLevel: "debug"}, &handlers.StockHandler{})
+	r := setupRouter(&config.Config{LogLevel: "debug"}, testHandlers())
 
 	routes := r.Routes()
 	if len(routes) < 10 {
 		t.Fatalf("expected many routes registered, got %d", len(routes))
 	}
 }
+
+func testHandlers() apiHandlers {
+	return apiHandlers{
+		stock:     &handlers.StockHandler{},
+		brokerage: &handlers.BrokerageHandler{},
+		consensus: &handlers.ConsensusHandler{},
+		rating:    &handlers.RatingHandler{},
+		ticker:    &handlers.TickerHandler{},
+		analytics: &handlers.AnalyticsHandler{},
+		anomaly:   &handlers.AnomalyHandler{},
+		watchlist: &handlers.WatchlistHandler{},
+		alert:     &handlers.AlertHandler{},
+		webhook:   &handlers.WebhookHandler{},
+		stream:    &handlers.StreamHandler{},
+		websocket: &handlers.WebSocketHandler{},
+	}
+}
+
+func TestSetupRouter_WatchlistsRequireOwner(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := setupRouter(&config.Config{LogLevel: "debug"}, testHandlers())
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/watchlists", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -11,12 +11,32 @@ import (
 	"strconv"
 
 	"github.com/Hitomiblood/StockStream/internal/config"
+	"github.com/Hitomiblood/StockStream/internal/database"
+	"github.com/Hitomiblood/StockStream/internal/repositories/gormrepo"
+	"github.com/Hitomiblood/StockStream/internal/services"
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/cockroachdb"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 	_ "github.com/lib/pq"
+	"gorm.io/gorm"
 )
 
+// dataTask es un comando que recorre los datos existentes con GORM y devuelve cuántos elementos procesó
+type dataTask struct {
+	label string
+	unit  string
+	run   func(db *gorm.DB) (int, error)
+}
+
+// dataTasks son los backfills de datos; corren sobre GORM y no sobre el driver de migraciones
+var dataTasks = map[string]dataTask{
+	"backfill-ratings":     {label: "Rating backfill", unit: "records", run: backfillRatings},
+	"backfill-event-types": {label: "Event type backfill", unit: "records", run: backfillEventTypes},
+	"backfill-targets":     {label: "Target backfill", unit: "records", run: backfillTargets},
+	"backfill-tickers":     {label: "Ticker backfill", unit: "tickers", run: backfillTickers},
+	"detect-anomalies":     {label: "Anomaly detection", unit: "anomalies", run: detectAnomalies},
+}
+
 func main() {
 	if len(os.Args) < 2 {
 		usageAndExit()
@@ -25,6 +45,14 @@ func main() {
 	cmd := os.Args[1]
 	cfg := config.Load()
 
+	if task, ok := dataTasks[cmd]; ok {
+		// log.Fatalf se llama fuera de runDataTask para que su Close diferido llegue a ejecutarse
+		if err := runDataTask(cfg, task); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
 	userInfo := url.User(cfg.DBUser)
 	if cfg.DBPassword != "" {
 		userInfo = url.UserPassword(cfg.DBUser, cfg.DBPassword)
@@ -108,10 +136,60 @@ func main() {
 }
 
 func usageAndExit() {
-	fmt.Println("Usage: go run ./cmd/migrate <up|down|version|steps N>")
+	fmt.Println("Usage: go run ./cmd/migrate <up|down|version|steps N|backfill-ratings|backfill-event-types|backfill-targets|backfill-tickers|detect-anomalies>")
 	os.Exit(2)
 }
 
+// runDataTask conecta la base, ejecuta la tarea y cierra la conexión incluso si falla
+func runDataTask(cfg *config.Config, task dataTask) error {
+	if err := database.Connect(cfg); err != nil {
+		return fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		_ = database.Close()
+	}()
+
+	count, err := task.run(database.GetDB())
+	if err != nil {
+		return fmt.Errorf("%s failed after %d %s: %w", task.label, count, task.unit, err)
+	}
+	log.Printf("✅ %s completed: %d %s", task.label, count, task.unit)
+	return nil
+}
+
+// backfillRatings completa las columnas de rating normalizado (migración 0003)
+// en los registros sincronizados antes de que existieran
+func backfillRatings(db *gorm.DB) (int, error) {
+	normalizer := services.NewRatingNormalizer(gormrepo.NewRatingRepository(db))
+	if err := normalizer.Load(); err != nil {
+		return 0, fmt.Errorf("failed to load rating mappings: %w", err)
+	}
+	return normalizer.Backfill(gormrepo.NewStockRepository(db))
+}
+
+// backfillEventTypes clasifica event_type (migración 0004) en los registros sincronizados antes de que existiera
+func backfillEventTypes(db *gorm.DB) (int, error) {
+	return services.NewActionClassifier().Backfill(gormrepo.NewStockRepository(db))
+}
+
+// backfillTargets completa las columnas numéricas de precio objetivo (migración 0005)
+// en los registros sincronizados antes de que existieran
+func backfillTargets(db *gorm.DB) (int, error) {
+	return services.NewTargetPriceParser().Backfill(gormrepo.NewStockRepository(db))
+}
+
+// backfillTickers puebla las tablas tickers/companies (migración 0006) con todo el historial existente
+func backfillTickers(db *gorm.DB) (int, error) {
+	catalog := services.NewTickerCatalog(gormrepo.NewStockRepository(db), gormrepo.NewTickerRepository(db))
+	return catalog.Rebuild()
+}
+
+// detectAnomalies analiza todo el historial existente y puebla la tabla anomalies (migración 0007)
+func detectAnomalies(db *gorm.DB) (int, error) {
+	detector := services.NewAnomalyDetector(gormrepo.NewStockRepository(db), gormrepo.NewAnomalyRepository(db))
+	return detector.DetectAll()
+}
+
 func findMigrationsDir() string {
 	// Prefer current working directory layout: ./migrations (when run from backend/)
 	if stat, err := os.Stat("migrations"); err == nil && stat.IsDir() {
@@ -15,9 +15,9 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/v1/metadata": {
+        "/api/v1/admin/ratings/mappings": {
             "get": {
-                "description": "Get unique values for actions and ratings available in the database",
+                "description": "List the administered raw rating to canonical rating mappings (built-in rules are not included)",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,30 +25,28 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "metadata"
+                    "admin"
                 ],
-                "summary": "Get metadata",
+                "summary": "Rating mappings",
                 "responses": {
                     "200": {
-                        "description": "Available actions and ratings",
+                        "description": "Rating mappings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to fetch metadata",
+                        "description": "Failed to fetch rating mappings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/recommendations": {
-            "get": {
-                "description": "Get intelligent stock recommendations based on multiple criteria",
+            },
+            "post": {
+                "description": "Map a raw rating string to a canonical rating. The mapping takes precedence over built-in rules and is applied to stored rows immediately",
                 "consumes": [
                     "application/json"
                 ],
@@ -56,84 +54,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "recommendations"
+                    "admin"
                 ],
-                "summary": "Get investment recommendations",
+                "summary": "Add rating mapping",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of recommendations (default: 10, max: 50)",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Mapping (score is optional, 0-5)",
+                        "name": "mapping",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ratingMappingRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Recommendations payload",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
-                    },
-                    "500": {
-                        "description": "Failed to generate recommendations",
+                    "201": {
+                        "description": "Mapping saved",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/api/v1/stocks": {
-            "get": {
-                "description": "Get all stocks with pagination, sorting and filtering",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "stocks"
-                ],
-                "summary": "List stocks",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Number of results (default: 50, max: 200)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Offset for pagination (default: 0)",
-                        "name": "offset",
-                        "in": "query"
                     },
-                    {
-                        "type": "string",
-                        "description": "Field to sort by (default: time)",
-                        "name": "sort",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort order: asc or desc (default: desc)",
-                        "name": "order",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "List of stocks",
+                    "400": {
+                        "description": "Invalid mapping",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to save rating mapping",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -142,9 +93,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stocks/fetch": {
-            "post": {
-                "description": "Fetch and synchronize all stocks from the external API to the database",
+        "/api/v1/admin/ratings/unmapped": {
+            "get": {
+                "description": "List raw rating strings that the normalizer could not map to the canonical taxonomy, with how many rating columns use each one",
                 "consumes": [
                     "application/json"
                 ],
@@ -152,19 +103,19 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "admin"
                 ],
-                "summary": "Sync stocks from external API",
+                "summary": "Unmapped raw ratings",
                 "responses": {
                     "200": {
-                        "description": "Sync completed successfully",
+                        "description": "Unmapped raw ratings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Failed to sync stocks",
+                        "description": "Failed to fetch unmapped ratings",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -173,30 +124,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stocks/filter": {
+        "/api/v1/alerts": {
             "get": {
-                "description": "Filter stocks by action, rating or both criteria",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get the alerts fired by the caller's rules, newest first, with the stock record that triggered each one.\nRules are evaluated against the records inserted or updated by each sync; a record fires a rule at most once",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "alerts"
                 ],
-                "summary": "Filter stocks",
+                "summary": "List fired alerts",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Filter by action (e.g., Upgrade, Downgrade, Initiated, Maintains)",
-                        "name": "action",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only alerts of this rule",
+                        "name": "rule_id",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Filter by rating (e.g., Buy, Sell, Hold)",
-                        "name": "rating",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Fired at or after (RFC3339 or YYYY-MM-DD)",
+                        "name": "since",
                         "in": "query"
                     },
                     {
@@ -214,21 +180,28 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Filtered results",
+                        "description": "Alerts",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "400": {
-                        "description": "Missing filter parameters",
+                        "description": "Invalid filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to fetch alerts",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -237,48 +210,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stocks/latest": {
+        "/api/v1/alerts/rules": {
             "get": {
-                "description": "Get the most recent stock updates ordered by time",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "List the caller's alert rules ordered by name",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "alerts"
                 ],
-                "summary": "Get latest stocks",
+                "summary": "List alert rules",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Number of results (default: 20, max: 100)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Latest stocks",
+                        "description": "Alert rules",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to fetch alert rules",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/stocks/search": {
-            "get": {
-                "description": "Search stocks by ticker or company name",
+            },
+            "post": {
+                "description": "Create a rule evaluated after each sync. All given conditions must match: tickers and/or watchlist_id, brokerages,\nevent_types, canonical rating_from/rating_to, and an inclusive min/max target change percentage. At least one condition is required",
                 "consumes": [
                     "application/json"
                 ],
@@ -286,41 +267,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "alerts"
                 ],
-                "summary": "Search stocks",
+                "summary": "Create alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search query (ticker or company name)",
-                        "name": "q",
-                        "in": "query",
-                        "required": true
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "description": "Number of results (default: 50, max: 200)",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Alert rule (enabled defaults to true)",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createAlertRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Search results",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid alert rule",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Missing search query",
+                    "401": {
+                        "description": "Missing owner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to create alert rule",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -329,63 +324,76 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/v1/stocks/ticker/{ticker}": {
+        "/api/v1/alerts/rules/{id}": {
             "get": {
-                "description": "Get all historical records for a specific stock ticker",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get one of the caller's alert rules",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "alerts"
                 ],
-                "summary": "Get stocks by ticker",
+                "summary": "Get alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Stock ticker symbol",
-                        "name": "ticker",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Stock history for ticker",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AlertRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid alert rule ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Invalid ticker",
+                    "401": {
+                        "description": "Missing owner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "No stocks found for ticker",
+                        "description": "Alert rule not found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "500": {
-                        "description": "Internal server error",
+                        "description": "Failed to fetch alert rule",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/api/v1/stocks/{id}": {
-            "get": {
-                "description": "Get detailed information of a specific stock",
+            },
+            "put": {
+                "description": "Update name, conditions and/or enabled. Omitted fields are kept; a conditions object replaces all conditions",
                 "consumes": [
                     "application/json"
                 ],
@@ -393,70 +401,3137 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "stocks"
+                    "alerts"
                 ],
-                "summary": "Get stock by ID",
+                "summary": "Update alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Stock ID (as string due to large ID values)",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateAlertRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Stock"
+                            "$ref": "#/definitions/models.AlertRule"
                         }
                     },
                     "400": {
-                        "description": "Invalid ID",
+                        "description": "Invalid alert rule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
                     "404": {
-                        "description": "Stock not found",
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update alert rule",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Check if the API is running",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete one of the caller's alert rules. Alerts it already fired are kept",
                 "tags": [
-                    "health"
+                    "alerts"
+                ],
+                "summary": "Delete alert rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Alert rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Health check",
                 "responses": {
-                    "200": {
-                        "description": "API is healthy",
+                    "204": {
+                        "description": "Alert rule deleted"
+                    },
+                    "400": {
+                        "description": "Invalid alert rule ID",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "models.ConfidenceLevel": {
-            "type": "integer",
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Alert rule not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete alert rule",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/rating-distribution": {
+            "get": {
+                "description": "Count tickers by the rating of their latest rated call",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Current rating distribution",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "canonical or raw (default: canonical)",
+                        "name": "mode",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rating distribution",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mode",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute rating distribution",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/rating-transitions": {
+            "get": {
+                "description": "Count how often each rating_from moved to each rating_to over a date range, as a matrix and as a list with\nthe share of each origin row. Canonical mode uses the normalized taxonomy; raw mode the rating text as received.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Rating transition matrix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "canonical or raw (default: canonical)",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brokerage names, comma separated or repeated (case-insensitive)",
+                        "name": "brokerage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tickers, comma separated or repeated",
+                        "name": "tickers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RatingTransitionMatrix"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid mode or filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute rating transitions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/analytics/sentiment": {
+            "get": {
+                "description": "Get counts of upgrades, downgrades, target raises, target cuts and initiations per day, week (starting Monday) or month in UTC,\nwith a net sentiment index from -100 to 100. Empty buckets between the first and last one with data are included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "analytics"
+                ],
+                "summary": "Market sentiment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bucket: day, week or month (default: day)",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brokerage names, comma separated or repeated (case-insensitive)",
+                        "name": "brokerage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tickers, comma separated or repeated",
+                        "name": "tickers",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SentimentSeries"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid bucket or range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute sentiment",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/anomalies": {
+            "get": {
+                "description": "Get unusual analyst activity detected after each sync, newest first: activity spikes (many firms acting on a ticker\nwithin days versus its baseline) and target outliers (a firm's target far from the median of the others),\nwith severity and the supporting stock records",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "List anomalies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "activity_spike or target_outlier",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "low, medium or high",
+                        "name": "severity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Anomalies",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kind or severity",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch anomalies",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/brokerages": {
+            "get": {
+                "description": "List every brokerage with its total calls, tickers covered, first and last activity, counts per event type and upgrade/downgrade ratio",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "brokerages"
+                ],
+                "summary": "Brokerage directory",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Sort by total_calls, tickers_covered, last_activity or brokerage (default: total_calls)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default: desc)",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Brokerage directory",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch brokerages",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/brokerages/reliability": {
+            "get": {
+                "description": "Get the credibility score learned from history for each brokerage and the multiplier applied in scoring",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "brokerages"
+                ],
+                "summary": "Brokerage reliability",
+                "responses": {
+                    "200": {
+                        "description": "Reliability table",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch brokerage reliability",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/brokerages/{name}/calls": {
+            "get": {
+                "description": "Get the paginated calls of one brokerage (case-insensitive), newest first. Accepts the same filters as GET /api/v1/stocks except brokerage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "brokerages"
+                ],
+                "summary": "Brokerage call history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Brokerage name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tickers, comma separated or repeated",
+                        "name": "tickers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, same syntax as GET /api/v1/stocks",
+                        "name": "filter",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Brokerage calls",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid stock filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Brokerage not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/metadata": {
+            "get": {
+                "description": "Get unique values for actions, raw ratings, normalized ratings and brokerages, plus record counts per event type",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metadata"
+                ],
+                "summary": "Get metadata",
+                "responses": {
+                    "200": {
+                        "description": "Available actions and ratings",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch metadata",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recommendations": {
+            "get": {
+                "description": "Get intelligent stock recommendations based on multiple criteria. Record filters are applied before scoring; score and confidence thresholds before ranking.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get investment recommendations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations (default: 10, max: 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum score (0-100); disables the fallback to lower scores",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum confidence: low, medium or high",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to include",
+                        "name": "brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to exclude",
+                        "name": "exclude_brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tickers to include",
+                        "name": "tickers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event types (e.g. upgrade, target_raise) or exact actions (e.g. upgraded by) to include",
+                        "name": "actions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lookback window in days (default: 30, widened to 90 when empty; max: 365)",
+                        "name": "lookback_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendations payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate recommendations",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recommendations/bearish": {
+            "get": {
+                "description": "Get the lowest-scored tickers (sell/avoid signals) with the same explanations as recommendations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Get bearish signals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of signals (default: 10, max: 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum confidence: low, medium or high",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to include",
+                        "name": "brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to exclude",
+                        "name": "exclude_brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated tickers to include",
+                        "name": "tickers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event types (e.g. downgrade, target_cut) or exact actions (e.g. downgraded by) to include",
+                        "name": "actions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lookback window in days (default: 30, widened to 90 when empty; max: 365)",
+                        "name": "lookback_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Bearish signals payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate bearish signals",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/recommendations/{ticker}/explain": {
+            "get": {
+                "description": "Get the full feature breakdown behind the recommendation score of a ticker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "recommendations"
+                ],
+                "summary": "Explain recommendation score",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.RecommendationExplanation"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "No stocks found for ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to explain recommendation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks": {
+            "get": {
+                "description": "Get all stocks with pagination, sorting and filtering",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "List stocks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Field to sort by, including target_from_value, target_to_value and target_change_pct (default: time)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default: desc)",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum numeric target_from",
+                        "name": "min_target_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum numeric target_from",
+                        "name": "max_target_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum numeric target_to",
+                        "name": "min_target_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum numeric target_to",
+                        "name": "max_target_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum target change percent",
+                        "name": "min_change_pct",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Maximum target change percent",
+                        "name": "max_change_pct",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target currency ISO code (e.g., USD)",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brokerage names, comma separated or repeated (case-insensitive)",
+                        "name": "brokerage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tickers, comma separated or repeated",
+                        "name": "tickers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Normalized target rating (strong_buy, buy, hold, sell, strong_sell, unmapped)",
+                        "name": "canonical_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. ticker in ('AAPL','MSFT') and (target_change_pct gt 10 or action contains 'raised')",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to cursor for keyset pagination (implied when cursor is present)",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque next_cursor/prev_cursor from a previous cursor page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of stocks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid stock filters or cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/fetch": {
+            "post": {
+                "description": "Fetch and synchronize all stocks from the external API to the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Sync stocks from external API",
+                "responses": {
+                    "200": {
+                        "description": "Sync completed successfully",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to sync stocks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/filter": {
+            "get": {
+                "description": "Filter stocks by action, event type, raw rating, normalized rating, a filter expression or any combination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Filter stocks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by action (e.g., Upgrade, Downgrade, Initiated, Maintains)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by raw rating (e.g., Buy, Sell, Hold)",
+                        "name": "rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)",
+                        "name": "event_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by normalized rating (strong_buy, buy, hold, sell, strong_sell, unmapped)",
+                        "name": "canonical_rating",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression over the sortable fields (eq, ne, in, gt, lt, contains, and, or, parentheses)",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Set to cursor for keyset pagination ordered by time desc",
+                        "name": "pagination",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque next_cursor/prev_cursor from a previous cursor page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Filtered results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid filter parameters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/latest": {
+            "get": {
+                "description": "Get the most recent stock updates ordered by time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Get latest stocks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 20, max: 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest stocks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/search": {
+            "get": {
+                "description": "Ranked search by ticker prefix, company name (typo tolerant) and brokerage. Returns one result per ticker:\ndata holds the latest record of each ticker and results the ranking, record count and highlights.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Search stocks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search query (ticker, company or brokerage)",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of tickers (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Search results",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing search query",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/ticker/{ticker}": {
+            "get": {
+                "description": "Get all historical records for a specific stock ticker",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Get stocks by ticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock history for ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "No stocks found for ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/ticker/{ticker}/consensus": {
+            "get": {
+                "description": "Get mean/median/high/low target and rating distribution across brokerages using the latest call of each firm within the window",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Consensus price target",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lookback window in days (default: 90, max: 365)",
+                        "name": "window_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TickerConsensus"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "No stocks found for ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute consensus",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/ticker/{ticker}/timeline": {
+            "get": {
+                "description": "Get chart-ready series for a ticker: target price steps per brokerage, rating state changes and event counts per day, week (starting Monday) or month in UTC",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Ticker timeline",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Records at or after this time (RFC3339 or YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Records at or before this time (RFC3339 or YYYY-MM-DD, whole day)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Event bucket: day, week or month (default: day)",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TickerTimeline"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ticker, range or bucket",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "No stocks found for ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stocks/{id}": {
+            "get": {
+                "description": "Get detailed information of a specific stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stocks"
+                ],
+                "summary": "Get stock by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ID (as string due to large ID values)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Stock"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Stock not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/stream": {
+            "get": {
+                "description": "Server-Sent Events stream of stock.created, stock.updated, sync.started, sync.progress, sync.completed, sync.failed\nand recommendation.changed, published while a sync processes records. Each event carries an id; reconnect with the Last-Event-ID header\n(or last_event_id) to resume from a bounded in-memory buffer. A stream.reset event means events were lost and\nthe client should refetch. ticker and brokerage filter stock and recommendation events only. A heartbeat comment is sent every 15 seconds",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream sync events (SSE)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only stock and recommendation events of these tickers (comma-separated)",
+                        "name": "ticker",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only stock events of these brokerages (comma-separated)",
+                        "name": "brokerage",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resume after this event id",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Resume after this event id (for clients that cannot send headers)",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "text/event-stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid Last-Event-ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tickers": {
+            "get": {
+                "description": "Ticker entities with canonical company, first/last seen dates, record count and latest rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tickers"
+                ],
+                "summary": "List tickers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: ticker, first_seen, last_seen or record_count (default: last_seen)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: asc or desc (default: desc)",
+                        "name": "order",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of tickers",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tickers/suggest": {
+            "get": {
+                "description": "Distinct ticker/company pairs whose ticker or company starts with q, ranked by match type and recent activity. Served from an in-memory index rebuilt after each sync.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tickers"
+                ],
+                "summary": "Ticker autocomplete",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix of a ticker or company name",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of suggestions (default: 10, max: 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Suggestions",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Missing query",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/tickers/{ticker}": {
+            "get": {
+                "description": "Ticker entity with its canonical company and latest rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tickers"
+                ],
+                "summary": "Get ticker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Ticker"
+                        }
+                    },
+                    "404": {
+                        "description": "Ticker not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists": {
+            "get": {
+                "description": "List the caller's watchlists ordered by name. The owner is the X-API-Key header or, without it, X-User-ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "List watchlists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Watchlists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch watchlists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a named watchlist with optional notes and tickers. Names are unique per owner; tickers are upper-cased and de-duplicated",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Create watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Watchlist",
+                        "name": "watchlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createWatchlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Watchlist name already in use",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}": {
+            "get": {
+                "description": "Get one of the caller's watchlists with its tickers",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Get watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update name, notes and/or tickers. Omitted fields are kept; a tickers array replaces the whole list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Update watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "watchlist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateWatchlistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Watchlist name already in use",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete one of the caller's watchlists and its tickers",
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Delete watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Watchlist deleted"
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}/consensus": {
+            "get": {
+                "description": "Get the price target and rating consensus of each ticker in the watchlist. Tickers without records are omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Consensus for a watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lookback window in days (default: 90, max: 365)",
+                        "name": "window_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Consensus per ticker",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to compute consensus",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}/recommendations": {
+            "get": {
+                "description": "Rank only the tickers of the watchlist with the recommendation model. Accepts the same filters as /recommendations except tickers",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Recommendations for a watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recommendations (default: 10, max: 50)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Minimum score (0-100)",
+                        "name": "min_score",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Minimum confidence: low, medium or high",
+                        "name": "min_confidence",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to include",
+                        "name": "brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated brokerages to exclude",
+                        "name": "exclude_brokerages",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated action types to include",
+                        "name": "actions",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Lookback window in days (default: 30, max: 365)",
+                        "name": "lookback_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recommendations payload",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID or filters",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to generate recommendations",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}/stocks": {
+            "get": {
+                "description": "Get the latest analyst record of each ticker in the watchlist, newest first. Tickers without records are omitted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Latest stocks of a watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Latest stocks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch watchlist stocks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}/tickers": {
+            "post": {
+                "description": "Append tickers to a watchlist; tickers already present are ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Add tickers to watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Tickers to add",
+                        "name": "tickers",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.watchlistTickersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tickers",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/watchlists/{id}/tickers/{ticker}": {
+            "delete": {
+                "description": "Remove a single ticker from a watchlist",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "watchlists"
+                ],
+                "summary": "Remove ticker from watchlist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Watchlist ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock ticker symbol",
+                        "name": "ticker",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Watchlist"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid watchlist ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Watchlist or ticker not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update watchlist",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks": {
+            "get": {
+                "description": "List the caller's webhook subscriptions. Secrets are never included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhooks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Webhooks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch webhooks",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribe a URL to stock.created, stock.updated, sync.completed and/or recommendation.changed events.\nEach event is POSTed as JSON signed with HMAC-SHA256 (X-StockStream-Signature: sha256=hex of timestamp + \".\" + body)\nand retried with exponential backoff until a 2xx response. The secret is generated when omitted and only returned here",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Webhook (enabled defaults to true)",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.createWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to create webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}": {
+            "get": {
+                "description": "Get one of the caller's webhooks. The secret is not included",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Get webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update url, secret, event_types and/or enabled. Omitted fields are kept. An empty secret rotates it to a generated one;\nthe response includes the secret only when it changed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.updateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to update webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete one of the caller's webhooks together with its pending deliveries and delivery log",
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Webhook deleted"
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to delete webhook",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/webhooks/{id}/deliveries": {
+            "get": {
+                "description": "Delivery log of a webhook, newest first: payload, status (pending, delivered or failed), attempts,\nlast response status and error, and the next retry for pending deliveries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhooks"
+                ],
+                "summary": "List webhook deliveries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key identifying the owner",
+                        "name": "X-API-Key",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "User identifier (used when no API key is sent)",
+                        "name": "X-User-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Delivery status: pending, delivered or failed",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of results (default: 50, max: 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset for pagination (default: 0)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Deliveries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid webhook ID or status",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing owner",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Webhook not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Failed to fetch deliveries",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v1/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket fed by the same event bus as /stream. Messages are JSON objects with a \"type\":\nthe client sends subscribe/unsubscribe with \"tickers\" (or \"*\"), \"recommendations\" and \"sync\", and ping;\nthe server answers with subscriptions (current state), event, pong and error, and sends ping every 30 seconds.\nA client that sends nothing for two ping intervals, or falls too far behind the event stream, is disconnected",
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Subscribe to live events (WebSocket)",
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Not a WebSocket upgrade request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is running",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "API is healthy",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.createAlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/models.AlertConditions"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.createWatchlistRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "tickers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.createWebhookRequest": {
+            "type": "object",
+            "required": [
+                "event_types",
+                "url"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookEventType"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ratingMappingRequest": {
+            "type": "object",
+            "required": [
+                "canonical",
+                "raw_rating"
+            ],
+            "properties": {
+                "canonical": {
+                    "type": "string"
+                },
+                "raw_rating": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "handlers.updateAlertRuleRequest": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/models.AlertConditions"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.updateWatchlistRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "tickers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.updateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookEventType"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.watchlistTickersRequest": {
+            "type": "object",
+            "required": [
+                "tickers"
+            ],
+            "properties": {
+                "tickers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ActionEvent": {
+            "type": "string",
+            "enum": [
+                "upgrade",
+                "downgrade",
+                "target_raise",
+                "target_cut",
+                "initiation",
+                "reiteration",
+                "coverage_drop",
+                "other"
+            ],
+            "x-enum-varnames": [
+                "ActionEventUpgrade",
+                "ActionEventDowngrade",
+                "ActionEventTargetRaise",
+                "ActionEventTargetCut",
+                "ActionEventInitiation",
+                "ActionEventReiteration",
+                "ActionEventCoverageDrop",
+                "ActionEventOther"
+            ]
+        },
+        "models.AlertConditions": {
+            "type": "object",
+            "properties": {
+                "brokerages": {
+                    "description": "Brokerages se comparan sin distinguir mayúsculas",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActionEvent"
+                    }
+                },
+                "max_target_change_pct": {
+                    "type": "number"
+                },
+                "min_target_change_pct": {
+                    "description": "Rango inclusivo sobre TargetChangePct; los registros sin cambio de precio objetivo no lo cumplen",
+                    "type": "number"
+                },
+                "rating_from": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CanonicalRating"
+                    }
+                },
+                "rating_to": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CanonicalRating"
+                    }
+                },
+                "tickers": {
+                    "description": "Tickers y WatchlistID restringen el conjunto de tickers; si vienen ambos el ticker debe estar en los dos",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "watchlist_id": {
+                    "type": "string",
+                    "example": "0"
+                }
+            }
+        },
+        "models.AlertRule": {
+            "type": "object",
+            "properties": {
+                "conditions": {
+                    "$ref": "#/definitions/models.AlertConditions"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BrokerageTargetSeries": {
+            "type": "object",
+            "properties": {
+                "brokerage": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TargetStep"
+                    }
+                }
+            }
+        },
+        "models.CanonicalRating": {
+            "type": "string",
+            "enum": [
+                "strong_buy",
+                "buy",
+                "hold",
+                "sell",
+                "strong_sell",
+                "unmapped"
+            ],
+            "x-enum-varnames": [
+                "RatingStrongBuy",
+                "RatingBuy",
+                "RatingHold",
+                "RatingSell",
+                "RatingStrongSell",
+                "RatingUnmapped"
+            ]
+        },
+        "models.Company": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConfidenceLevel": {
+            "type": "integer",
             "enum": [
                 0,
                 1,
@@ -468,6 +3543,297 @@ const docTemplate = `{
                 "ConfidenceHigh"
             ]
         },
+        "models.ConsensusCall": {
+            "type": "object",
+            "properties": {
+                "brokerage": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "string"
+                },
+                "rating_bucket": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ConsensusItem": {
+            "type": "object",
+            "properties": {
+                "brokerage_weight": {
+                    "type": "number"
+                },
+                "recency_weight": {
+                    "type": "number"
+                },
+                "signal": {
+                    "type": "number"
+                },
+                "stock": {
+                    "$ref": "#/definitions/models.Stock"
+                }
+            }
+        },
+        "models.RatingChange": {
+            "type": "object",
+            "properties": {
+                "brokerage": {
+                    "type": "string"
+                },
+                "event_type": {
+                    "$ref": "#/definitions/models.ActionEvent"
+                },
+                "rating_from": {
+                    "type": "string"
+                },
+                "rating_from_canonical": {
+                    "$ref": "#/definitions/models.CanonicalRating"
+                },
+                "rating_to": {
+                    "type": "string"
+                },
+                "rating_to_canonical": {
+                    "$ref": "#/definitions/models.CanonicalRating"
+                },
+                "rating_to_score": {
+                    "type": "number"
+                },
+                "stock_id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RatingDistribution": {
+            "type": "object",
+            "properties": {
+                "buy": {
+                    "type": "integer"
+                },
+                "hold": {
+                    "type": "integer"
+                },
+                "sell": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RatingMode": {
+            "type": "string",
+            "enum": [
+                "canonical",
+                "raw"
+            ],
+            "x-enum-varnames": [
+                "RatingModeCanonical",
+                "RatingModeRaw"
+            ]
+        },
+        "models.RatingTransition": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "rating_from": {
+                    "type": "string"
+                },
+                "rating_to": {
+                    "type": "string"
+                },
+                "share": {
+                    "description": "Share es la fracción de las llamadas que partían de RatingFrom que terminaron en RatingTo",
+                    "type": "number"
+                }
+            }
+        },
+        "models.RatingTransitionMatrix": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "integer",
+                            "format": "int64"
+                        }
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/models.RatingMode"
+                },
+                "ratings": {
+                    "description": "Ratings son las etiquetas de filas y columnas; Counts[i][j] cuenta Ratings[i] -\u003e Ratings[j]",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "transitions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RatingTransition"
+                    }
+                }
+            }
+        },
+        "models.RecommendationExplanation": {
+            "type": "object",
+            "properties": {
+                "brokerage_multiplier": {
+                    "type": "number"
+                },
+                "confidence": {
+                    "$ref": "#/definitions/models.ConfidenceLevel"
+                },
+                "confidence_rule": {
+                    "type": "string"
+                },
+                "data_quality": {
+                    "type": "number"
+                },
+                "factors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RecommendationFactor"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsensusItem"
+                    }
+                },
+                "history_count": {
+                    "type": "integer"
+                },
+                "quality_factor": {
+                    "type": "number"
+                },
+                "raw_score": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "stock": {
+                    "$ref": "#/definitions/models.Stock"
+                },
+                "ticker": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RecommendationFactor": {
+            "type": "object",
+            "properties": {
+                "contribution": {
+                    "type": "number"
+                },
+                "explanation": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SentimentPoint": {
+            "type": "object",
+            "properties": {
+                "downgrades": {
+                    "type": "integer"
+                },
+                "initiations": {
+                    "type": "integer"
+                },
+                "net_sentiment": {
+                    "description": "NetSentiment va de -100 (solo downgrades y recortes) a 100 (solo upgrades y subidas de target)",
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "target_cuts": {
+                    "type": "integer"
+                },
+                "target_raises": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "upgrades": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SentimentSeries": {
+            "type": "object",
+            "properties": {
+                "brokerages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "bucket": {
+                    "$ref": "#/definitions/models.TimelineBucket"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SentimentPoint"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.SentimentPoint"
+                },
+                "tickers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
         "models.Stock": {
             "type": "object",
             "properties": {
@@ -483,6 +3849,14 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
+                "event_type": {
+                    "description": "Tipo de evento derivado de Action al ingerir (ver migración 0004)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ActionEvent"
+                        }
+                    ]
+                },
                 "id": {
                     "type": "string",
                     "example": "0"
@@ -490,15 +3864,45 @@ const docTemplate = `{
                 "rating_from": {
                     "type": "string"
                 },
+                "rating_from_canonical": {
+                    "description": "Ratings normalizados al ingerir (ver migración 0003)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.CanonicalRating"
+                        }
+                    ]
+                },
+                "rating_from_score": {
+                    "type": "number"
+                },
                 "rating_to": {
                     "type": "string"
                 },
+                "rating_to_canonical": {
+                    "$ref": "#/definitions/models.CanonicalRating"
+                },
+                "rating_to_score": {
+                    "type": "number"
+                },
+                "target_change_pct": {
+                    "type": "number"
+                },
+                "target_currency": {
+                    "type": "string"
+                },
                 "target_from": {
                     "type": "string"
                 },
+                "target_from_value": {
+                    "description": "Precios objetivo numéricos derivados al ingerir (ver migración 0005); nil si no se pudieron interpretar",
+                    "type": "number"
+                },
                 "target_to": {
                     "type": "string"
                 },
+                "target_to_value": {
+                    "type": "number"
+                },
                 "ticker": {
                     "type": "string"
                 },
@@ -510,22 +3914,266 @@ const docTemplate = `{
                 }
             }
         },
-        "models.StockRecommendation": {
+        "models.TargetStep": {
             "type": "object",
             "properties": {
-                "confidence": {
-                    "$ref": "#/definitions/models.ConfidenceLevel"
+                "currency": {
+                    "type": "string"
                 },
-                "reason": {
+                "event_type": {
+                    "$ref": "#/definitions/models.ActionEvent"
+                },
+                "previous_target": {
+                    "type": "number"
+                },
+                "stock_id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Ticker": {
+            "type": "object",
+            "properties": {
+                "company": {
+                    "$ref": "#/definitions/models.Company"
+                },
+                "company_id": {
+                    "type": "integer"
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "score": {
+                "first_seen": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "type": "string"
+                },
+                "latest_brokerage": {
+                    "type": "string"
+                },
+                "latest_rating": {
+                    "type": "string"
+                },
+                "latest_rating_canonical": {
+                    "$ref": "#/definitions/models.CanonicalRating"
+                },
+                "latest_stock_id": {
+                    "type": "integer"
+                },
+                "record_count": {
+                    "type": "integer"
+                },
+                "ticker": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TickerConsensus": {
+            "type": "object",
+            "properties": {
+                "calls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ConsensusCall"
+                    }
+                },
+                "covering_firms": {
+                    "type": "integer"
+                },
+                "high_target": {
                     "type": "number"
                 },
-                "stock": {
-                    "$ref": "#/definitions/models.Stock"
+                "low_target": {
+                    "type": "number"
+                },
+                "mean_target": {
+                    "type": "number"
+                },
+                "median_target": {
+                    "type": "number"
+                },
+                "rating_distribution": {
+                    "$ref": "#/definitions/models.RatingDistribution"
+                },
+                "target_count": {
+                    "type": "integer"
+                },
+                "target_currency": {
+                    "type": "string"
+                },
+                "ticker": {
+                    "type": "string"
+                },
+                "window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TickerTimeline": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "$ref": "#/definitions/models.TimelineBucket"
+                },
+                "company": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimelineEvents"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "rating_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RatingChange"
+                    }
+                },
+                "records": {
+                    "type": "integer"
+                },
+                "targets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BrokerageTargetSeries"
+                    }
+                },
+                "ticker": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TimelineBucket": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month"
+            ],
+            "x-enum-varnames": [
+                "TimelineBucketDay",
+                "TimelineBucketWeek",
+                "TimelineBucketMonth"
+            ]
+        },
+        "models.TimelineEvents": {
+            "type": "object",
+            "properties": {
+                "by_event_type": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "start": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Watchlist": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "tickers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WatchlistTicker"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WatchlistTicker": {
+            "type": "object",
+            "properties": {
+                "added_at": {
+                    "type": "string"
+                },
+                "ticker": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WebhookEventType"
+                    }
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "secret": {
+                    "description": "Secret firma los payloads; solo se devuelve al crear el webhook o al cambiarlo",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
                 }
             }
+        },
+        "models.WebhookEventType": {
+            "type": "string",
+            "enum": [
+                "stock.created",
+                "stock.updated",
+                "sync.completed",
+                "recommendation.changed"
+            ],
+            "x-enum-varnames": [
+                "WebhookEventStockCreated",
+                "WebhookEventStockUpdated",
+                "WebhookEventSyncCompleted",
+                "WebhookEventRecommendationChanged"
+            ]
         }
     }
 }`
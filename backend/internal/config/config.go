@@ -27,8 +27,11 @@ type Config struct {
 	APIHost string
 
 	// Configuración
-	FetchInterval int
-	LogLevel      string
+	FetchInterval              int
+	ReliabilityRefreshInterval int
+	WebhookDeliveryInterval    int
+	WebhookAllowPrivateTargets bool
+	LogLevel                   string
 }
 
 func Load() *Config {
@@ -41,21 +44,27 @@ func Load() *Config {
 
 	dbPort, _ := strconv.Atoi(getEnv("DB_PORT", "26257"))
 	fetchInterval, _ := strconv.Atoi(getEnv("FETCH_INTERVAL", "3600"))
+	reliabilityRefreshInterval, _ := strconv.Atoi(getEnv("RELIABILITY_REFRESH_INTERVAL", "21600"))
+	webhookDeliveryInterval, _ := strconv.Atoi(getEnv("WEBHOOK_DELIVERY_INTERVAL", "15"))
+	webhookAllowPrivateTargets, _ := strconv.ParseBool(getEnv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "false"))
 
 	return &Config{
-		ExternalAPIURL:   getEnv("EXTERNAL_API_URL", ""),
-		ExternalAPIToken: getEnv("EXTERNAL_API_TOKEN", ""),
-		DBHost:           getEnv("DB_HOST", "localhost"),
-		DBPort:           dbPort,
-		DBUser:           getEnv("DB_USER", "root"),
-		DBPassword:       getEnv("DB_PASSWORD", ""),
-		DBName:           getEnv("DB_NAME", "stockdb"),
-		DBSchema:         getEnv("DB_SCHEMA", "public"),
-		DBSSLMode:        getEnv("DB_SSLMODE", "disable"),
-		APIPort:          getEnv("API_PORT", "8080"),
-		APIHost:          getEnv("API_HOST", "localhost"),
-		FetchInterval:    fetchInterval,
-		LogLevel:         getEnv("LOG_LEVEL", "info"),
+		ExternalAPIURL:             getEnv("EXTERNAL_API_URL", ""),
+		ExternalAPIToken:           getEnv("EXTERNAL_API_TOKEN", ""),
+		DBHost:                     getEnv("DB_HOST", "localhost"),
+		DBPort:                     dbPort,
+		DBUser:                     getEnv("DB_USER", "root"),
+		DBPassword:                 getEnv("DB_PASSWORD", ""),
+		DBName:                     getEnv("DB_NAME", "stockdb"),
+		DBSchema:                   getEnv("DB_SCHEMA", "public"),
+		DBSSLMode:                  getEnv("DB_SSLMODE", "disable"),
+		APIPort:                    getEnv("API_PORT", "8080"),
+		APIHost:                    getEnv("API_HOST", "localhost"),
+		FetchInterval:              fetchInterval,
+		ReliabilityRefreshInterval: reliabilityRefreshInterval,
+		WebhookDeliveryInterval:    webhookDeliveryInterval,
+		WebhookAllowPrivateTargets: webhookAllowPrivateTargets,
+		LogLevel:                   getEnv("LOG_LEVEL", "info"),
 	}
 }
 
@@ -26,11 +26,14 @@ func TestLoad_ReadsEnvironment(t *testing.T) {
 	t.Setenv("API_PORT", "9999")
 	t.Setenv("API_HOST", "0.0.0.0")
 	t.Setenv("FETCH_INTERVAL", "120")
+	t.Setenv("RELIABILITY_REFRESH_INTERVAL", "600")
+	t.Setenv("WEBHOOK_DELIVERY_INTERVAL", "30")
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE_TARGETS", "true")
 	t.Setenv("LOG_LEVEL", "debug")
 
 	cfg := Load()
 
-	if cfg.ExternalAPIURL != "https://api.example.com" || cfg.DBPort != 26258 || cfg.APIPort != "9999" || cfg.FetchInterval != 120 || cfg.LogLevel != "debug" {
+	if cfg.ExternalAPIURL != "https://api.example.com" || cfg.DBPort != 26258 || cfg.APIPort != "9999" || cfg.FetchInterval != 120 || cfg.ReliabilityRefreshInterval != 600 || cfg.WebhookDeliveryInterval != 30 || !cfg.WebhookAllowPrivateTargets || cfg.LogLevel != "debug" {
 		t.Fatalf("unexpected config loaded: %+v", cfg)
 	}
 }
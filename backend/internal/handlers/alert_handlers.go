@@ -0,0 +1,316 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type alertService interface {
+	ListRules(owner string) ([]models.AlertRule, error)
+	GetRule(owner string, id uint64) (*models.AlertRule, error)
+	CreateRule(owner, name string, conditions models.AlertConditions, enabled bool) (*models.AlertRule, error)
+	UpdateRule(owner string, id uint64, update services.AlertRuleUpdate) (*models.AlertRule, error)
+	DeleteRule(owner string, id uint64) error
+	ListAlerts(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error)
+}
+
+type AlertHandler struct {
+	alertService alertService
+}
+
+// createAlertRuleRequest: enabled es opcional y por defecto true
+type createAlertRuleRequest struct {
+	Name       string                 `json:"name" binding:"required"`
+	Conditions models.AlertConditions `json:"conditions"`
+	Enabled    *bool                  `json:"enabled"`
+}
+
+// updateAlertRuleRequest usa punteros para distinguir campos omitidos de campos vacíos
+type updateAlertRuleRequest struct {
+	Name       *string                 `json:"name"`
+	Conditions *models.AlertConditions `json:"conditions"`
+	Enabled    *bool                   `json:"enabled"`
+}
+
+// NewAlertHandler crea una nueva instancia del handler de alertas
+func NewAlertHandler(alertService *services.AlertEngine) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+func NewAlertHandlerWithServices(alertService alertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// ListAlerts maneja GET /api/v1/alerts
+// @Summary      List fired alerts
+// @Description  Get the alerts fired by the caller's rules, newest first, with the stock record that triggered each one.
+// @Description  Rules are evaluated against the records inserted or updated by each sync; a record fires a rule at most once
+// @Tags         alerts
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        rule_id    query   string  false  "Only alerts of this rule"
+// @Param        ticker     query   string  false  "Stock ticker symbol"
+// @Param        since      query   string  false  "Fired at or after (RFC3339 or YYYY-MM-DD)"
+// @Param        limit      query   int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset     query   int     false  "Offset for pagination (default: 0)"
+// @Success      200  {object}  map[string]interface{}  "Alerts"
+// @Failure      400  {object}  map[string]interface{}  "Invalid filters"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch alerts"
+// @Router       /api/v1/alerts [get]
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter := repositories.AlertFilter{
+		Owner:  middleware.Owner(c),
+		Ticker: strings.ToUpper(strings.TrimSpace(c.Query("ticker"))),
+	}
+	if raw := strings.TrimSpace(c.Query("rule_id")); raw != "" {
+		ruleID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid rule_id",
+			})
+			return
+		}
+		filter.RuleID = ruleID
+	}
+	since, err := queryTime(c, "since", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	filter.Since = since
+
+	alerts, total, err := h.alertService.ListAlerts(filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch alerts",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   alerts,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ListRules maneja GET /api/v1/alerts/rules
+// @Summary      List alert rules
+// @Description  List the caller's alert rules ordered by name
+// @Tags         alerts
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Success      200  {object}  map[string]interface{}  "Alert rules"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch alert rules"
+// @Router       /api/v1/alerts/rules [get]
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	rules, err := h.alertService.ListRules(middleware.Owner(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch alert rules",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  rules,
+		"total": len(rules),
+	})
+}
+
+// CreateRule maneja POST /api/v1/alerts/rules
+// @Summary      Create alert rule
+// @Description  Create a rule evaluated after each sync. All given conditions must match: tickers and/or watchlist_id, brokerages,
+// @Description  event_types, canonical rating_from/rating_to, and an inclusive min/max target change percentage. At least one condition is required
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                  false  "User identifier (used when no API key is sent)"
+// @Param        rule       body    createAlertRuleRequest  true   "Alert rule (enabled defaults to true)"
+// @Success      201  {object}  models.AlertRule
+// @Failure      400  {object}  map[string]interface{}  "Invalid alert rule"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to create alert rule"
+// @Router       /api/v1/alerts/rules [post]
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	var req createAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid alert rule: name is required and conditions must be an object",
+		})
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	rule, err := h.alertService.CreateRule(middleware.Owner(c), req.Name, req.Conditions, enabled)
+	if err != nil {
+		writeAlertRuleError(c, err, "Failed to create alert rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRule maneja GET /api/v1/alerts/rules/:id
+// @Summary      Get alert rule
+// @Description  Get one of the caller's alert rules
+// @Tags         alerts
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Alert rule ID"
+// @Success      200  {object}  models.AlertRule
+// @Failure      400  {object}  map[string]interface{}  "Invalid alert rule ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Alert rule not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch alert rule"
+// @Router       /api/v1/alerts/rules/{id} [get]
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alertService.GetRule(middleware.Owner(c), id)
+	if err != nil {
+		writeAlertRuleError(c, err, "Failed to fetch alert rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule maneja PUT /api/v1/alerts/rules/:id
+// @Summary      Update alert rule
+// @Description  Update name, conditions and/or enabled. Omitted fields are kept; a conditions object replaces all conditions
+// @Tags         alerts
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string                  true   "Alert rule ID"
+// @Param        rule       body    updateAlertRuleRequest  true   "Fields to change"
+// @Success      200  {object}  models.AlertRule
+// @Failure      400  {object}  map[string]interface{}  "Invalid alert rule"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Alert rule not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to update alert rule"
+// @Router       /api/v1/alerts/rules/{id} [put]
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+
+	var req updateAlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid alert rule: malformed JSON body",
+		})
+		return
+	}
+
+	rule, err := h.alertService.UpdateRule(middleware.Owner(c), id, services.AlertRuleUpdate{
+		Name:       req.Name,
+		Conditions: req.Conditions,
+		Enabled:    req.Enabled,
+	})
+	if err != nil {
+		writeAlertRuleError(c, err, "Failed to update alert rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule maneja DELETE /api/v1/alerts/rules/:id
+// @Summary      Delete alert rule
+// @Description  Delete one of the caller's alert rules. Alerts it already fired are kept
+// @Tags         alerts
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Alert rule ID"
+// @Success      204  "Alert rule deleted"
+// @Failure      400  {object}  map[string]interface{}  "Invalid alert rule ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Alert rule not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to delete alert rule"
+// @Router       /api/v1/alerts/rules/{id} [delete]
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.alertService.DeleteRule(middleware.Owner(c), id); err != nil {
+		writeAlertRuleError(c, err, "Failed to delete alert rule")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// alertRuleID lee el id de la ruta y responde 400 si no es válido
+func alertRuleID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid alert rule ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeAlertRuleError traduce los errores del motor de alertas a respuestas HTTP
+func writeAlertRuleError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Alert rule not found",
+		})
+	case errors.Is(err, services.ErrInvalidAlertRule):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fallback,
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAlertService struct {
+	createFn     func(owner, name string, conditions models.AlertConditions, enabled bool) (*models.AlertRule, error)
+	listAlertsFn func(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error)
+}
+
+func (f *fakeAlertService) ListRules(string) ([]models.AlertRule, error) {
+	return []models.AlertRule{}, nil
+}
+func (f *fakeAlertService) GetRule(string, uint64) (*models.AlertRule, error) {
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeAlertService) CreateRule(owner, name string, conditions models.AlertConditions, enabled bool) (*models.AlertRule, error) {
+	return f.createFn(owner, name, conditions, enabled)
+}
+func (f *fakeAlertService) UpdateRule(string, uint64, services.AlertRuleUpdate) (*models.AlertRule, error) {
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeAlertService) DeleteRule(string, uint64) error {
+	return repositories.ErrNotFound
+}
+func (f *fakeAlertService) ListAlerts(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error) {
+	return f.listAlertsFn(filter, limit, offset)
+}
+
+func newAlertRouter(service alertService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewAlertHandlerWithServices(service)
+	g := r.Group("/alerts", middleware.RequireOwner())
+	g.GET("", h.ListAlerts)
+	g.POST("/rules", h.CreateRule)
+	g.GET("/rules/:id", h.GetRule)
+	g.DELETE("/rules/:id", h.DeleteRule)
+	return r
+}
+
+func TestCreateAlertRule(t *testing.T) {
+	var gotConditions models.AlertConditions
+	var gotEnabled bool
+	r := newAlertRouter(&fakeAlertService{
+		createFn: func(_ string, name string, conditions models.AlertConditions, enabled bool) (*models.AlertRule, error) {
+			if name == "empty" {
+				return nil, fmt.Errorf("%w: at least one condition is required", services.ErrInvalidAlertRule)
+			}
+			gotConditions, gotEnabled = conditions, enabled
+			return &models.AlertRule{ID: 4, Name: name, Conditions: conditions, Enabled: enabled}, nil
+		},
+	})
+
+	body := `{"name":"Goldman raises","conditions":{"watchlist_id":"12","brokerages":["Goldman Sachs"],"min_target_change_pct":15}}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodPost, "/alerts/rules", body))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if !gotEnabled || gotConditions.WatchlistID != 12 || gotConditions.MinTargetChangePct == nil || *gotConditions.MinTargetChangePct != 15 {
+		t.Fatalf("unexpected rule input: enabled=%v conditions=%+v", gotEnabled, gotConditions)
+	}
+
+	for payload, want := range map[string]int{
+		`{"conditions":{}}`:                http.StatusBadRequest,
+		`{"name":"x","conditions":"oops"}`: http.StatusBadRequest,
+		`{"name":"empty","conditions":{}}`: http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, watchlistRequest(http.MethodPost, "/alerts/rules", payload))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", payload, rec.Code, want)
+		}
+	}
+}
+
+func TestListAlerts(t *testing.T) {
+	var gotFilter repositories.AlertFilter
+	r := newAlertRouter(&fakeAlertService{
+		listAlertsFn: func(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error) {
+			gotFilter = filter
+			return []models.Alert{{RuleID: 3, Ticker: "AAPL"}}, 1, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodGet, "/alerts?rule_id=3&ticker=aapl&since=2026-03-01", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotFilter.Owner != "user:alice" || gotFilter.RuleID != 3 || gotFilter.Ticker != "AAPL" || gotFilter.Since == nil {
+		t.Fatalf("unexpected filter: %+v", gotFilter)
+	}
+
+	for path, want := range map[string]int{
+		"/alerts?rule_id=abc": http.StatusBadRequest,
+		"/alerts?since=yday":  http.StatusBadRequest,
+		"/alerts/rules/7":     http.StatusNotFound,
+		"/alerts/rules/seven": http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, watchlistRequest(http.MethodGet, path, ""))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type analyticsService interface {
+	GetSentiment(filter repositories.StockFilter, bucket models.TimelineBucket) (*models.SentimentSeries, error)
+	GetRatingTransitions(filter repositories.StockFilter, mode models.RatingMode) (*models.RatingTransitionMatrix, error)
+	GetRatingDistribution(mode models.RatingMode) ([]models.RatingShare, int64, error)
+}
+
+type AnalyticsHandler struct {
+	analyticsService analyticsService
+}
+
+// NewAnalyticsHandler crea una nueva instancia del handler de analítica
+func NewAnalyticsHandler(analyticsService *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+func NewAnalyticsHandlerWithServices(analyticsService analyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetSentiment maneja GET /api/v1/analytics/sentiment
+// @Summary      Market sentiment
+// @Description  Get counts of upgrades, downgrades, target raises, target cuts and initiations per day, week (starting Monday) or month in UTC,
+// @Description  with a net sentiment index from -100 to 100. Empty buckets between the first and last one with data are included.
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        bucket     query  string  false  "Bucket: day, week or month (default: day)"
+// @Param        from       query  string  false  "Calls at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param        to         query  string  false  "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param        brokerage  query  string  false  "Brokerage names, comma separated or repeated (case-insensitive)"
+// @Param        tickers    query  string  false  "Tickers, comma separated or repeated"
+// @Success      200  {object}  models.SentimentSeries
+// @Failure      400  {object}  map[string]interface{}  "Invalid bucket or range"
+// @Failure      500  {object}  map[string]interface{}  "Failed to compute sentiment"
+// @Router       /api/v1/analytics/sentiment [get]
+func (h *AnalyticsHandler) GetSentiment(c *gin.Context) {
+	bucket, ok := models.ParseTimelineBucket(strings.ToLower(strings.TrimSpace(c.DefaultQuery("bucket", "day"))))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid bucket: must be day, week or month",
+		})
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid analytics filters: " + err.Error(),
+		})
+		return
+	}
+
+	series, err := h.analyticsService.GetSentiment(filter, bucket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compute sentiment",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, series)
+}
+
+// GetRatingTransitions maneja GET /api/v1/analytics/rating-transitions
+// @Summary      Rating transition matrix
+// @Description  Count how often each rating_from moved to each rating_to over a date range, as a matrix and as a list with
+// @Description  the share of each origin row. Canonical mode uses the normalized taxonomy; raw mode the rating text as received.
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        mode       query  string  false  "canonical or raw (default: canonical)"
+// @Param        from       query  string  false  "Calls at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param        to         query  string  false  "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param        brokerage  query  string  false  "Brokerage names, comma separated or repeated (case-insensitive)"
+// @Param        tickers    query  string  false  "Tickers, comma separated or repeated"
+// @Success      200  {object}  models.RatingTransitionMatrix
+// @Failure      400  {object}  map[string]interface{}  "Invalid mode or filters"
+// @Failure      500  {object}  map[string]interface{}  "Failed to compute rating transitions"
+// @Router       /api/v1/analytics/rating-transitions [get]
+func (h *AnalyticsHandler) GetRatingTransitions(c *gin.Context) {
+	mode, ok := models.ParseRatingMode(c.DefaultQuery("mode", string(models.RatingModeCanonical)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid mode: must be canonical or raw",
+		})
+		return
+	}
+
+	filter, err := parseAnalyticsFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid analytics filters: " + err.Error(),
+		})
+		return
+	}
+
+	matrix, err := h.analyticsService.GetRatingTransitions(filter, mode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compute rating transitions",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, matrix)
+}
+
+// GetRatingDistribution maneja GET /api/v1/analytics/rating-distribution
+// @Summary      Current rating distribution
+// @Description  Count tickers by the rating of their latest rated call
+// @Tags         analytics
+// @Accept       json
+// @Produce      json
+// @Param        mode  query  string  false  "canonical or raw (default: canonical)"
+// @Success      200  {object}  map[string]interface{}  "Rating distribution"
+// @Failure      400  {object}  map[string]interface{}  "Invalid mode"
+// @Failure      500  {object}  map[string]interface{}  "Failed to compute rating distribution"
+// @Router       /api/v1/analytics/rating-distribution [get]
+func (h *AnalyticsHandler) GetRatingDistribution(c *gin.Context) {
+	mode, ok := models.ParseRatingMode(c.DefaultQuery("mode", string(models.RatingModeCanonical)))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid mode: must be canonical or raw",
+		})
+		return
+	}
+
+	items, total, err := h.analyticsService.GetRatingDistribution(mode)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compute rating distribution",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mode":  mode,
+		"data":  items,
+		"total": total,
+	})
+}
+
+// parseAnalyticsFilter interpreta el rango y los filtros de brokerage/tickers comunes a las analíticas
+func parseAnalyticsFilter(c *gin.Context) (repositories.StockFilter, error) {
+	var filter repositories.StockFilter
+
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		return filter, err
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		return filter, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return filter, fmt.Errorf("invalid time range: from is after to")
+	}
+	filter.TimeFrom, filter.TimeTo = from, to
+
+	filter.Brokerages = queryList(c, "brokerage")
+	for _, ticker := range queryList(c, "tickers") {
+		filter.Tickers = append(filter.Tickers, strings.ToUpper(ticker))
+	}
+	return filter, nil
+}
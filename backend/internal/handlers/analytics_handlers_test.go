@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAnalyticsService struct {
+	getSentimentFn    func(filter repositories.StockFilter, bucket models.TimelineBucket) (*models.SentimentSeries, error)
+	getTransitionsFn  func(filter repositories.StockFilter, mode models.RatingMode) (*models.RatingTransitionMatrix, error)
+	getDistributionFn func(mode models.RatingMode) ([]models.RatingShare, int64, error)
+}
+
+func (f *fakeAnalyticsService) GetSentiment(filter repositories.StockFilter, bucket models.TimelineBucket) (*models.SentimentSeries, error) {
+	if f.getSentimentFn != nil {
+		return f.getSentimentFn(filter, bucket)
+	}
+	return &models.SentimentSeries{Bucket: bucket, Points: []models.SentimentPoint{}}, nil
+}
+
+func (f *fakeAnalyticsService) GetRatingTransitions(filter repositories.StockFilter, mode models.RatingMode) (*models.RatingTransitionMatrix, error) {
+	if f.getTransitionsFn != nil {
+		return f.getTransitionsFn(filter, mode)
+	}
+	return &models.RatingTransitionMatrix{Mode: mode}, nil
+}
+func (f *fakeAnalyticsService) GetRatingDistribution(mode models.RatingMode) ([]models.RatingShare, int64, error) {
+	if f.getDistributionFn != nil {
+		return f.getDistributionFn(mode)
+	}
+	return []models.RatingShare{}, 0, nil
+}
+
+func TestGetSentiment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotFilter repositories.StockFilter
+	var gotBucket models.TimelineBucket
+	h := NewAnalyticsHandlerWithServices(&fakeAnalyticsService{
+		getSentimentFn: func(filter repositories.StockFilter, bucket models.TimelineBucket) (*models.SentimentSeries, error) {
+			gotFilter, gotBucket = filter, bucket
+			return &models.SentimentSeries{Bucket: bucket, Points: []models.SentimentPoint{}}, nil
+		},
+	})
+	r.GET("/sentiment", h.GetSentiment)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sentiment?bucket=week&from=2026-01-01&brokerage=Goldman%20Sachs&tickers=aapl,msft", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotBucket != models.TimelineBucketWeek || gotFilter.TimeFrom == nil || gotFilter.TimeTo != nil {
+		t.Fatalf("bucket=%q from=%v to=%v", gotBucket, gotFilter.TimeFrom, gotFilter.TimeTo)
+	}
+	if len(gotFilter.Brokerages) != 1 || len(gotFilter.Tickers) != 2 || gotFilter.Tickers[0] != "AAPL" {
+		t.Fatalf("unexpected filter: %+v", gotFilter)
+	}
+
+	errHandler := NewAnalyticsHandlerWithServices(&fakeAnalyticsService{
+		getSentimentFn: func(repositories.StockFilter, models.TimelineBucket) (*models.SentimentSeries, error) {
+			return nil, errors.New("db")
+		},
+	})
+	r.GET("/sentiment-err", errHandler.GetSentiment)
+
+	for path, want := range map[string]int{
+		"/sentiment":                               http.StatusOK,
+		"/sentiment?bucket=hour":                   http.StatusBadRequest,
+		"/sentiment?to=soon":                       http.StatusBadRequest,
+		"/sentiment?from=2026-02-01&to=2026-01-01": http.StatusBadRequest,
+		"/sentiment-err":                           http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestGetRatingTransitions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotMode models.RatingMode
+	var gotFilter repositories.StockFilter
+	h := NewAnalyticsHandlerWithServices(&fakeAnalyticsService{
+		getTransitionsFn: func(filter repositories.StockFilter, mode models.RatingMode) (*models.RatingTransitionMatrix, error) {
+			if len(filter.Tickers) > 0 && filter.Tickers[0] == "FAIL" {
+				return nil, errors.New("db")
+			}
+			gotFilter, gotMode = filter, mode
+			return &models.RatingTransitionMatrix{Mode: mode}, nil
+		},
+	})
+	r.GET("/transitions", h.GetRatingTransitions)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transitions?from=2026-01-01&to=2026-03-31", nil))
+	if w.Code != http.StatusOK || gotMode != models.RatingModeCanonical || gotFilter.TimeFrom == nil || gotFilter.TimeTo == nil {
+		t.Fatalf("status=%d mode=%q filter=%+v", w.Code, gotMode, gotFilter)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/transitions?mode=RAW", nil))
+	if w.Code != http.StatusOK || gotMode != models.RatingModeRaw {
+		t.Fatalf("status=%d mode=%q", w.Code, gotMode)
+	}
+
+	for path, want := range map[string]int{
+		"/transitions?mode=normalized":               http.StatusBadRequest,
+		"/transitions?from=2026-02-01&to=2026-01-01": http.StatusBadRequest,
+		"/transitions?tickers=fail":                  http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestGetRatingDistribution(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	h := NewAnalyticsHandlerWithServices(&fakeAnalyticsService{
+		getDistributionFn: func(mode models.RatingMode) ([]models.RatingShare, int64, error) {
+			if mode == models.RatingModeRaw {
+				return nil, 0, errors.New("db")
+			}
+			return []models.RatingShare{{Rating: "buy", Tickers: 3, Share: 0.75}, {Rating: "hold", Tickers: 1, Share: 0.25}}, 4, nil
+		},
+	})
+	r.GET("/distribution", h.GetRatingDistribution)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/distribution", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total":4`) || !strings.Contains(w.Body.String(), `"mode":"canonical"`) {
+		t.Fatalf("status=%d body=%s", w.Code, w.Body.String())
+	}
+
+	for path, want := range map[string]int{
+		"/distribution?mode=other": http.StatusBadRequest,
+		"/distribution?mode=raw":   http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
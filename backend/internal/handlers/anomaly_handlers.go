@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type anomalyService interface {
+	ListAnomalies(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error)
+}
+
+type AnomalyHandler struct {
+	anomalyService anomalyService
+}
+
+// NewAnomalyHandler crea una nueva instancia del handler de anomalías
+func NewAnomalyHandler(anomalyService *services.AnomalyDetector) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+func NewAnomalyHandlerWithServices(anomalyService anomalyService) *AnomalyHandler {
+	return &AnomalyHandler{
+		anomalyService: anomalyService,
+	}
+}
+
+// ListAnomalies maneja GET /api/v1/anomalies
+// @Summary      List anomalies
+// @Description  Get unusual analyst activity detected after each sync, newest first: activity spikes (many firms acting on a ticker
+// @Description  within days versus its baseline) and target outliers (a firm's target far from the median of the others),
+// @Description  with severity and the supporting stock records
+// @Tags         anomalies
+// @Accept       json
+// @Produce      json
+// @Param        kind      query  string  false  "activity_spike or target_outlier"
+// @Param        severity  query  string  false  "low, medium or high"
+// @Param        ticker    query  string  false  "Stock ticker symbol"
+// @Param        limit     query  int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset    query  int     false  "Offset for pagination (default: 0)"
+// @Success      200  {object}  map[string]interface{}  "Anomalies"
+// @Failure      400  {object}  map[string]interface{}  "Invalid kind or severity"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch anomalies"
+// @Router       /api/v1/anomalies [get]
+func (h *AnomalyHandler) ListAnomalies(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter := repositories.AnomalyFilter{Ticker: c.Query("ticker")}
+	if raw := strings.ToLower(strings.TrimSpace(c.Query("kind"))); raw != "" {
+		kind, ok := models.ParseAnomalyKind(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid kind: must be activity_spike or target_outlier",
+			})
+			return
+		}
+		filter.Kind = kind
+	}
+	if raw := strings.ToLower(strings.TrimSpace(c.Query("severity"))); raw != "" {
+		severity, ok := models.ParseAnomalySeverity(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid severity: must be low, medium or high",
+			})
+			return
+		}
+		filter.Severity = severity
+	}
+
+	anomalies, total, err := h.anomalyService.ListAnomalies(filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch anomalies",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   anomalies,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeAnomalyService struct {
+	listAnomaliesFn func(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error)
+}
+
+func (f *fakeAnomalyService) ListAnomalies(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+	if f.listAnomaliesFn != nil {
+		return f.listAnomaliesFn(filter, limit, offset)
+	}
+	return []models.Anomaly{}, 0, nil
+}
+
+func TestListAnomalies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotFilter repositories.AnomalyFilter
+	var gotLimit, gotOffset int
+	h := NewAnomalyHandlerWithServices(&fakeAnomalyService{
+		listAnomaliesFn: func(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+			if filter.Ticker == "FAIL" {
+				return nil, 0, errors.New("db")
+			}
+			gotFilter, gotLimit, gotOffset = filter, limit, offset
+			return []models.Anomaly{{Kind: models.AnomalyTargetOutlier, Ticker: "AAPL"}}, 1, nil
+		},
+	})
+	r.GET("/anomalies", h.ListAnomalies)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/anomalies?kind=Target_Outlier&severity=high&ticker=aapl&limit=999&offset=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotFilter.Kind != models.AnomalyTargetOutlier || gotFilter.Severity != models.AnomalySeverityHigh || gotFilter.Ticker != "aapl" {
+		t.Fatalf("unexpected filter: %+v", gotFilter)
+	}
+	if gotLimit != 200 || gotOffset != 5 {
+		t.Fatalf("limit/offset = %d/%d", gotLimit, gotOffset)
+	}
+
+	for path, want := range map[string]int{
+		"/anomalies?kind=burst":      http.StatusBadRequest,
+		"/anomalies?severity=severe": http.StatusBadRequest,
+		"/anomalies?ticker=FAIL":     http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
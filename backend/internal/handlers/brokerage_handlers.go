@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type reliabilityService interface {
+	GetReliability() ([]models.BrokerageReliability, error)
+}
+
+type brokerageDirectory interface {
+	ListBrokerages(sortBy, order string) ([]models.BrokerageSummary, error)
+	GetBrokerageCalls(name string, filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error)
+}
+
+type BrokerageHandler struct {
+	reliabilityService reliabilityService
+	directory          brokerageDirectory
+}
+
+// NewBrokerageHandler crea una nueva instancia del handler de brokerages
+func NewBrokerageHandler(reliabilityService *services.ReliabilityService, directory *services.BrokerageDirectory) *BrokerageHandler {
+	return &BrokerageHandler{
+		reliabilityService: reliabilityService,
+		directory:          directory,
+	}
+}
+
+func NewBrokerageHandlerWithServices(reliabilityService reliabilityService, directory brokerageDirectory) *BrokerageHandler {
+	return &BrokerageHandler{
+		reliabilityService: reliabilityService,
+		directory:          directory,
+	}
+}
+
+// ListBrokerages maneja GET /api/v1/brokerages
+// @Summary      Brokerage directory
+// @Description  List every brokerage with its total calls, tickers covered, first and last activity, counts per event type and upgrade/downgrade ratio
+// @Tags         brokerages
+// @Accept       json
+// @Produce      json
+// @Param        sort   query  string  false  "Sort by total_calls, tickers_covered, last_activity or brokerage (default: total_calls)"
+// @Param        order  query  string  false  "Sort order: asc or desc (default: desc)"
+// @Success      200  {object}  map[string]interface{}  "Brokerage directory"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch brokerages"
+// @Router       /api/v1/brokerages [get]
+func (h *BrokerageHandler) ListBrokerages(c *gin.Context) {
+	items, err := h.directory.ListBrokerages(c.DefaultQuery("sort", "total_calls"), c.DefaultQuery("order", "desc"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch brokerages",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": len(items),
+	})
+}
+
+// GetBrokerageCalls maneja GET /api/v1/brokerages/:name/calls
+// @Summary      Brokerage call history
+// @Description  Get the paginated calls of one brokerage (case-insensitive), newest first. Accepts the same filters as GET /api/v1/stocks except brokerage.
+// @Tags         brokerages
+// @Accept       json
+// @Produce      json
+// @Param        name    path   string  true   "Brokerage name"
+// @Param        limit   query  int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset  query  int     false  "Offset for pagination (default: 0)"
+// @Param        from    query  string  false  "Calls at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param        to      query  string  false  "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param        tickers query  string  false  "Tickers, comma separated or repeated"
+// @Param        event_type query string false  "Event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)"
+// @Param        filter  query  string  false  "Filter expression, same syntax as GET /api/v1/stocks"
+// @Success      200  {object}  map[string]interface{}  "Brokerage calls"
+// @Failure      400  {object}  map[string]interface{}  "Invalid stock filters"
+// @Failure      404  {object}  map[string]interface{}  "Brokerage not found"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /api/v1/brokerages/{name}/calls [get]
+func (h *BrokerageHandler) GetBrokerageCalls(c *gin.Context) {
+	name := strings.TrimSpace(c.Param("name"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter, err := parseStockListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid stock filters: " + err.Error(),
+		})
+		return
+	}
+	filter.Brokerages = []string{name}
+
+	stocks, total, err := h.directory.GetBrokerageCalls(name, filter, limit, offset)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Brokerage not found: " + name,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch brokerage calls",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"brokerage": name,
+		"filters":   stockFilterPayload(c, filter),
+		"data":      stocks,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetReliability maneja GET /api/v1/brokerages/reliability
+// @Summary      Brokerage reliability
+// @Description  Get the credibility score learned from history for each brokerage and the multiplier applied in scoring
+// @Tags         brokerages
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Reliability table"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch brokerage reliability"
+// @Router       /api/v1/brokerages/reliability [get]
+func (h *BrokerageHandler) GetReliability(c *gin.Context) {
+	items, err := h.reliabilityService.GetReliability()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch brokerage reliability",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": len(items),
+	})
+}
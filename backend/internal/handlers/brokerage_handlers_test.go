@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeReliabilityService struct {
+	getReliabilityFn func() ([]models.BrokerageReliability, error)
+}
+
+func (f *fakeReliabilityService) GetReliability() ([]models.BrokerageReliability, error) {
+	if f.getReliabilityFn != nil {
+		return f.getReliabilityFn()
+	}
+	return []models.BrokerageReliability{}, nil
+}
+
+type fakeBrokerageDirectory struct {
+	listBrokeragesFn    func(sortBy, order string) ([]models.BrokerageSummary, error)
+	getBrokerageCallsFn func(name string, filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error)
+}
+
+func (f *fakeBrokerageDirectory) ListBrokerages(sortBy, order string) ([]models.BrokerageSummary, error) {
+	if f.listBrokeragesFn != nil {
+		return f.listBrokeragesFn(sortBy, order)
+	}
+	return []models.BrokerageSummary{}, nil
+}
+func (f *fakeBrokerageDirectory) GetBrokerageCalls(name string, filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+	if f.getBrokerageCallsFn != nil {
+		return f.getBrokerageCallsFn(name, filter, limit, offset)
+	}
+	return []models.Stock{}, 0, nil
+}
+
+func TestGetReliability_ErrorAndSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	errHandler := NewBrokerageHandlerWithServices(&fakeReliabilityService{
+		getReliabilityFn: func() ([]models.BrokerageReliability, error) { return nil, errors.New("x") },
+	}, &fakeBrokerageDirectory{})
+	r.GET("/rel-err", errHandler.GetReliability)
+
+	wErr := httptest.NewRecorder()
+	r.ServeHTTP(wErr, httptest.NewRequest(http.MethodGet, "/rel-err", nil))
+	if wErr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", wErr.Code, http.StatusInternalServerError)
+	}
+
+	okHandler := NewBrokerageHandlerWithServices(&fakeReliabilityService{
+		getReliabilityFn: func() ([]models.BrokerageReliability, error) {
+			return []models.BrokerageReliability{{Brokerage: "Goldman Sachs", Score: 71, Multiplier: 1.13}}, nil
+		},
+	}, &fakeBrokerageDirectory{})
+	r.GET("/rel-ok", okHandler.GetReliability)
+
+	wOK := httptest.NewRecorder()
+	r.ServeHTTP(wOK, httptest.NewRequest(http.MethodGet, "/rel-ok", nil))
+	if wOK.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", wOK.Code, http.StatusOK)
+	}
+}
+
+func TestListBrokerages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotSort, gotOrder string
+	h := NewBrokerageHandlerWithServices(&fakeReliabilityService{}, &fakeBrokerageDirectory{
+		listBrokeragesFn: func(sortBy, order string) ([]models.BrokerageSummary, error) {
+			gotSort, gotOrder = sortBy, order
+			ratio := 2.0
+			return []models.BrokerageSummary{{Brokerage: "Goldman Sachs", TotalCalls: 12, Upgrades: 4, Downgrades: 2, UpgradeDowngradeRatio: &ratio}}, nil
+		},
+	})
+	r.GET("/brokerages", h.ListBrokerages)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/brokerages?sort=last_activity", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if gotSort != "last_activity" || gotOrder != "desc" {
+		t.Fatalf("sort/order = %q/%q", gotSort, gotOrder)
+	}
+
+	var body struct {
+		Data  []map[string]any `json:"data"`
+		Total int              `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if body.Total != 1 || body.Data[0]["upgrade_downgrade_ratio"] != 2.0 {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	errHandler := NewBrokerageHandlerWithServices(&fakeReliabilityService{}, &fakeBrokerageDirectory{
+		listBrokeragesFn: func(string, string) ([]models.BrokerageSummary, error) { return nil, errors.New("db") },
+	})
+	r.GET("/brokerages-err", errHandler.ListBrokerages)
+
+	wErr := httptest.NewRecorder()
+	r.ServeHTTP(wErr, httptest.NewRequest(http.MethodGet, "/brokerages-err", nil))
+	if wErr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", wErr.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestGetBrokerageCalls(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotName string
+	var gotFilter repositories.StockFilter
+	var gotLimit, gotOffset int
+	h := NewBrokerageHandlerWithServices(&fakeReliabilityService{}, &fakeBrokerageDirectory{
+		getBrokerageCallsFn: func(name string, filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+			if name == "Nobody" {
+				return nil, 0, repositories.ErrNotFound
+			}
+			if name == "Broken" {
+				return nil, 0, errors.New("db")
+			}
+			gotName, gotFilter, gotLimit, gotOffset = name, filter, limit, offset
+			return []models.Stock{{Ticker: "AAPL", Brokerage: "Goldman Sachs"}}, 31, nil
+		},
+	})
+	r.GET("/brokerages/:name/calls", h.GetBrokerageCalls)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/brokerages/Goldman%20Sachs/calls?limit=500&offset=10&brokerage=Other&event_type=upgrade", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotName != "Goldman Sachs" || gotLimit != 200 || gotOffset != 10 {
+		t.Fatalf("name/limit/offset = %q/%d/%d", gotName, gotLimit, gotOffset)
+	}
+	if len(gotFilter.Brokerages) != 1 || gotFilter.Brokerages[0] != "Goldman Sachs" {
+		t.Fatalf("brokerage filter should be the path name, got %v", gotFilter.Brokerages)
+	}
+	if gotFilter.EventType != models.ActionEventUpgrade {
+		t.Fatalf("event_type filter not forwarded: %q", gotFilter.EventType)
+	}
+
+	for path, want := range map[string]int{
+		"/brokerages/Nobody/calls":                http.StatusNotFound,
+		"/brokerages/Broken/calls":                http.StatusInternalServerError,
+		"/brokerages/Goldman/calls?from=tomorrow": http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
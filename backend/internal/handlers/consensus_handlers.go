@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type consensusService interface {
+	GetConsensus(ticker string, windowDays int) (*models.TickerConsensus, error)
+}
+
+type ConsensusHandler struct {
+	consensusService consensusService
+}
+
+// NewConsensusHandler crea una nueva instancia del handler de consenso
+func NewConsensusHandler(consensusService *services.ConsensusService) *ConsensusHandler {
+	return &ConsensusHandler{
+		consensusService: consensusService,
+	}
+}
+
+func NewConsensusHandlerWithServices(consensusService consensusService) *ConsensusHandler {
+	return &ConsensusHandler{
+		consensusService: consensusService,
+	}
+}
+
+// GetConsensus maneja GET /api/v1/stocks/ticker/:ticker/consensus
+// @Summary      Consensus price target
+// @Description  Get mean/median/high/low target and rating distribution across brokerages using the latest call of each firm within the window
+// @Tags         stocks
+// @Accept       json
+// @Produce      json
+// @Param        ticker       path   string  true   "Stock ticker symbol"
+// @Param        window_days  query  int     false  "Lookback window in days (default: 90, max: 365)"
+// @Success      200  {object}  models.TickerConsensus
+// @Failure      400  {object}  map[string]interface{}  "Invalid ticker"
+// @Failure      404  {object}  map[string]interface{}  "No stocks found for ticker"
+// @Failure      500  {object}  map[string]interface{}  "Failed to compute consensus"
+// @Router       /api/v1/stocks/ticker/{ticker}/consensus [get]
+func (h *ConsensusHandler) GetConsensus(c *gin.Context) {
+	ticker := c.Param("ticker")
+	if ticker == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Ticker is required",
+		})
+		return
+	}
+
+	windowDays, _ := strconv.Atoi(c.DefaultQuery("window_days", "90"))
+	if windowDays > 365 {
+		windowDays = 365
+	}
+	if windowDays < 1 {
+		windowDays = 90
+	}
+
+	consensus, err := h.consensusService.GetConsensus(ticker, windowDays)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No stocks found for ticker " + ticker,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to compute consensus",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, consensus)
+}
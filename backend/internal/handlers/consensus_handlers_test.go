@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeConsensusService struct {
+	getConsensusFn func(ticker string, windowDays int) (*models.TickerConsensus, error)
+}
+
+func (f *fakeConsensusService) GetConsensus(ticker string, windowDays int) (*models.TickerConsensus, error) {
+	if f.getConsensusFn != nil {
+		return f.getConsensusFn(ticker, windowDays)
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func TestGetConsensus_ClampsWindowAndNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	h := NewConsensusHandlerWithServices(&fakeConsensusService{
+		getConsensusFn: func(ticker string, windowDays int) (*models.TickerConsensus, error) {
+			if windowDays != 365 {
+				t.Fatalf("expected clamped window 365, got %d", windowDays)
+			}
+			return &models.TickerConsensus{Ticker: ticker, CoveringFirms: 3}, nil
+		},
+	})
+	r.GET("/ok/:ticker/consensus", h.GetConsensus)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok/AAPL/consensus?window_days=9999", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	missing := NewConsensusHandlerWithServices(&fakeConsensusService{})
+	r.GET("/missing/:ticker/consensus", missing.GetConsensus)
+
+	wMissing := httptest.NewRecorder()
+	r.ServeHTTP(wMissing, httptest.NewRequest(http.MethodGet, "/missing/ZZZ/consensus", nil))
+	if wMissing.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", wMissing.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,317 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// Límites para que una expresión no genere consultas desproporcionadas
+const (
+	maxExpressionLength      = 1000
+	maxExpressionComparisons = 30
+	maxExpressionDepth       = 8
+	maxExpressionInValues    = 100
+)
+
+type expressionFieldKind int
+
+const (
+	expressionString expressionFieldKind = iota
+	expressionNumber
+	expressionID
+	expressionTime
+)
+
+// expressionFieldKinds indica cómo tipar los literales de cada campo de allowedSortQueryFields;
+// los campos que no aparecen aquí son texto.
+var expressionFieldKinds = map[string]expressionFieldKind{
+	"id":                expressionID,
+	"time":              expressionTime,
+	"created_at":        expressionTime,
+	"updated_at":        expressionTime,
+	"target_from_value": expressionNumber,
+	"target_to_value":   expressionNumber,
+	"target_change_pct": expressionNumber,
+}
+
+var expressionOperators = map[string]repositories.ExpressionOp{
+	"eq":       repositories.ExprEq,
+	"ne":       repositories.ExprNe,
+	"in":       repositories.ExprIn,
+	"gt":       repositories.ExprGt,
+	"lt":       repositories.ExprLt,
+	"contains": repositories.ExprContains,
+}
+
+type expressionTokenKind int
+
+const (
+	tokenWord expressionTokenKind = iota
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenComma
+	tokenEOF
+)
+
+type expressionToken struct {
+	kind expressionTokenKind
+	text string
+	pos  int
+}
+
+// parseFilterExpression interpreta el parámetro filter de los listados de stocks.
+// Gramática:
+//
+//	expr       := and_expr ("or" and_expr)*
+//	and_expr   := factor ("and" factor)*
+//	factor     := "(" expr ")" | field op value | field "in" "(" value ("," value)* ")"
+//	op         := eq | ne | gt | lt | contains
+//
+// Los valores pueden ir entre comillas simples o dobles; las palabras clave no distinguen mayúsculas.
+func parseFilterExpression(raw string) (*repositories.StockExpression, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	if len(raw) > maxExpressionLength {
+		return nil, fmt.Errorf("invalid filter expression: longer than %d characters", maxExpressionLength)
+	}
+
+	tokens, err := tokenizeExpression(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+	expr, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, "unexpected %q", tok.text)
+	}
+	return expr, nil
+}
+
+func tokenizeExpression(raw string) ([]expressionToken, error) {
+	var tokens []expressionToken
+	runes := []rune(raw)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			i++
+		case r == '(':
+			tokens = append(tokens, expressionToken{kind: tokenLParen, text: "(", pos: i + 1})
+			i++
+		case r == ')':
+			tokens = append(tokens, expressionToken{kind: tokenRParen, text: ")", pos: i + 1})
+			i++
+		case r == ',':
+			tokens = append(tokens, expressionToken{kind: tokenComma, text: ",", pos: i + 1})
+			i++
+		case r == '\'' || r == '"':
+			start := i
+			var value strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					value.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == r {
+					closed = true
+					i++
+					break
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if !closed {
+				return nil, fmt.Errorf("invalid filter expression at position %d: unterminated string", start+1)
+			}
+			tokens = append(tokens, expressionToken{kind: tokenString, text: value.String(), pos: start + 1})
+		default:
+			start := i
+			for i < len(runes) && !strings.ContainsRune(" \t\n\r(),'\"", runes[i]) {
+				i++
+			}
+			tokens = append(tokens, expressionToken{kind: tokenWord, text: string(runes[start:i]), pos: start + 1})
+		}
+	}
+
+	tokens = append(tokens, expressionToken{kind: tokenEOF, text: "end of expression", pos: len(runes) + 1})
+	return tokens, nil
+}
+
+type expressionParser struct {
+	tokens      []expressionToken
+	index       int
+	comparisons int
+}
+
+func (p *expressionParser) peek() expressionToken {
+	return p.tokens[p.index]
+}
+
+func (p *expressionParser) next() expressionToken {
+	tok := p.tokens[p.index]
+	if tok.kind != tokenEOF {
+		p.index++
+	}
+	return tok
+}
+
+func (p *expressionParser) errorAt(tok expressionToken, format string, args ...any) error {
+	return fmt.Errorf("invalid filter expression at position %d: %s", tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *expressionParser) keyword(word string) bool {
+	tok := p.peek()
+	return tok.kind == tokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (p *expressionParser) parseOr(depth int) (*repositories.StockExpression, error) {
+	return p.parseChain(depth, "or", repositories.ExprOr, p.parseAnd)
+}
+
+func (p *expressionParser) parseAnd(depth int) (*repositories.StockExpression, error) {
+	return p.parseChain(depth, "and", repositories.ExprAnd, p.parseFactor)
+}
+
+// parseChain agrupa operandos unidos por la misma palabra clave en un solo nodo lógico
+func (p *expressionParser) parseChain(depth int, word string, op repositories.ExpressionOp, operand func(int) (*repositories.StockExpression, error)) (*repositories.StockExpression, error) {
+	first, err := operand(depth)
+	if err != nil {
+		return nil, err
+	}
+	children := []*repositories.StockExpression{first}
+	for p.keyword(word) {
+		p.next()
+		child, err := operand(depth)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &repositories.StockExpression{Op: op, Children: children}, nil
+}
+
+func (p *expressionParser) parseFactor(depth int) (*repositories.StockExpression, error) {
+	tok := p.next()
+	if tok.kind == tokenLParen {
+		if depth >= maxExpressionDepth {
+			return nil, p.errorAt(tok, "nested deeper than %d levels", maxExpressionDepth)
+		}
+		expr, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, p.errorAt(closing, "expected ) but found %q", closing.text)
+		}
+		return expr, nil
+	}
+	if tok.kind != tokenWord {
+		return nil, p.errorAt(tok, "expected a field name but found %q", tok.text)
+	}
+	return p.parseComparison(tok)
+}
+
+func (p *expressionParser) parseComparison(fieldTok expressionToken) (*repositories.StockExpression, error) {
+	field := strings.ToLower(fieldTok.text)
+	if _, ok := allowedSortQueryFields[field]; !ok {
+		return nil, p.errorAt(fieldTok, "unknown field %q", fieldTok.text)
+	}
+
+	opTok := p.next()
+	op, ok := expressionOperators[strings.ToLower(opTok.text)]
+	if opTok.kind != tokenWord || !ok {
+		return nil, p.errorAt(opTok, "expected an operator (eq, ne, in, gt, lt, contains) but found %q", opTok.text)
+	}
+	kind := expressionFieldKinds[field]
+	if op == repositories.ExprContains && kind != expressionString {
+		return nil, p.errorAt(opTok, "contains only applies to text fields, not %s", field)
+	}
+
+	p.comparisons++
+	if p.comparisons > maxExpressionComparisons {
+		return nil, p.errorAt(fieldTok, "more than %d comparisons", maxExpressionComparisons)
+	}
+
+	expr := &repositories.StockExpression{Op: op, Field: field}
+	if op != repositories.ExprIn {
+		value, err := p.parseValue(field, kind)
+		if err != nil {
+			return nil, err
+		}
+		expr.Values = []any{value}
+		return expr, nil
+	}
+
+	if open := p.next(); open.kind != tokenLParen {
+		return nil, p.errorAt(open, "expected ( after in but found %q", open.text)
+	}
+	for {
+		value, err := p.parseValue(field, kind)
+		if err != nil {
+			return nil, err
+		}
+		expr.Values = append(expr.Values, value)
+		if len(expr.Values) > maxExpressionInValues {
+			return nil, p.errorAt(fieldTok, "in accepts at most %d values", maxExpressionInValues)
+		}
+
+		sep := p.next()
+		if sep.kind == tokenRParen {
+			return expr, nil
+		}
+		if sep.kind != tokenComma {
+			return nil, p.errorAt(sep, "expected , or ) but found %q", sep.text)
+		}
+	}
+}
+
+func (p *expressionParser) parseValue(field string, kind expressionFieldKind) (any, error) {
+	tok := p.next()
+	if tok.kind != tokenWord && tok.kind != tokenString {
+		return nil, p.errorAt(tok, "expected a value for %s but found %q", field, tok.text)
+	}
+
+	switch kind {
+	case expressionNumber:
+		value, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return nil, p.errorAt(tok, "%s expects a finite number", field)
+		}
+		return value, nil
+	case expressionID:
+		value, err := strconv.ParseUint(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorAt(tok, "%s expects a positive integer", field)
+		}
+		return value, nil
+	case expressionTime:
+		if value, err := time.Parse(time.RFC3339, tok.text); err == nil {
+			return value, nil
+		}
+		value, err := time.Parse(dateOnlyLayout, tok.text)
+		if err != nil {
+			return nil, p.errorAt(tok, "%s expects RFC3339 or YYYY-MM-DD", field)
+		}
+		return value, nil
+	default:
+		return tok.text, nil
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func TestParseFilterExpression_Valid(t *testing.T) {
+	expr, err := parseFilterExpression(`ticker IN ('AAPL', "MSFT") and (target_change_pct gt 10 OR action contains 'raised') and time gt 2026-02-01`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := &repositories.StockExpression{
+		Op: repositories.ExprAnd,
+		Children: []*repositories.StockExpression{
+			{Op: repositories.ExprIn, Field: "ticker", Values: []any{"AAPL", "MSFT"}},
+			{
+				Op: repositories.ExprOr,
+				Children: []*repositories.StockExpression{
+					{Op: repositories.ExprGt, Field: "target_change_pct", Values: []any{10.0}},
+					{Op: repositories.ExprContains, Field: "action", Values: []any{"raised"}},
+				},
+			},
+			{Op: repositories.ExprGt, Field: "time", Values: []any{time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}},
+		},
+	}
+	if !reflect.DeepEqual(expr, want) {
+		t.Fatalf("unexpected tree: %+v", expr)
+	}
+}
+
+func TestParseFilterExpression_SingleComparisonAndEscapes(t *testing.T) {
+	expr, err := parseFilterExpression(`company eq 'O\'Reilly Automotive'`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expr.Op != repositories.ExprEq || expr.Field != "company" || expr.Values[0] != "O'Reilly Automotive" {
+		t.Fatalf("unexpected expression: %+v", expr)
+	}
+
+	if expr, err := parseFilterExpression("   "); expr != nil || err != nil {
+		t.Fatalf("blank expression should be ignored, got %+v %v", expr, err)
+	}
+}
+
+func TestParseFilterExpression_Errors(t *testing.T) {
+	cases := map[string]string{
+		`password eq 'x'`:                  "unknown field",
+		`ticker like 'A%'`:                 "expected an operator",
+		`ticker eq`:                        "expected a value",
+		`ticker eq 'AAPL`:                  "unterminated string",
+		`(ticker eq 'AAPL'`:                "expected )",
+		`ticker eq 'AAPL')`:                "unexpected",
+		`ticker eq 'AAPL' and`:             "expected a field name",
+		`target_to_value gt cheap`:         "expects a finite number",
+		`target_to_value gt NaN`:           "expects a finite number",
+		`id eq -1`:                         "positive integer",
+		`time lt yesterday`:                "RFC3339",
+		`target_to_value contains '1'`:     "only applies to text",
+		`ticker in 'AAPL'`:                 "expected ( after in",
+		`ticker in ('AAPL' 'MSFT')`:        "expected , or )",
+		`ticker eq 'A'; DROP TABLE stocks`: "unexpected",
+	}
+	for input, fragment := range cases {
+		_, err := parseFilterExpression(input)
+		if err == nil || !strings.Contains(err.Error(), fragment) {
+			t.Errorf("%s: expected error containing %q, got %v", input, fragment, err)
+		}
+	}
+
+	deep := strings.Repeat("(", maxExpressionDepth+1) + "id eq 1" + strings.Repeat(")", maxExpressionDepth+1)
+	if _, err := parseFilterExpression(deep); err == nil || !strings.Contains(err.Error(), "nested deeper") {
+		t.Errorf("expected depth error, got %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type ratingNormalizer interface {
+	ListMappings() ([]models.RatingMapping, error)
+	ListUnmapped() ([]models.UnmappedRating, error)
+	AddMapping(raw string, canonical models.CanonicalRating, score *float64) (*models.RatingMapping, int64, error)
+}
+
+type RatingHandler struct {
+	normalizer ratingNormalizer
+}
+
+type ratingMappingRequest struct {
+	RawRating string   `json:"raw_rating" binding:"required"`
+	Canonical string   `json:"canonical" binding:"required"`
+	Score     *float64 `json:"score"`
+}
+
+// NewRatingHandler crea una nueva instancia del handler de administración de ratings
+func NewRatingHandler(normalizer *services.RatingNormalizer) *RatingHandler {
+	return &RatingHandler{
+		normalizer: normalizer,
+	}
+}
+
+func NewRatingHandlerWithServices(normalizer ratingNormalizer) *RatingHandler {
+	return &RatingHandler{
+		normalizer: normalizer,
+	}
+}
+
+// GetUnmapped maneja GET /api/v1/admin/ratings/unmapped
+// @Summary      Unmapped raw ratings
+// @Description  List raw rating strings that the normalizer could not map to the canonical taxonomy, with how many rating columns use each one
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Unmapped raw ratings"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch unmapped ratings"
+// @Router       /api/v1/admin/ratings/unmapped [get]
+func (h *RatingHandler) GetUnmapped(c *gin.Context) {
+	items, err := h.normalizer.ListUnmapped()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch unmapped ratings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  items,
+		"total": len(items),
+	})
+}
+
+// GetMappings maneja GET /api/v1/admin/ratings/mappings
+// @Summary      Rating mappings
+// @Description  List the administered raw rating to canonical rating mappings (built-in rules are not included)
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  map[string]interface{}  "Rating mappings"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch rating mappings"
+// @Router       /api/v1/admin/ratings/mappings [get]
+func (h *RatingHandler) GetMappings(c *gin.Context) {
+	items, err := h.normalizer.ListMappings()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch rating mappings",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":        items,
+		"total":       len(items),
+		"taxonomy":    models.CanonicalRatings,
+		"score_scale": "0 (strong sell) - 5 (strong buy)",
+	})
+}
+
+// CreateMapping maneja POST /api/v1/admin/ratings/mappings
+// @Summary      Add rating mapping
+// @Description  Map a raw rating string to a canonical rating. The mapping takes precedence over built-in rules and is applied to stored rows immediately
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        mapping  body  ratingMappingRequest  true  "Mapping (score is optional, 0-5)"
+// @Success      201  {object}  map[string]interface{}  "Mapping saved"
+// @Failure      400  {object}  map[string]interface{}  "Invalid mapping"
+// @Failure      500  {object}  map[string]interface{}  "Failed to save rating mapping"
+// @Router       /api/v1/admin/ratings/mappings [post]
+func (h *RatingHandler) CreateMapping(c *gin.Context) {
+	var req ratingMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid rating mapping: raw_rating and canonical are required",
+		})
+		return
+	}
+
+	canonical, ok := models.ParseCanonicalRating(req.Canonical)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid canonical rating: " + req.Canonical,
+		})
+		return
+	}
+
+	mapping, updated, err := h.normalizer.AddMapping(req.RawRating, canonical, req.Score)
+	if errors.Is(err, services.ErrInvalidRatingMapping) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to save rating mapping",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"data":         mapping,
+		"rows_updated": updated,
+	})
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeRatingNormalizer struct {
+	listMappingsFn func() ([]models.RatingMapping, error)
+	listUnmappedFn func() ([]models.UnmappedRating, error)
+	addMappingFn   func(raw string, canonical models.CanonicalRating, score *float64) (*models.RatingMapping, int64, error)
+}
+
+func (f *fakeRatingNormalizer) ListMappings() ([]models.RatingMapping, error) {
+	if f.listMappingsFn != nil {
+		return f.listMappingsFn()
+	}
+	return []models.RatingMapping{}, nil
+}
+func (f *fakeRatingNormalizer) ListUnmapped() ([]models.UnmappedRating, error) {
+	if f.listUnmappedFn != nil {
+		return f.listUnmappedFn()
+	}
+	return []models.UnmappedRating{}, nil
+}
+func (f *fakeRatingNormalizer) AddMapping(raw string, canonical models.CanonicalRating, score *float64) (*models.RatingMapping, int64, error) {
+	if f.addMappingFn != nil {
+		return f.addMappingFn(raw, canonical, score)
+	}
+	return &models.RatingMapping{RawRating: raw, Canonical: canonical}, 0, nil
+}
+
+func TestGetUnmappedRatings_ErrorAndSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	errHandler := NewRatingHandlerWithServices(&fakeRatingNormalizer{
+		listUnmappedFn: func() ([]models.UnmappedRating, error) { return nil, errors.New("x") },
+	})
+	r.GET("/unmapped-err", errHandler.GetUnmapped)
+
+	wErr := httptest.NewRecorder()
+	r.ServeHTTP(wErr, httptest.NewRequest(http.MethodGet, "/unmapped-err", nil))
+	if wErr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", wErr.Code, http.StatusInternalServerError)
+	}
+
+	okHandler := NewRatingHandlerWithServices(&fakeRatingNormalizer{
+		listUnmappedFn: func() ([]models.UnmappedRating, error) {
+			return []models.UnmappedRating{{RawRating: "Top Pick", Count: 3}}, nil
+		},
+	})
+	r.GET("/unmapped-ok", okHandler.GetUnmapped)
+
+	wOK := httptest.NewRecorder()
+	r.ServeHTTP(wOK, httptest.NewRequest(http.MethodGet, "/unmapped-ok", nil))
+	if wOK.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", wOK.Code, http.StatusOK)
+	}
+	if !strings.Contains(wOK.Body.String(), `"raw_rating":"Top Pick"`) {
+		t.Fatalf("unexpected body: %s", wOK.Body.String())
+	}
+}
+
+func TestCreateRatingMapping(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewRatingHandlerWithServices(&fakeRatingNormalizer{
+		addMappingFn: func(raw string, canonical models.CanonicalRating, score *float64) (*models.RatingMapping, int64, error) {
+			if score != nil && *score > 5 {
+				return nil, 0, fmt.Errorf("%w: score must be between 0 and 5", services.ErrInvalidRatingMapping)
+			}
+			if canonical != models.RatingStrongBuy {
+				t.Fatalf("canonical = %q, want strong_buy", canonical)
+			}
+			return &models.RatingMapping{RawRating: "top pick", Canonical: canonical, Score: 5}, 4, nil
+		},
+	})
+	r.POST("/mappings", h.CreateMapping)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "created", body: `{"raw_rating":"Top Pick","canonical":"strong buy"}`, want: http.StatusCreated},
+		{name: "missing fields", body: `{"raw_rating":"Top Pick"}`, want: http.StatusBadRequest},
+		{name: "unknown canonical", body: `{"raw_rating":"Top Pick","canonical":"moon"}`, want: http.StatusBadRequest},
+		{name: "invalid score", body: `{"raw_rating":"Top Pick","canonical":"strong_buy","score":9}`, want: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mappings", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body=%s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
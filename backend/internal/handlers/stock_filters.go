@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+const dateOnlyLayout = "2006-01-02"
+
+// parseStockListFilter interpreta los filtros opcionales de GET /api/v1/stocks.
+// A diferencia de limit/offset, un valor inválido se rechaza en lugar de ignorarse.
+func parseStockListFilter(c *gin.Context) (repositories.StockFilter, error) {
+	var filter repositories.StockFilter
+
+	ranges := []struct {
+		key    string
+		target **float64
+	}{
+		{key: "min_target_from", target: &filter.MinTargetFrom},
+		{key: "max_target_from", target: &filter.MaxTargetFrom},
+		{key: "min_target_to", target: &filter.MinTargetTo},
+		{key: "max_target_to", target: &filter.MaxTargetTo},
+		{key: "min_change_pct", target: &filter.MinChangePct},
+		{key: "max_change_pct", target: &filter.MaxChangePct},
+	}
+	for _, rg := range ranges {
+		value, err := queryFloat(c, rg.key)
+		if err != nil {
+			return filter, err
+		}
+		*rg.target = value
+	}
+
+	if err := checkRange("target_from", filter.MinTargetFrom, filter.MaxTargetFrom); err != nil {
+		return filter, err
+	}
+	if err := checkRange("target_to", filter.MinTargetTo, filter.MaxTargetTo); err != nil {
+		return filter, err
+	}
+	if err := checkRange("change_pct", filter.MinChangePct, filter.MaxChangePct); err != nil {
+		return filter, err
+	}
+
+	if raw := strings.ToUpper(strings.TrimSpace(c.Query("currency"))); raw != "" {
+		if len(raw) != 3 {
+			return filter, fmt.Errorf("invalid currency: must be a 3-letter ISO code")
+		}
+		filter.Currency = raw
+	}
+
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		return filter, err
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		return filter, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return filter, fmt.Errorf("invalid time range: from is after to")
+	}
+	filter.TimeFrom, filter.TimeTo = from, to
+
+	filter.Brokerages = queryList(c, "brokerage")
+	for _, ticker := range queryList(c, "tickers") {
+		filter.Tickers = append(filter.Tickers, strings.ToUpper(ticker))
+	}
+
+	if raw := strings.TrimSpace(c.Query("canonical_rating")); raw != "" {
+		rating, ok := parseCanonicalRatingFilter(raw)
+		if !ok {
+			return filter, fmt.Errorf("invalid canonical_rating: %s", raw)
+		}
+		filter.CanonicalRating = rating
+	}
+	if raw := strings.TrimSpace(c.Query("event_type")); raw != "" {
+		event, ok := models.ParseActionEvent(raw)
+		if !ok {
+			return filter, fmt.Errorf("invalid event_type: %s", raw)
+		}
+		filter.EventType = event
+	}
+
+	expression, err := parseFilterExpression(c.Query("filter"))
+	if err != nil {
+		return filter, err
+	}
+	filter.Expression = expression
+
+	return filter, nil
+}
+
+func stockFilterPayload(c *gin.Context, filter repositories.StockFilter) gin.H {
+	return gin.H{
+		"min_target_from":  filter.MinTargetFrom,
+		"max_target_from":  filter.MaxTargetFrom,
+		"min_target_to":    filter.MinTargetTo,
+		"max_target_to":    filter.MaxTargetTo,
+		"min_change_pct":   filter.MinChangePct,
+		"max_change_pct":   filter.MaxChangePct,
+		"currency":         filter.Currency,
+		"from":             filter.TimeFrom,
+		"to":               filter.TimeTo,
+		"brokerage":        filter.Brokerages,
+		"tickers":          filter.Tickers,
+		"canonical_rating": filter.CanonicalRating,
+		"event_type":       filter.EventType,
+		"filter":           strings.TrimSpace(c.Query("filter")),
+	}
+}
+
+// queryTime acepta RFC3339 o YYYY-MM-DD. Con endOfDay, una fecha sin hora cubre el día completo.
+func queryTime(c *gin.Context, key string, endOfDay bool) (*time.Time, error) {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse(dateOnlyLayout, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: must be RFC3339 or YYYY-MM-DD", key)
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &parsed, nil
+}
+
+func queryFloat(c *gin.Context, key string) (*float64, error) {
+	raw := strings.TrimSpace(c.Query(key))
+	if raw == "" {
+		return nil, nil
+	}
+	// ParseFloat acepta "NaN" e "Inf", que no se pueden comparar en un rango
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil, fmt.Errorf("invalid %s: must be a finite number", key)
+	}
+	return &value, nil
+}
+
+func checkRange(name string, minValue, maxValue *float64) error {
+	if minValue != nil && maxValue != nil && *minValue > *maxValue {
+		return fmt.Errorf("invalid %s range: min is greater than max", name)
+	}
+	return nil
+}
@@ -1,30 +1,40 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
 	"github.com/Hitomiblood/StockStream/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type stockService interface {
-	GetAllStocks(limit, offset int, sortBy, order string) ([]models.Stock, int64, error)
+	GetAllStocks(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error)
+	GetStocksPage(filter repositories.StockFilter, limit int, sortBy, order, cursor string) (*services.StockPage, error)
 	GetStockByID(id uint64) (*models.Stock, error)
 	GetStocksByTicker(ticker string) ([]models.Stock, error)
-	SearchStocks(query string, limit int) ([]models.Stock, error)
-	FilterStocks(action, rating string, limit, offset int) ([]models.Stock, int64, error)
+	GetTickerTimeline(ticker string, from, to *time.Time, bucket models.TimelineBucket) (*models.TickerTimeline, error)
+	SearchStocks(query string, limit int) ([]models.SearchHit, error)
+	FilterStocks(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error)
 	SyncStocksFromAPI() (int, int, error)
 	GetUniqueActions() ([]string, error)
 	GetUniqueRatings() ([]string, error)
+	GetUniqueCanonicalRatings() ([]string, error)
+	GetUniqueBrokerages() ([]string, error)
+	GetEventTypeCounts() ([]models.EventTypeCount, error)
 	GetLatestStocks(limit int) ([]models.Stock, error)
 }
 
 type recommendationService interface {
-	GetRecommendations(limit int) ([]models.StockRecommendation, error)
+	GetRecommendations(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error)
+	GetBearishRecommendations(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error)
+	ExplainRecommendation(ticker string) (*models.RecommendationExplanation, error)
 }
 
 type StockHandler struct {
@@ -46,6 +56,11 @@ var allowedSortQueryFields = map[string]struct{}{
 	"time":        {},
 	"created_at":  {},
 	"updated_at":  {},
+
+	"target_from_value": {},
+	"target_to_value":   {},
+	"target_change_pct": {},
+	"target_currency":   {},
 }
 
 // NewStockHandler crea una nueva instancia del handler
@@ -73,9 +88,26 @@ func NewStockHandlerWithServices(stockService stockService, recService recommend
 // @Produce      json
 // @Param        limit   query  int     false  "Number of results (default: 50, max: 200)"
 // @Param        offset  query  int     false  "Offset for pagination (default: 0)"
-// @Param        sort    query  string  false  "Field to sort by (default: time)"
+// @Param        sort    query  string  false  "Field to sort by, including target_from_value, target_to_value and target_change_pct (default: time)"
 // @Param        order   query  string  false  "Sort order: asc or desc (default: desc)"
+// @Param        min_target_from  query  number  false  "Minimum numeric target_from"
+// @Param        max_target_from  query  number  false  "Maximum numeric target_from"
+// @Param        min_target_to    query  number  false  "Minimum numeric target_to"
+// @Param        max_target_to    query  number  false  "Maximum numeric target_to"
+// @Param        min_change_pct   query  number  false  "Minimum target change percent"
+// @Param        max_change_pct   query  number  false  "Maximum target change percent"
+// @Param        currency         query  string  false  "Target currency ISO code (e.g., USD)"
+// @Param        from             query  string  false  "Calls at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param        to               query  string  false  "Calls at or before this time (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param        brokerage        query  string  false  "Brokerage names, comma separated or repeated (case-insensitive)"
+// @Param        tickers          query  string  false  "Tickers, comma separated or repeated"
+// @Param        canonical_rating query  string  false  "Normalized target rating (strong_buy, buy, hold, sell, strong_sell, unmapped)"
+// @Param        event_type       query  string  false  "Event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)"
+// @Param        filter           query  string  false  "Filter expression, e.g. ticker in ('AAPL','MSFT') and (target_change_pct gt 10 or action contains 'raised')"
+// @Param        pagination       query  string  false  "Set to cursor for keyset pagination (implied when cursor is present)"
+// @Param        cursor           query  string  false  "Opaque next_cursor/prev_cursor from a previous cursor page"
 // @Success      200  {object}  map[string]interface{}  "List of stocks"
+// @Failure      400  {object}  map[string]interface{}  "Invalid stock filters or cursor"
 // @Failure      500  {object}  map[string]interface{}  "Internal server error"
 // @Router       /api/v1/stocks [get]
 func (h *StockHandler) GetAllStocks(c *gin.Context) {
@@ -94,7 +126,62 @@ func (h *StockHandler) GetAllStocks(c *gin.Context) {
 		limit = 50
 	}
 
-	stocks, total, err := h.stockService.GetAllStocks(limit, offset, sortBy, order)
+	filter, err := parseStockListFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid stock filters: " + err.Error(),
+		})
+		return
+	}
+
+	if cursor, ok := cursorPagination(c); ok {
+		h.respondStockPage(c, filter, limit, sortBy, order, cursor, stockFilterPayload(c, filter))
+		return
+	}
+
+	stocks, total, err := h.stockService.GetAllStocks(filter, limit, offset, sortBy, order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch stocks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"filters": stockFilterPayload(c, filter),
+		"data":    stocks,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
+	})
+}
+
+// cursorPagination indica si se pidió paginación por cursor y devuelve el cursor recibido
+func cursorPagination(c *gin.Context) (string, bool) {
+	cursor := strings.TrimSpace(c.Query("cursor"))
+	if cursor != "" {
+		return cursor, true
+	}
+	return "", strings.EqualFold(strings.TrimSpace(c.Query("pagination")), "cursor")
+}
+
+// respondStockPage responde una página por cursor. No incluye total: contar en cada página
+// anularía la ventaja frente a OFFSET en listados grandes.
+func (h *StockHandler) respondStockPage(c *gin.Context, filter repositories.StockFilter, limit int, sortBy, order, cursor string, filters gin.H) {
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+
+	page, err := h.stockService.GetStocksPage(filter, limit, sortBy, order, cursor)
+	if errors.Is(err, services.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid cursor",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch stocks",
@@ -103,10 +190,13 @@ func (h *StockHandler) GetAllStocks(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":   stocks,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"filters":     filters,
+		"data":        page.Data,
+		"limit":       limit,
+		"sort":        page.SortField,
+		"order":       page.Order,
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
 	})
 }
 
@@ -192,20 +282,90 @@ func (h *StockHandler) GetStocksByTicker(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"ticker":  ticker,
-		"company": stocks[0].Company,
+		"company": services.CanonicalCompanyName(stocks),
 		"history": stocks,
 		"total":   len(stocks),
 	})
 }
 
+// GetTickerTimeline maneja GET /api/v1/stocks/ticker/:ticker/timeline
+// @Summary      Ticker timeline
+// @Description  Get chart-ready series for a ticker: target price steps per brokerage, rating state changes and event counts per day, week (starting Monday) or month in UTC
+// @Tags         stocks
+// @Accept       json
+// @Produce      json
+// @Param        ticker  path   string  true   "Stock ticker symbol"
+// @Param        from    query  string  false  "Records at or after this time (RFC3339 or YYYY-MM-DD)"
+// @Param        to      query  string  false  "Records at or before this time (RFC3339 or YYYY-MM-DD, whole day)"
+// @Param        bucket  query  string  false  "Event bucket: day, week or month (default: day)"
+// @Success      200  {object}  models.TickerTimeline
+// @Failure      400  {object}  map[string]interface{}  "Invalid ticker, range or bucket"
+// @Failure      404  {object}  map[string]interface{}  "No stocks found for ticker"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /api/v1/stocks/ticker/{ticker}/timeline [get]
+func (h *StockHandler) GetTickerTimeline(c *gin.Context) {
+	ticker := c.Param("ticker")
+	if ticker == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Ticker is required",
+		})
+		return
+	}
+
+	bucket, ok := models.ParseTimelineBucket(strings.ToLower(strings.TrimSpace(c.DefaultQuery("bucket", "day"))))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid bucket: must be day, week or month",
+		})
+		return
+	}
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid timeline range: " + err.Error(),
+		})
+		return
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid timeline range: " + err.Error(),
+		})
+		return
+	}
+	if from != nil && to != nil && to.Before(*from) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid timeline range: to must not be before from",
+		})
+		return
+	}
+
+	timeline, err := h.stockService.GetTickerTimeline(ticker, from, to, bucket)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No stocks found for ticker " + ticker,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to build ticker timeline",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
+
 // SearchStocks maneja GET /api/v1/stocks/search
 // @Summary      Search stocks
-// @Description  Search stocks by ticker or company name
+// @Description  Ranked search by ticker prefix, company name (typo tolerant) and brokerage. Returns one result per ticker:
+// @Description  data holds the latest record of each ticker and results the ranking, record count and highlights.
 // @Tags         stocks
 // @Accept       json
 // @Produce      json
-// @Param        q      query  string  true   "Search query (ticker or company name)"
-// @Param        limit  query  int     false  "Number of results (default: 50, max: 200)"
+// @Param        q      query  string  true   "Search query (ticker, company or brokerage)"
+// @Param        limit  query  int     false  "Number of tickers (default: 50, max: 200)"
 // @Success      200  {object}  map[string]interface{}  "Search results"
 // @Failure      400  {object}  map[string]interface{}  "Missing search query"
 // @Failure      500  {object}  map[string]interface{}  "Internal server error"
@@ -223,8 +383,11 @@ func (h *StockHandler) SearchStocks(c *gin.Context) {
 	if limit > 200 {
 		limit = 200
 	}
+	if limit < 1 {
+		limit = 50
+	}
 
-	stocks, err := h.stockService.SearchStocks(query, limit)
+	hits, err := h.stockService.SearchStocks(query, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to search stocks",
@@ -232,41 +395,99 @@ func (h *StockHandler) SearchStocks(c *gin.Context) {
 		return
 	}
 
+	// data conserva la forma []Stock que ya consume el frontend
+	stocks := make([]models.Stock, len(hits))
+	for i, hit := range hits {
+		stocks[i] = hit.Latest
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"query": query,
-		"data":  stocks,
-		"total": len(stocks),
+		"query":   query,
+		"data":    stocks,
+		"results": hits,
+		"total":   len(hits),
 	})
 }
 
 // FilterStocks maneja GET /api/v1/stocks/filter
 // @Summary      Filter stocks
-// @Description  Filter stocks by action, rating or both criteria
+// @Description  Filter stocks by action, event type, raw rating, normalized rating, a filter expression or any combination
 // @Tags         stocks
 // @Accept       json
 // @Produce      json
-// @Param        action  query  string  false  "Filter by action (e.g., Upgrade, Downgrade, Initiated, Maintains)"
-// @Param        rating  query  string  false  "Filter by rating (e.g., Buy, Sell, Hold)"
-// @Param        limit   query  int     false  "Number of results (default: 50, max: 200)"
-// @Param        offset  query  int     false  "Offset for pagination (default: 0)"
+// @Param        action            query  string  false  "Filter by action (e.g., Upgrade, Downgrade, Initiated, Maintains)"
+// @Param        rating            query  string  false  "Filter by raw rating (e.g., Buy, Sell, Hold)"
+// @Param        event_type        query  string  false  "Filter by event type (upgrade, downgrade, target_raise, target_cut, initiation, reiteration, coverage_drop, other)"
+// @Param        canonical_rating  query  string  false  "Filter by normalized rating (strong_buy, buy, hold, sell, strong_sell, unmapped)"
+// @Param        filter            query  string  false  "Filter expression over the sortable fields (eq, ne, in, gt, lt, contains, and, or, parentheses)"
+// @Param        limit             query  int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset            query  int     false  "Offset for pagination (default: 0)"
+// @Param        pagination        query  string  false  "Set to cursor for keyset pagination ordered by time desc"
+// @Param        cursor            query  string  false  "Opaque next_cursor/prev_cursor from a previous cursor page"
 // @Success      200  {object}  map[string]interface{}  "Filtered results"
-// @Failure      400  {object}  map[string]interface{}  "Missing filter parameters"
+// @Failure      400  {object}  map[string]interface{}  "Missing or invalid filter parameters"
 // @Failure      500  {object}  map[string]interface{}  "Internal server error"
 // @Router       /api/v1/stocks/filter [get]
 func (h *StockHandler) FilterStocks(c *gin.Context) {
 	action := c.Query("action")
 	rating := c.Query("rating")
+	eventType := strings.TrimSpace(c.Query("event_type"))
+	canonicalRating := strings.TrimSpace(c.Query("canonical_rating"))
+	rawExpression := strings.TrimSpace(c.Query("filter"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	if action == "" && rating == "" {
+	if action == "" && rating == "" && eventType == "" && canonicalRating == "" && rawExpression == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "At least one filter parameter (action, event_type, rating, canonical_rating or filter) is required",
+		})
+		return
+	}
+
+	filter := repositories.StockFilter{Action: action, Rating: rating}
+	if eventType != "" {
+		parsed, ok := models.ParseActionEvent(eventType)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid event_type: " + eventType,
+			})
+			return
+		}
+		filter.EventType = parsed
+	}
+	if canonicalRating != "" {
+		parsed, ok := parseCanonicalRatingFilter(canonicalRating)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid canonical_rating: " + canonicalRating,
+			})
+			return
+		}
+		filter.CanonicalRating = parsed
+	}
+	expression, err := parseFilterExpression(rawExpression)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "At least one filter parameter (action or rating) is required",
+			"error": "Invalid stock filters: " + err.Error(),
 		})
 		return
 	}
+	filter.Expression = expression
+
+	filters := gin.H{
+		"action":           action,
+		"event_type":       filter.EventType,
+		"rating":           rating,
+		"canonical_rating": filter.CanonicalRating,
+		"filter":           rawExpression,
+	}
+
+	if cursor, ok := cursorPagination(c); ok {
+		h.respondStockPage(c, filter, limit, "time", "desc", cursor, filters)
+		return
+	}
 
-	stocks, total, err := h.stockService.FilterStocks(action, rating, limit, offset)
+	stocks, total, err := h.stockService.FilterStocks(filter, limit, offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to filter stocks",
@@ -275,14 +496,11 @@ func (h *StockHandler) FilterStocks(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"filters": gin.H{
-			"action": action,
-			"rating": rating,
-		},
-		"data":   stocks,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+		"filters": filters,
+		"data":    stocks,
+		"total":   total,
+		"limit":   limit,
+		"offset":  offset,
 	})
 }
 
@@ -319,24 +537,32 @@ func (h *StockHandler) FetchStocks(c *gin.Context) {
 
 // GetRecommendations maneja GET /api/v1/recommendations
 // @Summary      Get investment recommendations
-// @Description  Get intelligent stock recommendations based on multiple criteria
+// @Description  Get intelligent stock recommendations based on multiple criteria. Record filters are applied before scoring; score and confidence thresholds before ranking.
 // @Tags         recommendations
 // @Accept       json
 // @Produce      json
-// @Param        limit  query  int  false  "Number of recommendations (default: 10, max: 50)"
+// @Param        limit               query  int     false  "Number of recommendations (default: 10, max: 50)"
+// @Param        min_score           query  number  false  "Minimum score (0-100); disables the fallback to lower scores"
+// @Param        min_confidence      query  string  false  "Minimum confidence: low, medium or high"
+// @Param        brokerages          query  string  false  "Comma-separated brokerages to include"
+// @Param        exclude_brokerages  query  string  false  "Comma-separated brokerages to exclude"
+// @Param        tickers             query  string  false  "Comma-separated tickers to include"
+// @Param        actions             query  string  false  "Comma-separated event types (e.g. upgrade, target_raise) or exact actions (e.g. upgraded by) to include"
+// @Param        lookback_days       query  int     false  "Lookback window in days (default: 30, widened to 90 when empty; max: 365)"
 // @Success      200  {object}  map[string]interface{}  "Recommendations payload"
+// @Failure      400  {object}  map[string]interface{}  "Invalid filter parameters"
 // @Failure      500  {object}  map[string]interface{}  "Failed to generate recommendations"
 // @Router       /api/v1/recommendations [get]
 func (h *StockHandler) GetRecommendations(c *gin.Context) {
-	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
-	if limit > 50 {
-		limit = 50
-	}
-	if limit < 1 {
-		limit = 10
+	filter, err := parseRecommendationFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid recommendation filters: " + err.Error(),
+		})
+		return
 	}
 
-	recommendations, err := h.recommendationService.GetRecommendations(limit)
+	recommendations, model, err := h.recommendationService.GetRecommendations(filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to generate recommendations",
@@ -348,17 +574,177 @@ func (h *StockHandler) GetRecommendations(c *gin.Context) {
 		"recommendations": recommendations,
 		"generated_at":    h.now(),
 		"count":           len(recommendations),
-		"criteria": gin.H{
-			"target_change_weight":   0.4,
-			"rating_change_weight":   0.3,
-			"recent_activity_weight": 0.3,
-		},
+		"filters":         recommendationFilterPayload(filter),
+		"model":           model,
+	})
+}
+
+// GetBearishRecommendations maneja GET /api/v1/recommendations/bearish
+// @Summary      Get bearish signals
+// @Description  Get the lowest-scored tickers (sell/avoid signals) with the same explanations as recommendations
+// @Tags         recommendations
+// @Accept       json
+// @Produce      json
+// @Param        limit               query  int     false  "Number of signals (default: 10, max: 50)"
+// @Param        min_confidence      query  string  false  "Minimum confidence: low, medium or high"
+// @Param        brokerages          query  string  false  "Comma-separated brokerages to include"
+// @Param        exclude_brokerages  query  string  false  "Comma-separated brokerages to exclude"
+// @Param        tickers             query  string  false  "Comma-separated tickers to include"
+// @Param        actions             query  string  false  "Comma-separated event types (e.g. downgrade, target_cut) or exact actions (e.g. downgraded by) to include"
+// @Param        lookback_days       query  int     false  "Lookback window in days (default: 30, widened to 90 when empty; max: 365)"
+// @Success      200  {object}  map[string]interface{}  "Bearish signals payload"
+// @Failure      400  {object}  map[string]interface{}  "Invalid filter parameters"
+// @Failure      500  {object}  map[string]interface{}  "Failed to generate bearish signals"
+// @Router       /api/v1/recommendations/bearish [get]
+func (h *StockHandler) GetBearishRecommendations(c *gin.Context) {
+	if _, ok := c.GetQuery("min_score"); ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid recommendation filters: min_score is not supported for bearish signals",
+		})
+		return
+	}
+
+	filter, err := parseRecommendationFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid recommendation filters: " + err.Error(),
+		})
+		return
+	}
+
+	recommendations, model, err := h.recommendationService.GetBearishRecommendations(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to generate bearish signals",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"recommendations": recommendations,
+		"direction":       "bearish",
+		"generated_at":    h.now(),
+		"count":           len(recommendations),
+		"filters":         recommendationFilterPayload(filter),
+		"model":           model,
 	})
 }
 
+// parseRecommendationFilter lee los filtros de query compartidos por los endpoints de recomendaciones
+func parseRecommendationFilter(c *gin.Context) (services.RecommendationFilter, error) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit > 50 {
+		limit = 50
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	filter := services.RecommendationFilter{
+		Limit:             limit,
+		Brokerages:        queryList(c, "brokerages"),
+		ExcludeBrokerages: queryList(c, "exclude_brokerages"),
+		Tickers:           queryList(c, "tickers"),
+		Actions:           queryList(c, "actions"),
+	}
+
+	if raw := strings.TrimSpace(c.Query("min_score")); raw != "" {
+		minScore, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(minScore) || minScore < 0 || minScore > 100 {
+			return filter, errors.New("invalid min_score: must be a number between 0 and 100")
+		}
+		filter.MinScore = minScore
+	}
+
+	if raw := strings.ToLower(strings.TrimSpace(c.Query("min_confidence"))); raw != "" {
+		level, ok := models.ParseConfidenceLevel(raw)
+		if !ok {
+			return filter, errors.New("invalid min_confidence: must be low, medium or high")
+		}
+		filter.MinConfidence = level
+	}
+
+	if raw := strings.TrimSpace(c.Query("lookback_days")); raw != "" {
+		lookbackDays, err := strconv.Atoi(raw)
+		if err != nil || lookbackDays < 1 {
+			return filter, errors.New("invalid lookback_days: must be a positive integer")
+		}
+		if lookbackDays > 365 {
+			lookbackDays = 365
+		}
+		filter.LookbackDays = lookbackDays
+	}
+
+	return filter, nil
+}
+
+func recommendationFilterPayload(filter services.RecommendationFilter) gin.H {
+	return gin.H{
+		"limit":              filter.Limit,
+		"min_score":          filter.MinScore,
+		"min_confidence":     filter.MinConfidence,
+		"brokerages":         filter.Brokerages,
+		"exclude_brokerages": filter.ExcludeBrokerages,
+		"tickers":            filter.Tickers,
+		"actions":            filter.Actions,
+		"lookback_days":      filter.LookbackDays,
+	}
+}
+
+// queryList acepta valores separados por coma y/o el parámetro repetido
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, part := range strings.Split(raw, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+	}
+	return values
+}
+
+// ExplainRecommendation maneja GET /api/v1/recommendations/:ticker/explain
+// @Summary      Explain recommendation score
+// @Description  Get the full feature breakdown behind the recommendation score of a ticker
+// @Tags         recommendations
+// @Accept       json
+// @Produce      json
+// @Param        ticker  path      string  true  "Stock ticker symbol"
+// @Success      200  {object}  models.RecommendationExplanation
+// @Failure      400  {object}  map[string]interface{}  "Invalid ticker"
+// @Failure      404  {object}  map[string]interface{}  "No stocks found for ticker"
+// @Failure      500  {object}  map[string]interface{}  "Failed to explain recommendation"
+// @Router       /api/v1/recommendations/{ticker}/explain [get]
+func (h *StockHandler) ExplainRecommendation(c *gin.Context) {
+	ticker := c.Param("ticker")
+	if ticker == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Ticker is required",
+		})
+		return
+	}
+
+	explanation, err := h.recommendationService.ExplainRecommendation(ticker)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "No stocks found for ticker " + ticker,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to explain recommendation",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
+
 // GetMetadata maneja GET /api/v1/metadata
 // @Summary      Get metadata
-// @Description  Get unique values for actions and ratings available in the database
+// @Description  Get unique values for actions, raw ratings, normalized ratings and brokerages, plus record counts per event type
 // @Tags         metadata
 // @Accept       json
 // @Produce      json
@@ -368,8 +754,11 @@ func (h *StockHandler) GetRecommendations(c *gin.Context) {
 func (h *StockHandler) GetMetadata(c *gin.Context) {
 	actions, err1 := h.stockService.GetUniqueActions()
 	ratings, err2 := h.stockService.GetUniqueRatings()
+	canonicalRatings, err3 := h.stockService.GetUniqueCanonicalRatings()
+	eventTypes, err4 := h.stockService.GetEventTypeCounts()
+	brokerages, err5 := h.stockService.GetUniqueBrokerages()
 
-	if err1 != nil || err2 != nil {
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil || err5 != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to fetch metadata",
 		})
@@ -377,11 +766,22 @@ func (h *StockHandler) GetMetadata(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"actions": actions,
-		"ratings": ratings,
+		"actions":           actions,
+		"ratings":           ratings,
+		"canonical_ratings": canonicalRatings,
+		"event_types":       eventTypes,
+		"brokerages":        brokerages,
 	})
 }
 
+// parseCanonicalRatingFilter acepta la taxonomía canónica y además "unmapped" para auditar datos
+func parseCanonicalRatingFilter(value string) (models.CanonicalRating, bool) {
+	if strings.EqualFold(value, string(models.RatingUnmapped)) {
+		return models.RatingUnmapped, true
+	}
+	return models.ParseCanonicalRating(value)
+}
+
 // GetLatestStocks maneja GET /api/v1/stocks/latest
 // @Summary      Get latest stocks
 // @Description  Get the most recent stock updates ordered by time
@@ -1,34 +1,51 @@
 package handlers
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
 type fakeStockService struct {
-	getAllStocksFn    func(limit, offset int, sortBy, order string) ([]models.Stock, int64, error)
+	getAllStocksFn    func(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error)
 	getStockByIDFn    func(id uint64) (*models.Stock, error)
-	searchStocksFn    func(query string, limit int) ([]models.Stock, error)
-	filterStocksFn    func(action, rating string, limit, offset int) ([]models.Stock, int64, error)
+	searchStocksFn    func(query string, limit int) ([]models.SearchHit, error)
+	filterStocksFn    func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error)
 	syncStocksFn      func() (int, int, error)
 	getActionsFn      func() ([]string, error)
 	getRatingsFn      func() ([]string, error)
+	getCanonicalFn    func() ([]string, error)
+	getBrokeragesFn   func() ([]string, error)
+	getEventTypesFn   func() ([]models.EventTypeCount, error)
+	getStocksPageFn   func(filter repositories.StockFilter, limit int, sortBy, order, cursor string) (*services.StockPage, error)
 	getLatestStocksFn func(limit int) ([]models.Stock, error)
 	getByTickerFn     func(ticker string) ([]models.Stock, error)
+	getTimelineFn     func(ticker string, from, to *time.Time, bucket models.TimelineBucket) (*models.TickerTimeline, error)
 }
 
-func (f *fakeStockService) GetAllStocks(limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
+func (f *fakeStockService) GetAllStocks(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
 	if f.getAllStocksFn != nil {
-		return f.getAllStocksFn(limit, offset, sortBy, order)
+		return f.getAllStocksFn(filter, limit, offset, sortBy, order)
 	}
 	return nil, 0, nil
 }
+func (f *fakeStockService) GetStocksPage(filter repositories.StockFilter, limit int, sortBy, order, cursor string) (*services.StockPage, error) {
+	if f.getStocksPageFn != nil {
+		return f.getStocksPageFn(filter, limit, sortBy, order, cursor)
+	}
+	return &services.StockPage{}, nil
+}
 func (f *fakeStockService) GetStockByID(id uint64) (*models.Stock, error) {
 	if f.getStockByIDFn != nil {
 		return f.getStockByIDFn(id)
@@ -41,15 +58,21 @@ func (f *fakeStockService) GetStocksByTicker(ticker string) ([]models.Stock, err
 	}
 	return nil, nil
 }
-func (f *fakeStockService) SearchStocks(query string, limit int) ([]models.Stock, error) {
+func (f *fakeStockService) GetTickerTimeline(ticker string, from, to *time.Time, bucket models.TimelineBucket) (*models.TickerTimeline, error) {
+	if f.getTimelineFn != nil {
+		return f.getTimelineFn(ticker, from, to, bucket)
+	}
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeStockService) SearchStocks(query string, limit int) ([]models.SearchHit, error) {
 	if f.searchStocksFn != nil {
 		return f.searchStocksFn(query, limit)
 	}
 	return nil, nil
 }
-func (f *fakeStockService) FilterStocks(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
+func (f *fakeStockService) FilterStocks(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
 	if f.filterStocksFn != nil {
-		return f.filterStocksFn(action, rating, limit, offset)
+		return f.filterStocksFn(filter, limit, offset)
 	}
 	return nil, 0, nil
 }
@@ -71,6 +94,24 @@ func (f *fakeStockService) GetUniqueRatings() ([]string, error) {
 	}
 	return []string{}, nil
 }
+func (f *fakeStockService) GetUniqueCanonicalRatings() ([]string, error) {
+	if f.getCanonicalFn != nil {
+		return f.getCanonicalFn()
+	}
+	return []string{}, nil
+}
+func (f *fakeStockService) GetUniqueBrokerages() ([]string, error) {
+	if f.getBrokeragesFn != nil {
+		return f.getBrokeragesFn()
+	}
+	return []string{}, nil
+}
+func (f *fakeStockService) GetEventTypeCounts() ([]models.EventTypeCount, error) {
+	if f.getEventTypesFn != nil {
+		return f.getEventTypesFn()
+	}
+	return []models.EventTypeCount{}, nil
+}
 func (f *fakeStockService) GetLatestStocks(limit int) ([]models.Stock, error) {
 	if f.getLatestStocksFn != nil {
 		return f.getLatestStocksFn(limit)
@@ -79,14 +120,28 @@ func (f *fakeStockService) GetLatestStocks(limit int) ([]models.Stock, error) {
 }
 
 type fakeRecommendationService struct {
-	getRecommendationsFn func(limit int) ([]models.StockRecommendation, error)
+	getRecommendationsFn func(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error)
+	getBearishFn         func(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error)
+	explainFn            func(ticker string) (*models.RecommendationExplanation, error)
 }
 
-func (f *fakeRecommendationService) GetRecommendations(limit int) ([]models.StockRecommendation, error) {
+func (f *fakeRecommendationService) GetRecommendations(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
 	if f.getRecommendationsFn != nil {
-		return f.getRecommendationsFn(limit)
+		return f.getRecommendationsFn(filter)
 	}
-	return nil, nil
+	return nil, models.RecommendationModel{}, nil
+}
+func (f *fakeRecommendationService) GetBearishRecommendations(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+	if f.getBearishFn != nil {
+		return f.getBearishFn(filter)
+	}
+	return nil, models.RecommendationModel{}, nil
+}
+func (f *fakeRecommendationService) ExplainRecommendation(ticker string) (*models.RecommendationExplanation, error) {
+	if f.explainFn != nil {
+		return f.explainFn(ticker)
+	}
+	return nil, repositories.ErrNotFound
 }
 
 func TestSanitizeSortParams(t *testing.T) {
@@ -101,7 +156,7 @@ func TestGetAllStocks_SanitizesParamsAndReturns200(t *testing.T) {
 	r := gin.New()
 
 	h := NewStockHandlerWithServices(&fakeStockService{
-		getAllStocksFn: func(limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
+		getAllStocksFn: func(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
 			if limit != 50 || offset != 0 || sortBy != "time" || order != "desc" {
 				t.Fatalf("unexpected params: limit=%d offset=%d sort=%s order=%s", limit, offset, sortBy, order)
 			}
@@ -169,11 +224,11 @@ func TestGetRecommendations_ClampsLimit(t *testing.T) {
 	r := gin.New()
 
 	h := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{
-		getRecommendationsFn: func(limit int) ([]models.StockRecommendation, error) {
-			if limit != 50 {
-				t.Fatalf("expected clamped limit 50, got %d", limit)
+		getRecommendationsFn: func(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+			if filter.Limit != 50 {
+				t.Fatalf("expected clamped limit 50, got %d", filter.Limit)
 			}
-			return []models.StockRecommendation{}, nil
+			return []models.StockRecommendation{}, models.RecommendationModel{}, nil
 		},
 	})
 	h.now = func() time.Time { return time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC) }
@@ -188,6 +243,111 @@ func TestGetRecommendations_ClampsLimit(t *testing.T) {
 	}
 }
 
+func TestGetRecommendations_ParsesFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	h := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{
+		getRecommendationsFn: func(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+			if filter.MinScore != 60 || filter.MinConfidence != models.ConfidenceMedium || filter.LookbackDays != 14 {
+				t.Fatalf("unexpected thresholds: %+v", filter)
+			}
+			if len(filter.Brokerages) != 2 || filter.Brokerages[1] != "Morgan Stanley" {
+				t.Fatalf("unexpected brokerages: %+v", filter.Brokerages)
+			}
+			if len(filter.Tickers) != 2 || len(filter.ExcludeBrokerages) != 1 || len(filter.Actions) != 1 {
+				t.Fatalf("unexpected lists: %+v", filter)
+			}
+			return []models.StockRecommendation{}, models.RecommendationModel{}, nil
+		},
+	})
+	r.GET("/recs", h.GetRecommendations)
+
+	query := "/recs?min_score=60&min_confidence=medium&lookback_days=14" +
+		"&brokerages=Goldman%20Sachs,Morgan%20Stanley&exclude_brokerages=Citi&tickers=AAPL&tickers=MSFT&actions=upgraded"
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	for _, bad := range []string{"/recs?min_confidence=extreme", "/recs?min_score=abc", "/recs?min_score=NaN", "/recs?lookback_days=-3"} {
+		wBad := httptest.NewRecorder()
+		r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, bad, nil))
+		if wBad.Code != http.StatusBadRequest {
+			t.Fatalf("%s status = %d, want %d", bad, wBad.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetBearishRecommendations_DefaultLimitAndError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	h := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{
+		getBearishFn: func(filter services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+			if filter.Limit != 10 {
+				t.Fatalf("expected default limit 10, got %d", filter.Limit)
+			}
+			return []models.StockRecommendation{{Score: 20}}, models.RecommendationModel{}, nil
+		},
+	})
+	r.GET("/bearish", h.GetBearishRecommendations)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bearish?limit=0", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// min_score no aplica a las señales bajistas: se rechaza en lugar de ignorarlo
+	wScore := httptest.NewRecorder()
+	r.ServeHTTP(wScore, httptest.NewRequest(http.MethodGet, "/bearish?min_score=20", nil))
+	if wScore.Code != http.StatusBadRequest || !strings.Contains(wScore.Body.String(), "min_score") {
+		t.Fatalf("min_score: status = %d body = %s, want 400", wScore.Code, wScore.Body.String())
+	}
+
+	errHandler := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{
+		getBearishFn: func(services.RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+			return nil, models.RecommendationModel{}, errors.New("x")
+		},
+	})
+	r.GET("/bearish-err", errHandler.GetBearishRecommendations)
+
+	wErr := httptest.NewRecorder()
+	r.ServeHTTP(wErr, httptest.NewRequest(http.MethodGet, "/bearish-err", nil))
+	if wErr.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", wErr.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestExplainRecommendation_NotFoundAndSuccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	missing := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{})
+	r.GET("/missing/:ticker/explain", missing.ExplainRecommendation)
+
+	wMissing := httptest.NewRecorder()
+	r.ServeHTTP(wMissing, httptest.NewRequest(http.MethodGet, "/missing/ZZZ/explain", nil))
+	if wMissing.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", wMissing.Code, http.StatusNotFound)
+	}
+
+	found := NewStockHandlerWithServices(&fakeStockService{}, &fakeRecommendationService{
+		explainFn: func(ticker string) (*models.RecommendationExplanation, error) {
+			return &models.RecommendationExplanation{Ticker: ticker, Score: 70}, nil
+		},
+	})
+	r.GET("/found/:ticker/explain", found.ExplainRecommendation)
+
+	wFound := httptest.NewRecorder()
+	r.ServeHTTP(wFound, httptest.NewRequest(http.MethodGet, "/found/AAPL/explain", nil))
+	if wFound.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", wFound.Code, http.StatusOK)
+	}
+}
+
 func TestGetStockByID_NotFound(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -231,7 +391,11 @@ func TestGetStocksByTicker_Success(t *testing.T) {
 	r := gin.New()
 	h := NewStockHandlerWithServices(&fakeStockService{
 		getByTickerFn: func(ticker string) ([]models.Stock, error) {
-			return []models.Stock{{Ticker: ticker, Company: "Apple"}}, nil
+			return []models.Stock{
+				{Ticker: ticker, Company: "Apple"},
+				{Ticker: ticker, Company: "Apple Inc."},
+				{Ticker: ticker, Company: "Apple Inc."},
+			}, nil
 		},
 	}, &fakeRecommendationService{})
 	r.GET("/ticker/:ticker", h.GetStocksByTicker)
@@ -243,6 +407,62 @@ func TestGetStocksByTicker_Success(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
 	}
+	if !strings.Contains(w.Body.String(), `"company":"Apple Inc."`) {
+		t.Fatalf("expected the canonical company name, got %s", w.Body.String())
+	}
+}
+
+func TestGetTickerTimeline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	var gotFrom, gotTo *time.Time
+	var gotBucket models.TimelineBucket
+	h := NewStockHandlerWithServices(&fakeStockService{
+		getTimelineFn: func(ticker string, from, to *time.Time, bucket models.TimelineBucket) (*models.TickerTimeline, error) {
+			if ticker == "NONE" {
+				return nil, repositories.ErrNotFound
+			}
+			if ticker == "FAIL" {
+				return nil, errors.New("db")
+			}
+			gotFrom, gotTo, gotBucket = from, to, bucket
+			return &models.TickerTimeline{Ticker: ticker, Bucket: bucket}, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/ticker/:ticker/timeline", h.GetTickerTimeline)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ticker/AAPL/timeline?from=2026-01-01&to=2026-01-31&bucket=WEEK", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotBucket != models.TimelineBucketWeek || gotFrom == nil || gotTo == nil {
+		t.Fatalf("bucket/from/to = %q/%v/%v", gotBucket, gotFrom, gotTo)
+	}
+	if want := time.Date(2026, 1, 31, 23, 59, 59, 999999999, time.UTC); !gotTo.Equal(want) {
+		t.Fatalf("to should cover the whole day, got %v", gotTo)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ticker/AAPL/timeline", nil))
+	if w.Code != http.StatusOK || gotBucket != models.TimelineBucketDay || gotFrom != nil {
+		t.Fatalf("defaults: status=%d bucket=%q from=%v", w.Code, gotBucket, gotFrom)
+	}
+
+	for path, want := range map[string]int{
+		"/ticker/AAPL/timeline?bucket=year":                   http.StatusBadRequest,
+		"/ticker/AAPL/timeline?from=yesterday":                http.StatusBadRequest,
+		"/ticker/AAPL/timeline?from=2026-02-01&to=2026-01-01": http.StatusBadRequest,
+		"/ticker/NONE/timeline":                               http.StatusNotFound,
+		"/ticker/FAIL/timeline":                               http.StatusInternalServerError,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Fatalf("%s status = %d, want %d", path, rec.Code, want)
+		}
+	}
 }
 
 func TestSearchStocks_MissingQuery(t *testing.T) {
@@ -260,6 +480,157 @@ func TestSearchStocks_MissingQuery(t *testing.T) {
 	}
 }
 
+func TestSearchStocks_GroupedResults(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		searchStocksFn: func(query string, limit int) ([]models.SearchHit, error) {
+			if query != "alphabt" || limit != 50 {
+				t.Fatalf("unexpected args query=%q limit=%d", query, limit)
+			}
+			return []models.SearchHit{{
+				Ticker:     "GOOGL",
+				Company:    "Alphabet Inc.",
+				Score:      0.327,
+				MatchType:  models.SearchMatchCompanyFuzzy,
+				Records:    5,
+				Highlights: []models.SearchHighlight{{Field: "company", Value: "Alphabet Inc.", Start: 0, End: 8}},
+				Latest:     models.Stock{ID: 12, Ticker: "GOOGL"},
+			}}, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/search", h.SearchStocks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?q=alphabt&limit=0", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var body struct {
+		Data    []models.Stock     `json:"data"`
+		Results []models.SearchHit `json:"results"`
+		Total   int                `json:"total"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if body.Total != 1 || len(body.Data) != 1 || body.Data[0].ID != 12 {
+		t.Fatalf("data should hold the latest record per ticker: %s", w.Body.String())
+	}
+	if len(body.Results) != 1 || body.Results[0].MatchType != models.SearchMatchCompanyFuzzy || len(body.Results[0].Highlights) != 1 {
+		t.Fatalf("unexpected results: %s", w.Body.String())
+	}
+}
+
+func TestGetAllStocks_RangeFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		getAllStocksFn: func(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
+			if filter.MinTargetTo == nil || *filter.MinTargetTo != 100 || filter.MaxChangePct == nil || *filter.MaxChangePct != -5 {
+				t.Fatalf("unexpected filter: %+v", filter)
+			}
+			if filter.Currency != "USD" || sortBy != "target_change_pct" || order != "asc" {
+				t.Fatalf("unexpected currency/sort: %q %q %q", filter.Currency, sortBy, order)
+			}
+			return []models.Stock{{Ticker: "AAPL"}}, 1, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/stocks", h.GetAllStocks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stocks?min_target_to=100&max_change_pct=-5&currency=usd&sort=target_change_pct&order=asc", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body=%s)", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	for _, query := range []string{"min_target_to=abc", "min_target_to=NaN", "max_target_from=Inf", "min_change_pct=-Infinity", "min_change_pct=10&max_change_pct=5", "currency=dollars"} {
+		wBad := httptest.NewRecorder()
+		r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/stocks?"+query, nil))
+		if wBad.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want %d", query, wBad.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetAllStocks_AnalystFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		getAllStocksFn: func(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
+			if filter.EventType != models.ActionEventTargetRaise || filter.MinChangePct == nil || *filter.MinChangePct != 20 {
+				t.Fatalf("unexpected event/change filter: %+v", filter)
+			}
+			if filter.TimeFrom == nil || !filter.TimeFrom.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+				t.Fatalf("unexpected from: %v", filter.TimeFrom)
+			}
+			if filter.TimeTo == nil || !filter.TimeTo.Equal(time.Date(2026, 2, 8, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond)) {
+				t.Fatalf("unexpected to: %v", filter.TimeTo)
+			}
+			if len(filter.Tickers) != 2 || filter.Tickers[0] != "AAPL" || len(filter.Brokerages) != 1 {
+				t.Fatalf("unexpected tickers/brokerages: %v %v", filter.Tickers, filter.Brokerages)
+			}
+			if filter.CanonicalRating != models.RatingBuy {
+				t.Fatalf("unexpected canonical rating: %q", filter.CanonicalRating)
+			}
+			return []models.Stock{{Ticker: "AAPL"}}, 1, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/stocks", h.GetAllStocks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stocks?event_type=target_raise&min_change_pct=20&from=2026-02-01&to=2026-02-07&tickers=aapl,msft&brokerage=Goldman%20Sachs&canonical_rating=buy", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d (body=%s)", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	for _, query := range []string{"from=yesterday", "from=2026-02-10&to=2026-02-01", "canonical_rating=great", "event_type=merger"} {
+		wBad := httptest.NewRecorder()
+		r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/stocks?"+query, nil))
+		if wBad.Code != http.StatusBadRequest {
+			t.Fatalf("%s: status = %d, want %d", query, wBad.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetAllStocks_CursorMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		getAllStocksFn: func(repositories.StockFilter, int, int, string, string) ([]models.Stock, int64, error) {
+			t.Fatalf("offset listing must not be used in cursor mode")
+			return nil, 0, nil
+		},
+		getStocksPageFn: func(filter repositories.StockFilter, limit int, sortBy, order, cursor string) (*services.StockPage, error) {
+			if cursor == "bogus" {
+				return nil, fmt.Errorf("%w: malformed encoding", services.ErrInvalidCursor)
+			}
+			if limit != 2 || sortBy != "time" || order != "desc" {
+				t.Fatalf("unexpected page args limit=%d sort=%s order=%s", limit, sortBy, order)
+			}
+			return &services.StockPage{Data: []models.Stock{{ID: 1}, {ID: 2}}, SortField: "time", Order: "desc", NextCursor: "next", PrevCursor: cursor}, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/stocks", h.GetAllStocks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stocks?pagination=cursor&limit=2", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"next_cursor":"next"`) || strings.Contains(body, `"total"`) {
+		t.Fatalf("unexpected body: %s", body)
+	}
+
+	wBad := httptest.NewRecorder()
+	r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/stocks?cursor=bogus", nil))
+	if wBad.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", wBad.Code, http.StatusBadRequest)
+	}
+}
+
 func TestFilterStocks_MissingFilters(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -279,7 +650,7 @@ func TestFilterStocks_Success(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
 	h := NewStockHandlerWithServices(&fakeStockService{
-		filterStocksFn: func(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
+		filterStocksFn: func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
 			return []models.Stock{{Ticker: "AAPL"}}, 1, nil
 		},
 	}, &fakeRecommendationService{})
@@ -294,6 +665,92 @@ func TestFilterStocks_Success(t *testing.T) {
 	}
 }
 
+func TestFilterStocks_CanonicalRating(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		filterStocksFn: func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+			if filter.CanonicalRating != models.RatingStrongBuy || filter.Action != "" {
+				t.Fatalf("unexpected filter: %+v", filter)
+			}
+			return []models.Stock{{Ticker: "AAPL", RatingToCanonical: models.RatingStrongBuy}}, 1, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/filter", h.FilterStocks)
+
+	req := httptest.NewRequest(http.MethodGet, "/filter?canonical_rating=Strong-Buy", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	reqBad := httptest.NewRequest(http.MethodGet, "/filter?canonical_rating=moon", nil)
+	wBad := httptest.NewRecorder()
+	r.ServeHTTP(wBad, reqBad)
+	if wBad.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", wBad.Code, http.StatusBadRequest)
+	}
+}
+
+func TestFilterStocks_Expression(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		filterStocksFn: func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+			expr := filter.Expression
+			if expr == nil || expr.Op != repositories.ExprOr || len(expr.Children) != 2 {
+				t.Fatalf("unexpected expression: %+v", expr)
+			}
+			return []models.Stock{{Ticker: "AAPL"}}, 1, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/filter", h.FilterStocks)
+
+	query := url.Values{"filter": {"ticker eq 'AAPL' or target_change_pct gt 15"}}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/filter?"+query.Encode(), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	bad := url.Values{"filter": {"ticker eq 'AAPL' or"}}
+	wBad := httptest.NewRecorder()
+	r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/filter?"+bad.Encode(), nil))
+	if wBad.Code != http.StatusBadRequest || !strings.Contains(wBad.Body.String(), "position") {
+		t.Fatalf("status = %d body = %s", wBad.Code, wBad.Body.String())
+	}
+}
+
+func TestFilterStocks_EventType(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStockHandlerWithServices(&fakeStockService{
+		filterStocksFn: func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+			if filter.EventType != models.ActionEventTargetRaise {
+				t.Fatalf("unexpected filter: %+v", filter)
+			}
+			return []models.Stock{{Ticker: "AAPL", EventType: models.ActionEventTargetRaise}}, 1, nil
+		},
+	}, &fakeRecommendationService{})
+	r.GET("/filter", h.FilterStocks)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/filter?event_type=target-raise", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), `"event_type":"target_raise"`) {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	wBad := httptest.NewRecorder()
+	r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/filter?event_type=merger", nil))
+	if wBad.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", wBad.Code, http.StatusBadRequest)
+	}
+}
+
 func TestGetMetadata_ErrorAndSuccess(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	r := gin.New()
@@ -313,6 +770,9 @@ func TestGetMetadata_ErrorAndSuccess(t *testing.T) {
 	okHandler := NewStockHandlerWithServices(&fakeStockService{
 		getActionsFn: func() ([]string, error) { return []string{"Upgrade"}, nil },
 		getRatingsFn: func() ([]string, error) { return []string{"Buy"}, nil },
+		getEventTypesFn: func() ([]models.EventTypeCount, error) {
+			return []models.EventTypeCount{{EventType: models.ActionEventUpgrade, Count: 4}}, nil
+		},
 	}, &fakeRecommendationService{})
 	r.GET("/meta-ok", okHandler.GetMetadata)
 
@@ -322,6 +782,9 @@ func TestGetMetadata_ErrorAndSuccess(t *testing.T) {
 	if wOK.Code != http.StatusOK {
 		t.Fatalf("status = %d, want %d", wOK.Code, http.StatusOK)
 	}
+	if !strings.Contains(wOK.Body.String(), `"event_types":[{"event_type":"upgrade","count":4}]`) {
+		t.Fatalf("unexpected body: %s", wOK.Body.String())
+	}
 }
 
 func TestGetLatestStocks_AndHealth(t *testing.T) {
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	streamHeartbeatInterval = 15 * time.Second
+	streamRetryMillis       = 3000
+)
+
+type eventSource interface {
+	Subscribe(lastEventID uint64) (*services.EventSubscription, []models.Event, bool)
+}
+
+type StreamHandler struct {
+	events    eventSource
+	heartbeat time.Duration
+}
+
+// NewStreamHandler crea una nueva instancia del handler del stream de eventos
+func NewStreamHandler(bus *services.EventBus) *StreamHandler {
+	return &StreamHandler{
+		events:    bus,
+		heartbeat: streamHeartbeatInterval,
+	}
+}
+
+func NewStreamHandlerWithServices(events eventSource) *StreamHandler {
+	return &StreamHandler{
+		events:    events,
+		heartbeat: streamHeartbeatInterval,
+	}
+}
+
+// Stream maneja GET /api/v1/stream
+// @Summary      Stream sync events (SSE)
+// @Description  Server-Sent Events stream of stock.created, stock.updated, sync.started, sync.progress, sync.completed, sync.failed
+// @Description  and recommendation.changed, published while a sync processes records. Each event carries an id; reconnect with the Last-Event-ID header
+// @Description  (or last_event_id) to resume from a bounded in-memory buffer. A stream.reset event means events were lost and
+// @Description  the client should refetch. ticker and brokerage filter stock and recommendation events only. A heartbeat comment is sent every 15 seconds
+// @Tags         stream
+// @Produce      text/event-stream
+// @Param        ticker         query   string  false  "Only stock and recommendation events of these tickers (comma-separated)"
+// @Param        brokerage      query   string  false  "Only stock events of these brokerages (comma-separated)"
+// @Param        Last-Event-ID  header  string  false  "Resume after this event id"
+// @Param        last_event_id  query   string  false  "Resume after this event id (for clients that cannot send headers)"
+// @Success      200  {string}  string  "text/event-stream"
+// @Failure      400  {object}  map[string]interface{}  "Invalid Last-Event-ID"
+// @Router       /api/v1/stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	lastEventID, err := streamLastEventID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid Last-Event-ID",
+		})
+		return
+	}
+
+	tickers := queryList(c, "ticker")
+	for i := range tickers {
+		tickers[i] = strings.ToUpper(tickers[i])
+	}
+	filter := services.EventFilter{Tickers: tickers, Brokerages: queryList(c, "brokerage")}
+
+	sub, replay, complete := h.events.Subscribe(lastEventID)
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetryMillis)
+	if !complete {
+		// Sin id para no mover el Last-Event-ID del cliente
+		fmt.Fprintf(w, "event: stream.reset\ndata: {\"reason\":\"events after Last-Event-ID are no longer buffered\"}\n\n")
+	}
+	for _, event := range replay {
+		if filter.Matches(event) {
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(h.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-sub.Events():
+			if !ok {
+				// El cliente no consumió a tiempo; EventSource se reconecta y reanuda con Last-Event-ID
+				return
+			}
+			if !filter.Matches(event) {
+				continue
+			}
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+			w.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprintf(w, ": heartbeat %s\n\n", time.Now().UTC().Format(time.RFC3339)); err != nil {
+				return
+			}
+			w.Flush()
+		}
+	}
+}
+
+// streamLastEventID lee Last-Event-ID (cabecera que envía EventSource al reconectar) o last_event_id
+func streamLastEventID(c *gin.Context) (uint64, error) {
+	raw := strings.TrimSpace(c.GetHeader("Last-Event-ID"))
+	if raw == "" {
+		raw = strings.TrimSpace(c.Query("last_event_id"))
+	}
+	if raw == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(raw, 10, 64)
+}
+
+func writeStreamEvent(w io.Writer, event models.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+func newStreamServer(t *testing.T, bus *services.EventBus, heartbeat time.Duration) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewStreamHandler(bus)
+	h.heartbeat = heartbeat
+	r.GET("/stream", h.Stream)
+	server := httptest.NewServer(r)
+	// Registrado antes que el cierre de las respuestas: Close espera a que terminen los streams
+	t.Cleanup(server.Close)
+	return server
+}
+
+// streamFrame es un bloque SSE separado por una línea en blanco
+type streamFrame struct {
+	id, event, data, comment string
+}
+
+func readStreamFrame(t *testing.T, reader *bufio.Reader) streamFrame {
+	t.Helper()
+	var frame streamFrame
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("reading stream: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			return frame
+		case strings.HasPrefix(line, ":"):
+			frame.comment = strings.TrimSpace(strings.TrimPrefix(line, ":"))
+		case strings.HasPrefix(line, "id: "):
+			frame.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			frame.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			frame.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func openStream(t *testing.T, url, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("expected event stream, got %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	reader := bufio.NewReader(resp.Body)
+	if frame := readStreamFrame(t, reader); frame.event != "" {
+		t.Fatalf("expected retry preamble first, got %+v", frame)
+	}
+	return resp, reader
+}
+
+func TestStream_ResumesFromLastEventIDWithFilters(t *testing.T) {
+	bus := services.NewEventBus(10)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+	bus.Publish(models.Event{Type: models.EventSyncStarted})
+	bus.Publish(models.Event{Type: models.EventStockCreated, Ticker: "AAPL", Brokerage: "UBS"})
+	bus.Publish(models.Event{Type: models.EventStockUpdated, Ticker: "MSFT", Brokerage: "UBS"})
+	bus.Publish(models.Event{Type: models.EventStockCreated, Ticker: "AAPL", Brokerage: "Goldman Sachs"})
+	started := <-sub.Events()
+
+	server := newStreamServer(t, bus, time.Hour)
+
+	_, reader := openStream(t, server.URL+"/stream?ticker=aapl&brokerage=ubs", strconv.FormatUint(started.ID, 10))
+
+	frame := readStreamFrame(t, reader)
+	if frame.event != string(models.EventStockCreated) || frame.id != strconv.FormatUint(started.ID+1, 10) ||
+		!strings.Contains(frame.data, `"ticker":"AAPL"`) || !strings.Contains(frame.data, `"brokerage":"UBS"`) {
+		t.Fatalf("unexpected replayed frame: %+v", frame)
+	}
+
+	// Los eventos en vivo pasan por el mismo filtro; los de sincronización no se filtran
+	bus.Publish(models.Event{Type: models.EventStockUpdated, Ticker: "TSLA", Brokerage: "UBS"})
+	bus.Publish(models.Event{Type: models.EventSyncCompleted, Data: models.SyncSummary{Created: 2}})
+	frame = readStreamFrame(t, reader)
+	if frame.event != string(models.EventSyncCompleted) || !strings.Contains(frame.data, `"created":2`) {
+		t.Fatalf("expected sync.completed after filtered TSLA event, got %+v", frame)
+	}
+}
+
+func TestStream_SendsResetWhenEventsWereDropped(t *testing.T) {
+	bus := services.NewEventBus(1)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+	bus.Publish(models.Event{Type: models.EventSyncStarted})
+	bus.Publish(models.Event{Type: models.EventSyncProgress})
+	bus.Publish(models.Event{Type: models.EventSyncCompleted})
+	started := <-sub.Events()
+
+	server := newStreamServer(t, bus, time.Hour)
+
+	_, reader := openStream(t, server.URL+"/stream", strconv.FormatUint(started.ID, 10))
+	if frame := readStreamFrame(t, reader); frame.event != "stream.reset" || frame.id != "" {
+		t.Fatalf("expected stream.reset without id, got %+v", frame)
+	}
+	if frame := readStreamFrame(t, reader); frame.event != string(models.EventSyncCompleted) {
+		t.Fatalf("expected buffered sync.completed, got %+v", frame)
+	}
+}
+
+func TestStream_Heartbeat(t *testing.T) {
+	server := newStreamServer(t, services.NewEventBus(10), 10*time.Millisecond)
+
+	_, reader := openStream(t, server.URL+"/stream", "")
+	if frame := readStreamFrame(t, reader); !strings.HasPrefix(frame.comment, "heartbeat ") || frame.event != "" {
+		t.Fatalf("expected heartbeat comment, got %+v", frame)
+	}
+}
+
+func TestStream_InvalidLastEventID(t *testing.T) {
+	server := newStreamServer(t, services.NewEventBus(10), time.Hour)
+
+	resp, err := http.Get(server.URL + "/stream?last_event_id=abc")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type tickerSuggester interface {
+	Suggest(query string, limit int) []models.TickerSuggestion
+	RefreshedAt() time.Time
+}
+
+type tickerCatalog interface {
+	ListTickers(limit, offset int, sortBy, order string) ([]models.Ticker, int64, error)
+	GetTicker(ticker string) (*models.Ticker, error)
+}
+
+type TickerHandler struct {
+	suggester tickerSuggester
+	catalog   tickerCatalog
+}
+
+var allowedTickerSortFields = map[string]struct{}{
+	"ticker":       {},
+	"first_seen":   {},
+	"last_seen":    {},
+	"record_count": {},
+}
+
+// NewTickerHandler crea una nueva instancia del handler de tickers
+func NewTickerHandler(index *services.TickerIndex, catalog *services.TickerCatalog) *TickerHandler {
+	return &TickerHandler{
+		suggester: index,
+		catalog:   catalog,
+	}
+}
+
+func NewTickerHandlerWithServices(suggester tickerSuggester, catalog tickerCatalog) *TickerHandler {
+	return &TickerHandler{
+		suggester: suggester,
+		catalog:   catalog,
+	}
+}
+
+// ListTickers maneja GET /api/v1/tickers
+// @Summary      List tickers
+// @Description  Ticker entities with canonical company, first/last seen dates, record count and latest rating
+// @Tags         tickers
+// @Accept       json
+// @Produce      json
+// @Param        limit   query  int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset  query  int     false  "Offset for pagination (default: 0)"
+// @Param        sort    query  string  false  "Sort field: ticker, first_seen, last_seen or record_count (default: last_seen)"
+// @Param        order   query  string  false  "Sort order: asc or desc (default: desc)"
+// @Success      200  {object}  map[string]interface{}  "List of tickers"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /api/v1/tickers [get]
+func (h *TickerHandler) ListTickers(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	sortBy := strings.ToLower(strings.TrimSpace(c.DefaultQuery("sort", "last_seen")))
+	if _, ok := allowedTickerSortFields[sortBy]; !ok {
+		sortBy = "last_seen"
+	}
+	order := "desc"
+	if strings.EqualFold(strings.TrimSpace(c.Query("order")), "asc") {
+		order = "asc"
+	}
+
+	tickers, total, err := h.catalog.ListTickers(limit, offset, sortBy, order)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch tickers",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   tickers,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+		"sort":   sortBy,
+		"order":  order,
+	})
+}
+
+// GetTicker maneja GET /api/v1/tickers/:ticker
+// @Summary      Get ticker
+// @Description  Ticker entity with its canonical company and latest rating
+// @Tags         tickers
+// @Accept       json
+// @Produce      json
+// @Param        ticker  path  string  true  "Stock ticker symbol"
+// @Success      200  {object}  models.Ticker
+// @Failure      404  {object}  map[string]interface{}  "Ticker not found"
+// @Failure      500  {object}  map[string]interface{}  "Internal server error"
+// @Router       /api/v1/tickers/{ticker} [get]
+func (h *TickerHandler) GetTicker(c *gin.Context) {
+	ticker := c.Param("ticker")
+
+	item, err := h.catalog.GetTicker(ticker)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Ticker not found: " + ticker,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch ticker",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// SuggestTickers maneja GET /api/v1/tickers/suggest
+// @Summary      Ticker autocomplete
+// @Description  Distinct ticker/company pairs whose ticker or company starts with q, ranked by match type and recent activity. Served from an in-memory index rebuilt after each sync.
+// @Tags         tickers
+// @Accept       json
+// @Produce      json
+// @Param        q      query  string  true   "Prefix of a ticker or company name"
+// @Param        limit  query  int     false  "Number of suggestions (default: 10, max: 50)"
+// @Success      200  {object}  map[string]interface{}  "Suggestions"
+// @Failure      400  {object}  map[string]interface{}  "Missing query"
+// @Router       /api/v1/tickers/suggest [get]
+func (h *TickerHandler) SuggestTickers(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Query 'q' is required",
+		})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit > 50 {
+		limit = 50
+	}
+	if limit < 1 {
+		limit = 10
+	}
+
+	suggestions := h.suggester.Suggest(query, limit)
+	c.JSON(http.StatusOK, gin.H{
+		"query":        query,
+		"data":         suggestions,
+		"total":        len(suggestions),
+		"refreshed_at": h.suggester.RefreshedAt(),
+	})
+}
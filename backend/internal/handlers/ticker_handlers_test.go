@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeTickerSuggester struct {
+	suggestFn func(query string, limit int) []models.TickerSuggestion
+}
+
+func (f *fakeTickerSuggester) Suggest(query string, limit int) []models.TickerSuggestion {
+	if f.suggestFn != nil {
+		return f.suggestFn(query, limit)
+	}
+	return nil
+}
+
+func (f *fakeTickerSuggester) RefreshedAt() time.Time {
+	return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+}
+
+type fakeTickerCatalog struct {
+	listTickersFn func(limit, offset int, sortBy, order string) ([]models.Ticker, int64, error)
+	getTickerFn   func(ticker string) (*models.Ticker, error)
+}
+
+func (f *fakeTickerCatalog) ListTickers(limit, offset int, sortBy, order string) ([]models.Ticker, int64, error) {
+	if f.listTickersFn != nil {
+		return f.listTickersFn(limit, offset, sortBy, order)
+	}
+	return nil, 0, nil
+}
+
+func (f *fakeTickerCatalog) GetTicker(ticker string) (*models.Ticker, error) {
+	if f.getTickerFn != nil {
+		return f.getTickerFn(ticker)
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func TestSuggestTickers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTickerHandlerWithServices(&fakeTickerSuggester{
+		suggestFn: func(query string, limit int) []models.TickerSuggestion {
+			if query != "aa" || limit != 50 {
+				t.Fatalf("unexpected args query=%q limit=%d", query, limit)
+			}
+			return []models.TickerSuggestion{{Ticker: "AAPL", Company: "Apple Inc.", Match: "ticker_prefix"}}
+		},
+	}, &fakeTickerCatalog{})
+	r.GET("/tickers/suggest", h.SuggestTickers)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tickers/suggest?q=aa&limit=500", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), `"ticker":"AAPL"`) || !strings.Contains(w.Body.String(), `"refreshed_at"`) {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+
+	wBad := httptest.NewRecorder()
+	r.ServeHTTP(wBad, httptest.NewRequest(http.MethodGet, "/tickers/suggest", nil))
+	if wBad.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", wBad.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListTickers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTickerHandlerWithServices(&fakeTickerSuggester{}, &fakeTickerCatalog{
+		listTickersFn: func(limit, offset int, sortBy, order string) ([]models.Ticker, int64, error) {
+			if limit != 200 || offset != 0 || sortBy != "last_seen" || order != "asc" {
+				t.Fatalf("unexpected args limit=%d offset=%d sort=%s order=%s", limit, offset, sortBy, order)
+			}
+			return []models.Ticker{{Ticker: "AAPL", RecordCount: 4, Company: &models.Company{ID: 1, Name: "Apple Inc."}}}, 1, nil
+		},
+	})
+	r.GET("/tickers", h.ListTickers)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tickers?limit=1000&offset=-3&sort=password&order=ASC", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if !strings.Contains(w.Body.String(), `"name":"Apple Inc."`) || !strings.Contains(w.Body.String(), `"total":1`) {
+		t.Fatalf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestGetTicker(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewTickerHandlerWithServices(&fakeTickerSuggester{}, &fakeTickerCatalog{
+		getTickerFn: func(ticker string) (*models.Ticker, error) {
+			if ticker == "AAPL" {
+				return &models.Ticker{Ticker: "AAPL", LatestRating: "Buy"}, nil
+			}
+			return nil, repositories.ErrNotFound
+		},
+	})
+	r.GET("/tickers/:ticker", h.GetTicker)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tickers/AAPL", nil))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"latest_rating":"Buy"`) {
+		t.Fatalf("status = %d body = %s", w.Code, w.Body.String())
+	}
+
+	wMissing := httptest.NewRecorder()
+	r.ServeHTTP(wMissing, httptest.NewRequest(http.MethodGet, "/tickers/ZZZZ", nil))
+	if wMissing.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", wMissing.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type watchlistService interface {
+	ListWatchlists(owner string) ([]models.Watchlist, error)
+	GetWatchlist(owner string, id uint64) (*models.Watchlist, error)
+	CreateWatchlist(owner, name, notes string, tickers []string) (*models.Watchlist, error)
+	UpdateWatchlist(owner string, id uint64, update services.WatchlistUpdate) (*models.Watchlist, error)
+	AddTickers(owner string, id uint64, tickers []string) (*models.Watchlist, error)
+	RemoveTicker(owner string, id uint64, ticker string) (*models.Watchlist, error)
+	DeleteWatchlist(owner string, id uint64) error
+	GetLatestStocks(owner string, id uint64) (*models.Watchlist, []models.Stock, error)
+	GetRecommendations(owner string, id uint64, filter services.RecommendationFilter) (*models.Watchlist, []models.StockRecommendation, models.RecommendationModel, error)
+	GetConsensus(owner string, id uint64, windowDays int) (*models.Watchlist, []models.TickerConsensus, error)
+}
+
+type WatchlistHandler struct {
+	watchlistService watchlistService
+}
+
+type createWatchlistRequest struct {
+	Name    string   `json:"name" binding:"required"`
+	Notes   string   `json:"notes"`
+	Tickers []string `json:"tickers"`
+}
+
+// updateWatchlistRequest usa punteros para distinguir campos omitidos de campos vacíos
+type updateWatchlistRequest struct {
+	Name    *string   `json:"name"`
+	Notes   *string   `json:"notes"`
+	Tickers *[]string `json:"tickers"`
+}
+
+type watchlistTickersRequest struct {
+	Tickers []string `json:"tickers" binding:"required"`
+}
+
+// NewWatchlistHandler crea una nueva instancia del handler de watchlists
+func NewWatchlistHandler(watchlistService *services.WatchlistService) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistService: watchlistService,
+	}
+}
+
+func NewWatchlistHandlerWithServices(watchlistService watchlistService) *WatchlistHandler {
+	return &WatchlistHandler{
+		watchlistService: watchlistService,
+	}
+}
+
+// ListWatchlists maneja GET /api/v1/watchlists
+// @Summary      List watchlists
+// @Description  List the caller's watchlists ordered by name. The owner is the X-API-Key header or, without it, X-User-ID
+// @Tags         watchlists
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Success      200  {object}  map[string]interface{}  "Watchlists"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch watchlists"
+// @Router       /api/v1/watchlists [get]
+func (h *WatchlistHandler) ListWatchlists(c *gin.Context) {
+	watchlists, err := h.watchlistService.ListWatchlists(middleware.Owner(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch watchlists",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  watchlists,
+		"total": len(watchlists),
+	})
+}
+
+// CreateWatchlist maneja POST /api/v1/watchlists
+// @Summary      Create watchlist
+// @Description  Create a named watchlist with optional notes and tickers. Names are unique per owner; tickers are upper-cased and de-duplicated
+// @Tags         watchlists
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                  false  "User identifier (used when no API key is sent)"
+// @Param        watchlist  body    createWatchlistRequest  true   "Watchlist"
+// @Success      201  {object}  models.Watchlist
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      409  {object}  map[string]interface{}  "Watchlist name already in use"
+// @Failure      500  {object}  map[string]interface{}  "Failed to create watchlist"
+// @Router       /api/v1/watchlists [post]
+func (h *WatchlistHandler) CreateWatchlist(c *gin.Context) {
+	var req createWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid watchlist: name is required",
+		})
+		return
+	}
+
+	watchlist, err := h.watchlistService.CreateWatchlist(middleware.Owner(c), req.Name, req.Notes, req.Tickers)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to create watchlist")
+		return
+	}
+
+	c.JSON(http.StatusCreated, watchlist)
+}
+
+// GetWatchlist maneja GET /api/v1/watchlists/:id
+// @Summary      Get watchlist
+// @Description  Get one of the caller's watchlists with its tickers
+// @Tags         watchlists
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Watchlist ID"
+// @Success      200  {object}  models.Watchlist
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch watchlist"
+// @Router       /api/v1/watchlists/{id} [get]
+func (h *WatchlistHandler) GetWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	watchlist, err := h.watchlistService.GetWatchlist(middleware.Owner(c), id)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to fetch watchlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// UpdateWatchlist maneja PUT /api/v1/watchlists/:id
+// @Summary      Update watchlist
+// @Description  Update name, notes and/or tickers. Omitted fields are kept; a tickers array replaces the whole list
+// @Tags         watchlists
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string                  true   "Watchlist ID"
+// @Param        watchlist  body    updateWatchlistRequest  true   "Fields to change"
+// @Success      200  {object}  models.Watchlist
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      409  {object}  map[string]interface{}  "Watchlist name already in use"
+// @Failure      500  {object}  map[string]interface{}  "Failed to update watchlist"
+// @Router       /api/v1/watchlists/{id} [put]
+func (h *WatchlistHandler) UpdateWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	var req updateWatchlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid watchlist: malformed JSON body",
+		})
+		return
+	}
+
+	watchlist, err := h.watchlistService.UpdateWatchlist(middleware.Owner(c), id, services.WatchlistUpdate{
+		Name:    req.Name,
+		Notes:   req.Notes,
+		Tickers: req.Tickers,
+	})
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to update watchlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// DeleteWatchlist maneja DELETE /api/v1/watchlists/:id
+// @Summary      Delete watchlist
+// @Description  Delete one of the caller's watchlists and its tickers
+// @Tags         watchlists
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Watchlist ID"
+// @Success      204  "Watchlist deleted"
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to delete watchlist"
+// @Router       /api/v1/watchlists/{id} [delete]
+func (h *WatchlistHandler) DeleteWatchlist(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.watchlistService.DeleteWatchlist(middleware.Owner(c), id); err != nil {
+		writeWatchlistError(c, err, "Failed to delete watchlist")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// AddTickers maneja POST /api/v1/watchlists/:id/tickers
+// @Summary      Add tickers to watchlist
+// @Description  Append tickers to a watchlist; tickers already present are ignored
+// @Tags         watchlists
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                   false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                   false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string                   true   "Watchlist ID"
+// @Param        tickers    body    watchlistTickersRequest  true   "Tickers to add"
+// @Success      200  {object}  models.Watchlist
+// @Failure      400  {object}  map[string]interface{}  "Invalid tickers"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to update watchlist"
+// @Router       /api/v1/watchlists/{id}/tickers [post]
+func (h *WatchlistHandler) AddTickers(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	var req watchlistTickersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid watchlist: tickers are required",
+		})
+		return
+	}
+
+	watchlist, err := h.watchlistService.AddTickers(middleware.Owner(c), id, req.Tickers)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to update watchlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// RemoveTicker maneja DELETE /api/v1/watchlists/:id/tickers/:ticker
+// @Summary      Remove ticker from watchlist
+// @Description  Remove a single ticker from a watchlist
+// @Tags         watchlists
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Watchlist ID"
+// @Param        ticker     path    string  true   "Stock ticker symbol"
+// @Success      200  {object}  models.Watchlist
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist or ticker not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to update watchlist"
+// @Router       /api/v1/watchlists/{id}/tickers/{ticker} [delete]
+func (h *WatchlistHandler) RemoveTicker(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	watchlist, err := h.watchlistService.RemoveTicker(middleware.Owner(c), id, c.Param("ticker"))
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Watchlist or ticker not found",
+		})
+		return
+	}
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to update watchlist")
+		return
+	}
+
+	c.JSON(http.StatusOK, watchlist)
+}
+
+// GetWatchlistStocks maneja GET /api/v1/watchlists/:id/stocks
+// @Summary      Latest stocks of a watchlist
+// @Description  Get the latest analyst record of each ticker in the watchlist, newest first. Tickers without records are omitted
+// @Tags         watchlists
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Watchlist ID"
+// @Success      200  {object}  map[string]interface{}  "Latest stocks"
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch watchlist stocks"
+// @Router       /api/v1/watchlists/{id}/stocks [get]
+func (h *WatchlistHandler) GetWatchlistStocks(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	watchlist, stocks, err := h.watchlistService.GetLatestStocks(middleware.Owner(c), id)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to fetch watchlist stocks")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlist": watchlistSummary(watchlist),
+		"data":      stocks,
+		"total":     len(stocks),
+	})
+}
+
+// GetWatchlistRecommendations maneja GET /api/v1/watchlists/:id/recommendations
+// @Summary      Recommendations for a watchlist
+// @Description  Rank only the tickers of the watchlist with the recommendation model. Accepts the same filters as /recommendations except tickers
+// @Tags         watchlists
+// @Produce      json
+// @Param        X-API-Key           header  string  false  "API key identifying the owner"
+// @Param        X-User-ID           header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id                  path    string  true   "Watchlist ID"
+// @Param        limit               query   int     false  "Number of recommendations (default: 10, max: 50)"
+// @Param        min_score           query   number  false  "Minimum score (0-100)"
+// @Param        min_confidence      query   string  false  "Minimum confidence: low, medium or high"
+// @Param        brokerages          query   string  false  "Comma-separated brokerages to include"
+// @Param        exclude_brokerages  query   string  false  "Comma-separated brokerages to exclude"
+// @Param        actions             query   string  false  "Comma-separated action types to include"
+// @Param        lookback_days       query   int     false  "Lookback window in days (default: 30, max: 365)"
+// @Success      200  {object}  map[string]interface{}  "Recommendations payload"
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID or filters"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to generate recommendations"
+// @Router       /api/v1/watchlists/{id}/recommendations [get]
+func (h *WatchlistHandler) GetWatchlistRecommendations(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	filter, err := parseRecommendationFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid recommendation filters: " + err.Error(),
+		})
+		return
+	}
+
+	watchlist, recommendations, model, err := h.watchlistService.GetRecommendations(middleware.Owner(c), id, filter)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to generate recommendations")
+		return
+	}
+	filter.Tickers = watchlist.Symbols()
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlist":       watchlistSummary(watchlist),
+		"recommendations": recommendations,
+		"count":           len(recommendations),
+		"filters":         recommendationFilterPayload(filter),
+		"model":           model,
+	})
+}
+
+// GetWatchlistConsensus maneja GET /api/v1/watchlists/:id/consensus
+// @Summary      Consensus for a watchlist
+// @Description  Get the price target and rating consensus of each ticker in the watchlist. Tickers without records are omitted
+// @Tags         watchlists
+// @Produce      json
+// @Param        X-API-Key    header  string  false  "API key identifying the owner"
+// @Param        X-User-ID    header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id           path    string  true   "Watchlist ID"
+// @Param        window_days  query   int     false  "Lookback window in days (default: 90, max: 365)"
+// @Success      200  {object}  map[string]interface{}  "Consensus per ticker"
+// @Failure      400  {object}  map[string]interface{}  "Invalid watchlist ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Watchlist not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to compute consensus"
+// @Router       /api/v1/watchlists/{id}/consensus [get]
+func (h *WatchlistHandler) GetWatchlistConsensus(c *gin.Context) {
+	id, ok := watchlistID(c)
+	if !ok {
+		return
+	}
+
+	windowDays, _ := strconv.Atoi(c.DefaultQuery("window_days", "90"))
+	if windowDays > 365 {
+		windowDays = 365
+	}
+	if windowDays < 1 {
+		windowDays = 90
+	}
+
+	watchlist, items, err := h.watchlistService.GetConsensus(middleware.Owner(c), id, windowDays)
+	if err != nil {
+		writeWatchlistError(c, err, "Failed to compute consensus")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"watchlist":   watchlistSummary(watchlist),
+		"data":        items,
+		"total":       len(items),
+		"window_days": windowDays,
+	})
+}
+
+// watchlistID lee el id de la ruta y responde 400 si no es válido
+func watchlistID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid watchlist ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeWatchlistError traduce los errores del servicio de watchlists a respuestas HTTP
+func writeWatchlistError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Watchlist not found",
+		})
+	case errors.Is(err, services.ErrInvalidWatchlist):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	case errors.Is(err, services.ErrWatchlistNameTaken):
+		c.JSON(http.StatusConflict, gin.H{
+			"error": "Watchlist name already in use",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fallback,
+		})
+	}
+}
+
+func watchlistSummary(watchlist *models.Watchlist) gin.H {
+	return gin.H{
+		"id":      strconv.FormatUint(watchlist.ID, 10),
+		"name":    watchlist.Name,
+		"tickers": watchlist.Symbols(),
+	}
+}
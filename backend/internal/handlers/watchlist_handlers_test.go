@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeWatchlistService struct {
+	createFn          func(owner, name, notes string, tickers []string) (*models.Watchlist, error)
+	getFn             func(owner string, id uint64) (*models.Watchlist, error)
+	updateFn          func(owner string, id uint64, update services.WatchlistUpdate) (*models.Watchlist, error)
+	recommendationsFn func(owner string, id uint64, filter services.RecommendationFilter) (*models.Watchlist, []models.StockRecommendation, models.RecommendationModel, error)
+}
+
+func (f *fakeWatchlistService) ListWatchlists(string) ([]models.Watchlist, error) {
+	return []models.Watchlist{}, nil
+}
+func (f *fakeWatchlistService) GetWatchlist(owner string, id uint64) (*models.Watchlist, error) {
+	if f.getFn != nil {
+		return f.getFn(owner, id)
+	}
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeWatchlistService) CreateWatchlist(owner, name, notes string, tickers []string) (*models.Watchlist, error) {
+	return f.createFn(owner, name, notes, tickers)
+}
+func (f *fakeWatchlistService) UpdateWatchlist(owner string, id uint64, update services.WatchlistUpdate) (*models.Watchlist, error) {
+	return f.updateFn(owner, id, update)
+}
+func (f *fakeWatchlistService) AddTickers(owner string, id uint64, _ []string) (*models.Watchlist, error) {
+	return f.GetWatchlist(owner, id)
+}
+func (f *fakeWatchlistService) RemoveTicker(owner string, id uint64, _ string) (*models.Watchlist, error) {
+	return f.GetWatchlist(owner, id)
+}
+func (f *fakeWatchlistService) DeleteWatchlist(owner string, id uint64) error {
+	_, err := f.GetWatchlist(owner, id)
+	return err
+}
+func (f *fakeWatchlistService) GetLatestStocks(owner string, id uint64) (*models.Watchlist, []models.Stock, error) {
+	watchlist, err := f.GetWatchlist(owner, id)
+	return watchlist, []models.Stock{}, err
+}
+func (f *fakeWatchlistService) GetRecommendations(owner string, id uint64, filter services.RecommendationFilter) (*models.Watchlist, []models.StockRecommendation, models.RecommendationModel, error) {
+	return f.recommendationsFn(owner, id, filter)
+}
+func (f *fakeWatchlistService) GetConsensus(owner string, id uint64, _ int) (*models.Watchlist, []models.TickerConsensus, error) {
+	watchlist, err := f.GetWatchlist(owner, id)
+	return watchlist, []models.TickerConsensus{}, err
+}
+
+func newWatchlistRouter(service watchlistService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewWatchlistHandlerWithServices(service)
+	g := r.Group("/watchlists", middleware.RequireOwner())
+	g.POST("", h.CreateWatchlist)
+	g.GET("/:id", h.GetWatchlist)
+	g.PUT("/:id", h.UpdateWatchlist)
+	g.DELETE("/:id", h.DeleteWatchlist)
+	g.GET("/:id/recommendations", h.GetWatchlistRecommendations)
+	return r
+}
+
+func watchlistRequest(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.UserIDHeader, "alice")
+	return req
+}
+
+func TestCreateWatchlist(t *testing.T) {
+	var gotOwner string
+	r := newWatchlistRouter(&fakeWatchlistService{
+		createFn: func(owner, name, notes string, tickers []string) (*models.Watchlist, error) {
+			gotOwner = owner
+			switch name {
+			case "taken":
+				return nil, services.ErrWatchlistNameTaken
+			case "bad":
+				return nil, fmt.Errorf("%w: invalid ticker", services.ErrInvalidWatchlist)
+			}
+			return &models.Watchlist{ID: 9, Name: name, Notes: notes}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodPost, "/watchlists", `{"name":"Tech","notes":"n","tickers":["aapl"]}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if gotOwner != "user:alice" {
+		t.Fatalf("owner = %q, want user:alice", gotOwner)
+	}
+	var body map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body["id"] != "9" {
+		t.Fatalf("id should be serialized as a string: %v", body["id"])
+	}
+
+	for payload, want := range map[string]int{
+		`{"notes":"no name"}`: http.StatusBadRequest,
+		`{"name":"bad"}`:      http.StatusBadRequest,
+		`{"name":"taken"}`:    http.StatusConflict,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, watchlistRequest(http.MethodPost, "/watchlists", payload))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", payload, rec.Code, want)
+		}
+	}
+}
+
+func TestUpdateWatchlist_PassesOnlyProvidedFields(t *testing.T) {
+	var got services.WatchlistUpdate
+	r := newWatchlistRouter(&fakeWatchlistService{
+		updateFn: func(_ string, id uint64, update services.WatchlistUpdate) (*models.Watchlist, error) {
+			got = update
+			return &models.Watchlist{ID: id}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodPut, "/watchlists/9", `{"tickers":[]}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if got.Name != nil || got.Notes != nil || got.Tickers == nil || len(*got.Tickers) != 0 {
+		t.Fatalf("unexpected update: %+v", got)
+	}
+}
+
+func TestWatchlistNotFoundAndInvalidID(t *testing.T) {
+	r := newWatchlistRouter(&fakeWatchlistService{})
+
+	for path, want := range map[string]int{
+		"/watchlists/9":   http.StatusNotFound,
+		"/watchlists/abc": http.StatusBadRequest,
+	} {
+		for _, method := range []string{http.MethodGet, http.MethodDelete} {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, watchlistRequest(method, path, ""))
+			if w.Code != want {
+				t.Fatalf("%s %s: status = %d, want %d", method, path, w.Code, want)
+			}
+		}
+	}
+}
+
+func TestGetWatchlistRecommendations(t *testing.T) {
+	var gotFilter services.RecommendationFilter
+	r := newWatchlistRouter(&fakeWatchlistService{
+		recommendationsFn: func(_ string, id uint64, filter services.RecommendationFilter) (*models.Watchlist, []models.StockRecommendation, models.RecommendationModel, error) {
+			gotFilter = filter
+			watchlist := &models.Watchlist{ID: id, Name: "Tech", Tickers: []models.WatchlistTicker{{Ticker: "AAPL"}}}
+			return watchlist, []models.StockRecommendation{{Score: 80}}, models.RecommendationModel{}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodGet, "/watchlists/9/recommendations?limit=5&min_confidence=medium", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotFilter.Limit != 5 || gotFilter.MinConfidence != models.ConfidenceMedium {
+		t.Fatalf("unexpected filter: %+v", gotFilter)
+	}
+
+	var body struct {
+		Count   int `json:"count"`
+		Filters struct {
+			Tickers []string `json:"tickers"`
+		} `json:"filters"`
+	}
+	_ = json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Count != 1 || len(body.Filters.Tickers) != 1 || body.Filters.Tickers[0] != "AAPL" {
+		t.Fatalf("unexpected payload: %s", w.Body.String())
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, watchlistRequest(http.MethodGet, "/watchlists/9/recommendations?min_confidence=extreme", ""))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
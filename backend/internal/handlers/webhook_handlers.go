@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type webhookService interface {
+	ListWebhooks(owner string) ([]models.Webhook, error)
+	GetWebhook(owner string, id uint64) (*models.Webhook, error)
+	CreateWebhook(owner, url, secret string, eventTypes []models.WebhookEventType, enabled bool) (*models.Webhook, error)
+	UpdateWebhook(owner string, id uint64, update services.WebhookUpdate) (*models.Webhook, error)
+	DeleteWebhook(owner string, id uint64) error
+	ListDeliveries(owner string, id uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error)
+}
+
+type WebhookHandler struct {
+	webhookService webhookService
+}
+
+// createWebhookRequest: secret es opcional (se genera uno) y enabled por defecto es true
+type createWebhookRequest struct {
+	URL        string                    `json:"url" binding:"required"`
+	Secret     string                    `json:"secret"`
+	EventTypes []models.WebhookEventType `json:"event_types" binding:"required"`
+	Enabled    *bool                     `json:"enabled"`
+}
+
+// updateWebhookRequest usa punteros para distinguir campos omitidos de campos vacíos
+type updateWebhookRequest struct {
+	URL        *string                    `json:"url"`
+	Secret     *string                    `json:"secret"`
+	EventTypes *[]models.WebhookEventType `json:"event_types"`
+	Enabled    *bool                      `json:"enabled"`
+}
+
+// NewWebhookHandler crea una nueva instancia del handler de webhooks
+func NewWebhookHandler(webhookService *services.WebhookDispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+func NewWebhookHandlerWithServices(webhookService webhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// ListWebhooks maneja GET /api/v1/webhooks
+// @Summary      List webhooks
+// @Description  List the caller's webhook subscriptions. Secrets are never included
+// @Tags         webhooks
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Success      200  {object}  map[string]interface{}  "Webhooks"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch webhooks"
+// @Router       /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	webhooks, err := h.webhookService.ListWebhooks(middleware.Owner(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "Failed to fetch webhooks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  webhooks,
+		"total": len(webhooks),
+	})
+}
+
+// CreateWebhook maneja POST /api/v1/webhooks
+// @Summary      Create webhook
+// @Description  Subscribe a URL to stock.created, stock.updated, sync.completed and/or recommendation.changed events.
+// @Description  Each event is POSTed as JSON signed with HMAC-SHA256 (X-StockStream-Signature: sha256=hex of timestamp + "." + body)
+// @Description  and retried with exponential backoff until a 2xx response. The secret is generated when omitted and only returned here
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                false  "User identifier (used when no API key is sent)"
+// @Param        webhook    body    createWebhookRequest  true   "Webhook (enabled defaults to true)"
+// @Success      201  {object}  models.Webhook
+// @Failure      400  {object}  map[string]interface{}  "Invalid webhook"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      500  {object}  map[string]interface{}  "Failed to create webhook"
+// @Router       /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req createWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook: url and event_types are required",
+		})
+		return
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	webhook, err := h.webhookService.CreateWebhook(middleware.Owner(c), req.URL, req.Secret, req.EventTypes, enabled)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to create webhook")
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhook maneja GET /api/v1/webhooks/:id
+// @Summary      Get webhook
+// @Description  Get one of the caller's webhooks. The secret is not included
+// @Tags         webhooks
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Webhook ID"
+// @Success      200  {object}  models.Webhook
+// @Failure      400  {object}  map[string]interface{}  "Invalid webhook ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Webhook not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch webhook"
+// @Router       /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	webhook, err := h.webhookService.GetWebhook(middleware.Owner(c), id)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to fetch webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook maneja PUT /api/v1/webhooks/:id
+// @Summary      Update webhook
+// @Description  Update url, secret, event_types and/or enabled. Omitted fields are kept. An empty secret rotates it to a generated one;
+// @Description  the response includes the secret only when it changed
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        X-API-Key  header  string                false  "API key identifying the owner"
+// @Param        X-User-ID  header  string                false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string                true   "Webhook ID"
+// @Param        webhook    body    updateWebhookRequest  true   "Fields to change"
+// @Success      200  {object}  models.Webhook
+// @Failure      400  {object}  map[string]interface{}  "Invalid webhook"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Webhook not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to update webhook"
+// @Router       /api/v1/webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	var req updateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook: malformed JSON body",
+		})
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(middleware.Owner(c), id, services.WebhookUpdate{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Enabled:    req.Enabled,
+	})
+	if err != nil {
+		writeWebhookError(c, err, "Failed to update webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// DeleteWebhook maneja DELETE /api/v1/webhooks/:id
+// @Summary      Delete webhook
+// @Description  Delete one of the caller's webhooks together with its pending deliveries and delivery log
+// @Tags         webhooks
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Webhook ID"
+// @Success      204  "Webhook deleted"
+// @Failure      400  {object}  map[string]interface{}  "Invalid webhook ID"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Webhook not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to delete webhook"
+// @Router       /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(middleware.Owner(c), id); err != nil {
+		writeWebhookError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListDeliveries maneja GET /api/v1/webhooks/:id/deliveries
+// @Summary      List webhook deliveries
+// @Description  Delivery log of a webhook, newest first: payload, status (pending, delivered or failed), attempts,
+// @Description  last response status and error, and the next retry for pending deliveries
+// @Tags         webhooks
+// @Produce      json
+// @Param        X-API-Key  header  string  false  "API key identifying the owner"
+// @Param        X-User-ID  header  string  false  "User identifier (used when no API key is sent)"
+// @Param        id         path    string  true   "Webhook ID"
+// @Param        status     query   string  false  "Delivery status: pending, delivered or failed"
+// @Param        limit      query   int     false  "Number of results (default: 50, max: 200)"
+// @Param        offset     query   int     false  "Offset for pagination (default: 0)"
+// @Success      200  {object}  map[string]interface{}  "Deliveries"
+// @Failure      400  {object}  map[string]interface{}  "Invalid webhook ID or status"
+// @Failure      401  {object}  map[string]interface{}  "Missing owner"
+// @Failure      404  {object}  map[string]interface{}  "Webhook not found"
+// @Failure      500  {object}  map[string]interface{}  "Failed to fetch deliveries"
+// @Router       /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit > 200 {
+		limit = 200
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var status models.WebhookDeliveryStatus
+	if raw := c.Query("status"); raw != "" {
+		parsed, ok := models.ParseWebhookDeliveryStatus(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Invalid status: use pending, delivered or failed",
+			})
+			return
+		}
+		status = parsed
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(middleware.Owner(c), id, status, limit, offset)
+	if err != nil {
+		writeWebhookError(c, err, "Failed to fetch deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   deliveries,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// webhookID lee el id de la ruta y responde 400 si no es válido
+func webhookID(c *gin.Context) (uint64, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "Invalid webhook ID",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// writeWebhookError traduce los errores del dispatcher a respuestas HTTP
+func writeWebhookError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "Webhook not found",
+		})
+	case errors.Is(err, services.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fallback,
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/middleware"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type fakeWebhookService struct {
+	createFn         func(owner, url, secret string, eventTypes []models.WebhookEventType, enabled bool) (*models.Webhook, error)
+	listDeliveriesFn func(owner string, id uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error)
+}
+
+func (f *fakeWebhookService) ListWebhooks(string) ([]models.Webhook, error) {
+	return []models.Webhook{}, nil
+}
+func (f *fakeWebhookService) GetWebhook(string, uint64) (*models.Webhook, error) {
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeWebhookService) CreateWebhook(owner, url, secret string, eventTypes []models.WebhookEventType, enabled bool) (*models.Webhook, error) {
+	return f.createFn(owner, url, secret, eventTypes, enabled)
+}
+func (f *fakeWebhookService) UpdateWebhook(string, uint64, services.WebhookUpdate) (*models.Webhook, error) {
+	return nil, repositories.ErrNotFound
+}
+func (f *fakeWebhookService) DeleteWebhook(string, uint64) error {
+	return nil
+}
+func (f *fakeWebhookService) ListDeliveries(owner string, id uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	return f.listDeliveriesFn(owner, id, status, limit, offset)
+}
+
+func newWebhookRouter(service webhookService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewWebhookHandlerWithServices(service)
+	g := r.Group("/webhooks", middleware.RequireOwner())
+	g.POST("", h.CreateWebhook)
+	g.GET("/:id", h.GetWebhook)
+	g.DELETE("/:id", h.DeleteWebhook)
+	g.GET("/:id/deliveries", h.ListDeliveries)
+	return r
+}
+
+func TestCreateWebhook(t *testing.T) {
+	var gotOwner string
+	var gotEvents []models.WebhookEventType
+	var gotEnabled bool
+	r := newWebhookRouter(&fakeWebhookService{
+		createFn: func(owner, url, secret string, eventTypes []models.WebhookEventType, enabled bool) (*models.Webhook, error) {
+			if url == "ftp://example.com" {
+				return nil, fmt.Errorf("%w: url must be an absolute http or https URL", services.ErrInvalidWebhook)
+			}
+			gotOwner, gotEvents, gotEnabled = owner, eventTypes, enabled
+			return &models.Webhook{ID: 5, URL: url, Secret: "whsec_generated", EventTypes: eventTypes, Enabled: enabled}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodPost, "/webhooks", `{"url":"https://hooks.example.com","event_types":["stock.created","sync.completed"]}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body.String())
+	}
+	if gotOwner != "user:alice" || !gotEnabled || len(gotEvents) != 2 {
+		t.Fatalf("unexpected webhook input: owner=%q enabled=%v events=%v", gotOwner, gotEnabled, gotEvents)
+	}
+
+	for payload, want := range map[string]int{
+		`{"event_types":["stock.created"]}`:                           http.StatusBadRequest,
+		`{"url":"https://hooks.example.com"}`:                         http.StatusBadRequest,
+		`{"url":"ftp://example.com","event_types":["stock.created"]}`: http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, watchlistRequest(http.MethodPost, "/webhooks", payload))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", payload, rec.Code, want)
+		}
+	}
+}
+
+func TestListWebhookDeliveries(t *testing.T) {
+	var gotStatus models.WebhookDeliveryStatus
+	var gotLimit int
+	r := newWebhookRouter(&fakeWebhookService{
+		listDeliveriesFn: func(_ string, id uint64, status models.WebhookDeliveryStatus, limit, _ int) ([]models.WebhookDelivery, int64, error) {
+			if id == 9 {
+				return nil, 0, repositories.ErrNotFound
+			}
+			gotStatus, gotLimit = status, limit
+			return []models.WebhookDelivery{{ID: 1, WebhookID: id, Status: status}}, 1, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, watchlistRequest(http.MethodGet, "/webhooks/5/deliveries?status=Failed&limit=500", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if gotStatus != models.WebhookDeliveryFailed || gotLimit != 200 {
+		t.Fatalf("unexpected query: status=%q limit=%d", gotStatus, gotLimit)
+	}
+
+	for path, want := range map[string]int{
+		"/webhooks/5/deliveries?status=lost": http.StatusBadRequest,
+		"/webhooks/9/deliveries":             http.StatusNotFound,
+		"/webhooks/abc/deliveries":           http.StatusBadRequest,
+		"/webhooks/5":                        http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, watchlistRequest(http.MethodGet, path, ""))
+		if rec.Code != want {
+			t.Fatalf("%s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	wsPingInterval    = 30 * time.Second
+	wsWriteTimeout    = 10 * time.Second
+	wsMaxMessageBytes = 64 << 10
+	wsAllTickers      = "*"
+)
+
+// Tipos de mensaje del protocolo JSON del WebSocket
+const (
+	wsMessageSubscribe     = "subscribe"
+	wsMessageUnsubscribe   = "unsubscribe"
+	wsMessageSubscriptions = "subscriptions"
+	wsMessageEvent         = "event"
+	wsMessagePing          = "ping"
+	wsMessagePong          = "pong"
+	wsMessageError         = "error"
+)
+
+// wsClientMessage es un mensaje del cliente. tickers y recommendations (y sync) indican
+// a qué se suscribe o de qué se da de baja
+type wsClientMessage struct {
+	Type            string   `json:"type"`
+	Tickers         []string `json:"tickers"`
+	Recommendations bool     `json:"recommendations"`
+	Sync            bool     `json:"sync"`
+}
+
+// wsServerMessage es un mensaje event, ping, pong o error del servidor
+type wsServerMessage struct {
+	Type  string        `json:"type"`
+	Event *models.Event `json:"event,omitempty"`
+	Time  *time.Time    `json:"time,omitempty"`
+	Error string        `json:"error,omitempty"`
+}
+
+// wsSubscriptionsMessage confirma un subscribe o unsubscribe con el estado completo de las suscripciones
+type wsSubscriptionsMessage struct {
+	Type            string   `json:"type"`
+	Tickers         []string `json:"tickers"`
+	Recommendations bool     `json:"recommendations"`
+	Sync            bool     `json:"sync"`
+}
+
+// wsSubscriptions son las suscripciones de una conexión; el lector las modifica y el escritor las consulta
+type wsSubscriptions struct {
+	mu              sync.Mutex
+	tickers         map[string]struct{}
+	recommendations bool
+	sync            bool
+}
+
+type WebSocketHandler struct {
+	events       eventSource
+	pingInterval time.Duration
+}
+
+// NewWebSocketHandler crea una nueva instancia del handler del WebSocket de eventos
+func NewWebSocketHandler(bus *services.EventBus) *WebSocketHandler {
+	return &WebSocketHandler{
+		events:       bus,
+		pingInterval: wsPingInterval,
+	}
+}
+
+func NewWebSocketHandlerWithServices(events eventSource) *WebSocketHandler {
+	return &WebSocketHandler{
+		events:       events,
+		pingInterval: wsPingInterval,
+	}
+}
+
+// WebSocket maneja GET /api/v1/ws
+// @Summary      Subscribe to live events (WebSocket)
+// @Description  Upgrades to a WebSocket fed by the same event bus as /stream. Messages are JSON objects with a "type":
+// @Description  the client sends subscribe/unsubscribe with "tickers" (or "*"), "recommendations" and "sync", and ping;
+// @Description  the server answers with subscriptions (current state), event, pong and error, and sends ping every 30 seconds.
+// @Description  A client that sends nothing for two ping intervals, or falls too far behind the event stream, is disconnected
+// @Tags         stream
+// @Success      101  {string}  string  "Switching Protocols"
+// @Failure      400  {object}  map[string]interface{}  "Not a WebSocket upgrade request"
+// @Router       /api/v1/ws [get]
+func (h *WebSocketHandler) WebSocket(c *gin.Context) {
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "WebSocket upgrade required",
+		})
+		return
+	}
+
+	// Sin Handshake no se exige Origin: la API ya acepta cualquier origen (CORS) y no usa cookies
+	websocket.Server{Handler: h.serve}.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *WebSocketHandler) serve(ws *websocket.Conn) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = wsMaxMessageBytes
+
+	sub, _, _ := h.events.Subscribe(0)
+	defer sub.Close()
+
+	subs := &wsSubscriptions{tickers: make(map[string]struct{})}
+	replies := make(chan any, 8)
+	done := make(chan struct{})
+	quit := make(chan struct{})
+	defer close(quit)
+	go h.readMessages(ws, subs, replies, done, quit)
+
+	ping := time.NewTicker(h.pingInterval)
+	defer ping.Stop()
+
+	for {
+		var msg any
+		select {
+		case <-done:
+			return
+		case msg = <-replies:
+		case event, ok := <-sub.Events():
+			if !ok {
+				// El cliente no lee al ritmo de los eventos: se avisa y se cierra en vez de acumularlos
+				h.send(ws, wsServerMessage{Type: wsMessageError, Error: "client too slow, events dropped; reconnect and subscribe again"})
+				return
+			}
+			if !subs.matches(event) {
+				continue
+			}
+			msg = wsServerMessage{Type: wsMessageEvent, Event: &event}
+		case now := <-ping.C:
+			now = now.UTC()
+			msg = wsServerMessage{Type: wsMessagePing, Time: &now}
+		}
+		if err := h.send(ws, msg); err != nil {
+			return
+		}
+	}
+}
+
+// readMessages atiende los mensajes del cliente hasta que se cierre la conexión o deje de responder,
+// y deja las respuestas en replies; quit se cierra cuando el escritor termina
+func (h *WebSocketHandler) readMessages(ws *websocket.Conn, subs *wsSubscriptions, replies chan<- any, done, quit chan struct{}) {
+	defer close(done)
+
+	send := func(msg any) bool {
+		select {
+		case replies <- msg:
+			return true
+		case <-quit:
+			return false
+		}
+	}
+
+	for {
+		// Cualquier mensaje del cliente (incluido pong) cuenta como señal de vida
+		ws.SetReadDeadline(time.Now().Add(2 * h.pingInterval))
+
+		var msg wsClientMessage
+		var reply any
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				return
+			}
+			reply = wsServerMessage{Type: wsMessageError, Error: "Invalid JSON message"}
+		} else {
+			switch msg.Type {
+			case wsMessageSubscribe, wsMessageUnsubscribe:
+				if len(msg.Tickers) == 0 && !msg.Recommendations && !msg.Sync {
+					reply = wsServerMessage{Type: wsMessageError, Error: "Nothing to " + msg.Type + ": set tickers, recommendations or sync"}
+				} else {
+					reply = subs.apply(msg)
+				}
+			case wsMessagePing:
+				now := time.Now().UTC()
+				reply = wsServerMessage{Type: wsMessagePong, Time: &now}
+			case wsMessagePong:
+				continue
+			default:
+				reply = wsServerMessage{Type: wsMessageError, Error: "Unknown message type: " + msg.Type}
+			}
+		}
+		if !send(reply) {
+			return
+		}
+	}
+}
+
+func (h *WebSocketHandler) send(ws *websocket.Conn, msg any) error {
+	ws.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return websocket.JSON.Send(ws, msg)
+}
+
+// apply aplica un subscribe o unsubscribe y devuelve el estado resultante
+func (s *wsSubscriptions) apply(msg wsClientMessage) wsSubscriptionsMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscribe := msg.Type == wsMessageSubscribe
+	for _, ticker := range msg.Tickers {
+		ticker = strings.ToUpper(strings.TrimSpace(ticker))
+		if ticker == "" {
+			continue
+		}
+		if subscribe {
+			s.tickers[ticker] = struct{}{}
+		} else {
+			delete(s.tickers, ticker)
+		}
+	}
+	if msg.Recommendations {
+		s.recommendations = subscribe
+	}
+	if msg.Sync {
+		s.sync = subscribe
+	}
+
+	tickers := make([]string, 0, len(s.tickers))
+	for ticker := range s.tickers {
+		tickers = append(tickers, ticker)
+	}
+	slices.Sort(tickers)
+	return wsSubscriptionsMessage{Type: wsMessageSubscriptions, Tickers: tickers, Recommendations: s.recommendations, Sync: s.sync}
+}
+
+// matches indica si el evento corresponde a alguna suscripción de la conexión
+func (s *wsSubscriptions) matches(event models.Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch event.Type {
+	case models.EventStockCreated, models.EventStockUpdated:
+		_, all := s.tickers[wsAllTickers]
+		_, ticker := s.tickers[event.Ticker]
+		return all || ticker
+	case models.EventRecommendationChanged:
+		return s.recommendations
+	default:
+		return s.sync
+	}
+}
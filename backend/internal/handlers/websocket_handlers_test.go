@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/services"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// wsTestMessage reúne los campos de todos los mensajes del servidor
+type wsTestMessage struct {
+	Type            string        `json:"type"`
+	Event           *models.Event `json:"event"`
+	Tickers         []string      `json:"tickers"`
+	Recommendations bool          `json:"recommendations"`
+	Sync            bool          `json:"sync"`
+	Error           string        `json:"error"`
+}
+
+func newWebSocketServer(t *testing.T, bus *services.EventBus, pingInterval time.Duration) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	h := NewWebSocketHandler(bus)
+	h.pingInterval = pingInterval
+	r.GET("/ws", h.WebSocket)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server) *websocket.Conn {
+	t.Helper()
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", "", server.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func wsExchange(t *testing.T, ws *websocket.Conn, msg any) wsTestMessage {
+	t.Helper()
+	if err := websocket.JSON.Send(ws, msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	return wsReceive(t, ws)
+}
+
+func wsReceive(t *testing.T, ws *websocket.Conn) wsTestMessage {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg wsTestMessage
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	return msg
+}
+
+func TestWebSocket_SubscribeAndUnsubscribe(t *testing.T) {
+	bus := services.NewEventBus(10)
+	ws := dialWebSocket(t, newWebSocketServer(t, bus, time.Hour))
+
+	ack := wsExchange(t, ws, map[string]any{"type": "subscribe", "tickers": []string{"aapl", "NVDA"}, "recommendations": true})
+	if ack.Type != "subscriptions" || strings.Join(ack.Tickers, ",") != "AAPL,NVDA" || !ack.Recommendations || ack.Sync {
+		t.Fatalf("unexpected subscribe ack: %+v", ack)
+	}
+
+	bus.Publish(models.Event{Type: models.EventStockCreated, Ticker: "MSFT"})
+	bus.Publish(models.Event{Type: models.EventSyncCompleted})
+	bus.Publish(models.Event{Type: models.EventStockUpdated, Ticker: "AAPL", Brokerage: "UBS"})
+	bus.Publish(models.Event{Type: models.EventRecommendationChanged, Ticker: "TSLA"})
+
+	if msg := wsReceive(t, ws); msg.Type != "event" || msg.Event.Type != models.EventStockUpdated || msg.Event.Ticker != "AAPL" {
+		t.Fatalf("expected AAPL stock.updated, got %+v", msg)
+	}
+	if msg := wsReceive(t, ws); msg.Type != "event" || msg.Event.Type != models.EventRecommendationChanged || msg.Event.Ticker != "TSLA" {
+		t.Fatalf("expected recommendation.changed, got %+v", msg)
+	}
+
+	ack = wsExchange(t, ws, map[string]any{"type": "unsubscribe", "tickers": []string{"AAPL"}, "recommendations": true})
+	if strings.Join(ack.Tickers, ",") != "NVDA" || ack.Recommendations {
+		t.Fatalf("unexpected unsubscribe ack: %+v", ack)
+	}
+	bus.Publish(models.Event{Type: models.EventStockUpdated, Ticker: "AAPL"})
+	bus.Publish(models.Event{Type: models.EventStockCreated, Ticker: "NVDA"})
+	if msg := wsReceive(t, ws); msg.Event == nil || msg.Event.Ticker != "NVDA" {
+		t.Fatalf("expected only NVDA after unsubscribing AAPL, got %+v", msg)
+	}
+}
+
+func TestWebSocket_PingAndErrors(t *testing.T) {
+	ws := dialWebSocket(t, newWebSocketServer(t, services.NewEventBus(10), time.Hour))
+
+	if msg := wsExchange(t, ws, map[string]string{"type": "ping"}); msg.Type != "pong" {
+		t.Fatalf("expected pong, got %+v", msg)
+	}
+	if msg := wsExchange(t, ws, map[string]string{"type": "shout"}); msg.Type != "error" || !strings.Contains(msg.Error, "shout") {
+		t.Fatalf("expected unknown type error, got %+v", msg)
+	}
+	if msg := wsExchange(t, ws, map[string]string{"type": "subscribe"}); msg.Type != "error" {
+		t.Fatalf("expected error for empty subscribe, got %+v", msg)
+	}
+	if err := websocket.Message.Send(ws, "{not json"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if msg := wsReceive(t, ws); msg.Type != "error" || msg.Error != "Invalid JSON message" {
+		t.Fatalf("expected invalid JSON error, got %+v", msg)
+	}
+}
+
+func TestWebSocket_DisconnectsUnresponsiveClients(t *testing.T) {
+	ws := dialWebSocket(t, newWebSocketServer(t, services.NewEventBus(10), 20*time.Millisecond))
+
+	if msg := wsReceive(t, ws); msg.Type != "ping" {
+		t.Fatalf("expected server ping, got %+v", msg)
+	}
+
+	// Sin responder, el servidor cierra tras dos intervalos de ping
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var msg wsTestMessage
+		if err := websocket.JSON.Receive(ws, &msg); err != nil {
+			if strings.Contains(err.Error(), "timeout") {
+				t.Fatalf("connection was not closed: %v", err)
+			}
+			return
+		}
+	}
+}
+
+func TestWebSocket_RequiresUpgrade(t *testing.T) {
+	server := newWebSocketServer(t, services.NewEventBus(10), time.Hour)
+
+	resp, err := http.Get(server.URL + "/ws")
+	if err != nil {
+		t.Fatalf("GET: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIKeyHeader identifica al cliente por su API key
+	APIKeyHeader = "X-API-Key"
+	// UserIDHeader identifica al usuario mientras no exista autenticación real
+	UserIDHeader = "X-User-ID"
+
+	ownerContextKey = "owner"
+	maxUserIDLength = 128
+)
+
+// RequireOwner resuelve el dueño de los recursos del usuario a partir de la API key
+// (se guarda solo su hash) o, si no llega, de la cabecera X-User-ID
+func RequireOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		owner := resolveOwner(c.Request)
+		if owner == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Missing owner: send an " + APIKeyHeader + " or " + UserIDHeader + " header",
+			})
+			return
+		}
+
+		c.Set(ownerContextKey, owner)
+		c.Next()
+	}
+}
+
+// Owner devuelve el dueño resuelto por RequireOwner, o "" si la ruta no lo usa
+func Owner(c *gin.Context) string {
+	return c.GetString(ownerContextKey)
+}
+
+func resolveOwner(r *http.Request) string {
+	if key := strings.TrimSpace(r.Header.Get(APIKeyHeader)); key != "" {
+		sum := sha256.Sum256([]byte(key))
+		return "key:" + hex.EncodeToString(sum[:])
+	}
+	if user := strings.TrimSpace(r.Header.Get(UserIDHeader)); user != "" && len(user) <= maxUserIDLength {
+		return "user:" + user
+	}
+	return ""
+}
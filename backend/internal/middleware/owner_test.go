@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func ownerRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequireOwner())
+	r.GET("/whoami", func(c *gin.Context) {
+		c.String(http.StatusOK, Owner(c))
+	})
+	return r
+}
+
+func TestRequireOwner_MissingHeaders(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	w := httptest.NewRecorder()
+	ownerRouter().ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestRequireOwner_PrefersHashedAPIKey(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set(APIKeyHeader, "secret-key")
+	req.Header.Set(UserIDHeader, "alice")
+	w := httptest.NewRecorder()
+	ownerRouter().ServeHTTP(w, req)
+
+	owner := w.Body.String()
+	if w.Code != http.StatusOK || !strings.HasPrefix(owner, "key:") {
+		t.Fatalf("status = %d, owner = %q", w.Code, owner)
+	}
+	if strings.Contains(owner, "secret-key") {
+		t.Fatalf("raw API key must not be used as owner: %q", owner)
+	}
+}
+
+func TestRequireOwner_FallsBackToUserHeader(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/whoami", nil)
+	req.Header.Set(UserIDHeader, " alice ")
+	w := httptest.NewRecorder()
+	ownerRouter().ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "user:alice" {
+		t.Fatalf("status = %d, owner = %q", w.Code, w.Body.String())
+	}
+}
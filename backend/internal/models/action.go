@@ -0,0 +1,48 @@
+package models
+
+import "strings"
+
+// ActionEvent es el tipo de evento canónico derivado del texto libre de Action
+type ActionEvent string
+
+const (
+	ActionEventUpgrade      ActionEvent = "upgrade"
+	ActionEventDowngrade    ActionEvent = "downgrade"
+	ActionEventTargetRaise  ActionEvent = "target_raise"
+	ActionEventTargetCut    ActionEvent = "target_cut"
+	ActionEventInitiation   ActionEvent = "initiation"
+	ActionEventReiteration  ActionEvent = "reiteration"
+	ActionEventCoverageDrop ActionEvent = "coverage_drop"
+	// ActionEventOther marca acciones no vacías que ninguna regla reconoce
+	ActionEventOther ActionEvent = "other"
+)
+
+// ActionEvents lista los tipos de evento válidos
+var ActionEvents = []ActionEvent{
+	ActionEventUpgrade,
+	ActionEventDowngrade,
+	ActionEventTargetRaise,
+	ActionEventTargetCut,
+	ActionEventInitiation,
+	ActionEventReiteration,
+	ActionEventCoverageDrop,
+	ActionEventOther,
+}
+
+// ParseActionEvent valida un tipo de evento aceptando guiones o espacios como separador
+func ParseActionEvent(s string) (ActionEvent, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	normalized = strings.NewReplacer("-", "_", " ", "_").Replace(normalized)
+	for _, event := range ActionEvents {
+		if string(event) == normalized {
+			return event, true
+		}
+	}
+	return "", false
+}
+
+// EventTypeCount indica cuántos registros hay de un tipo de evento
+type EventTypeCount struct {
+	EventType ActionEvent `json:"event_type"`
+	Count     int64       `json:"count"`
+}
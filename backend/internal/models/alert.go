@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// AlertConditions son los criterios de una regla de alerta. Todos los criterios
+// indicados deben cumplirse; los vacíos se ignoran
+type AlertConditions struct {
+	// Tickers y WatchlistID restringen el conjunto de tickers; si vienen ambos el ticker debe estar en los dos
+	Tickers     []string `json:"tickers,omitempty"`
+	WatchlistID uint64   `json:"watchlist_id,string,omitempty"`
+	// Brokerages se comparan sin distinguir mayúsculas
+	Brokerages []string          `json:"brokerages,omitempty"`
+	EventTypes []ActionEvent     `json:"event_types,omitempty"`
+	RatingFrom []CanonicalRating `json:"rating_from,omitempty"`
+	RatingTo   []CanonicalRating `json:"rating_to,omitempty"`
+	// Rango inclusivo sobre TargetChangePct; los registros sin cambio de precio objetivo no lo cumplen
+	MinTargetChangePct *float64 `json:"min_target_change_pct,omitempty"`
+	MaxTargetChangePct *float64 `json:"max_target_change_pct,omitempty"`
+}
+
+// IsEmpty indica si la regla no tiene ningún criterio (dispararía con cualquier registro)
+func (c AlertConditions) IsEmpty() bool {
+	return len(c.Tickers) == 0 && c.WatchlistID == 0 && len(c.Brokerages) == 0 && len(c.EventTypes) == 0 &&
+		len(c.RatingFrom) == 0 && len(c.RatingTo) == 0 && c.MinTargetChangePct == nil && c.MaxTargetChangePct == nil
+}
+
+// AlertRule es una regla de un usuario evaluada contra los registros nuevos o modificados de cada sincronización
+type AlertRule struct {
+	ID         uint64          `gorm:"primaryKey" json:"id,string"`
+	Owner      string          `gorm:"index:idx_alert_rules_owner" json:"-"`
+	Name       string          `json:"name"`
+	Conditions AlertConditions `gorm:"serializer:json" json:"conditions"`
+	Enabled    bool            `json:"enabled"`
+	CreatedAt  time.Time       `json:"created_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// Alert es el disparo de una regla por un registro de stock; cada registro dispara una regla una sola vez
+type Alert struct {
+	ID uint64 `gorm:"primaryKey" json:"id,string"`
+	// RuleID y RuleName se conservan aunque la regla se borre después
+	RuleID    uint64    `gorm:"uniqueIndex:ux_alerts_rule_stock" json:"rule_id,string"`
+	RuleName  string    `json:"rule_name"`
+	Owner     string    `json:"-"`
+	StockID   uint64    `gorm:"uniqueIndex:ux_alerts_rule_stock" json:"stock_id,string"`
+	Ticker    string    `json:"ticker"`
+	Summary   string    `json:"summary"`
+	FiredAt   time.Time `json:"fired_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Stock es el registro que disparó la alerta
+	Stock *Stock `gorm:"foreignKey:StockID" json:"stock,omitempty"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Alert) TableName() string {
+	return "alerts"
+}
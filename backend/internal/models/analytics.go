@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// SentimentPoint cuenta los eventos de analistas de un intervalo y su índice de sentimiento neto
+type SentimentPoint struct {
+	Start        time.Time `json:"start"`
+	Total        int64     `json:"total"`
+	Upgrades     int64     `json:"upgrades"`
+	Downgrades   int64     `json:"downgrades"`
+	TargetRaises int64     `json:"target_raises"`
+	TargetCuts   int64     `json:"target_cuts"`
+	Initiations  int64     `json:"initiations"`
+	// NetSentiment va de -100 (solo downgrades y recortes) a 100 (solo upgrades y subidas de target)
+	NetSentiment float64 `gorm:"-" json:"net_sentiment"`
+}
+
+// SentimentSeries es la serie de sentimiento del mercado con su resumen del período
+type SentimentSeries struct {
+	Bucket     TimelineBucket   `json:"bucket"`
+	From       *time.Time       `json:"from"`
+	To         *time.Time       `json:"to"`
+	Brokerages []string         `json:"brokerages"`
+	Tickers    []string         `json:"tickers"`
+	Points     []SentimentPoint `json:"points"`
+	Summary    SentimentPoint   `json:"summary"`
+}
+
+// RatingMode indica si las analíticas de ratings usan el texto crudo o la taxonomía canónica
+type RatingMode string
+
+const (
+	RatingModeCanonical RatingMode = "canonical"
+	RatingModeRaw       RatingMode = "raw"
+)
+
+// ParseRatingMode valida el modo recibido por query
+func ParseRatingMode(s string) (RatingMode, bool) {
+	switch RatingMode(strings.ToLower(strings.TrimSpace(s))) {
+	case RatingModeCanonical:
+		return RatingModeCanonical, true
+	case RatingModeRaw:
+		return RatingModeRaw, true
+	default:
+		return "", false
+	}
+}
+
+// RatingTransition cuenta las llamadas que pasaron de un rating a otro
+type RatingTransition struct {
+	RatingFrom string `json:"rating_from"`
+	RatingTo   string `json:"rating_to"`
+	Count      int64  `json:"count"`
+	// Share es la fracción de las llamadas que partían de RatingFrom que terminaron en RatingTo
+	Share float64 `gorm:"-" json:"share"`
+}
+
+// RatingTransitionMatrix es la matriz de transiciones de rating de un período
+type RatingTransitionMatrix struct {
+	Mode  RatingMode `json:"mode"`
+	From  *time.Time `json:"from"`
+	To    *time.Time `json:"to"`
+	Total int64      `json:"total"`
+	// Ratings son las etiquetas de filas y columnas; Counts[i][j] cuenta Ratings[i] -> Ratings[j]
+	Ratings     []string           `json:"ratings"`
+	Counts      [][]int64          `json:"counts"`
+	Transitions []RatingTransition `json:"transitions"`
+}
+
+// RatingShare cuenta los tickers cuyo rating vigente es Rating
+type RatingShare struct {
+	Rating  string  `json:"rating"`
+	Tickers int64   `json:"tickers"`
+	Share   float64 `gorm:"-" json:"share"`
+}
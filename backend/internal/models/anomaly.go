@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// AnomalyKind es el tipo de actividad inusual detectada
+type AnomalyKind string
+
+const (
+	// AnomalyActivitySpike marca muchas firmas actuando sobre un ticker en pocos días frente a su actividad habitual
+	AnomalyActivitySpike AnomalyKind = "activity_spike"
+	// AnomalyTargetOutlier marca un precio objetivo muy alejado del consenso del resto de firmas
+	AnomalyTargetOutlier AnomalyKind = "target_outlier"
+)
+
+// ParseAnomalyKind valida un tipo de anomalía recibido por query
+func ParseAnomalyKind(s string) (AnomalyKind, bool) {
+	switch AnomalyKind(s) {
+	case AnomalyActivitySpike, AnomalyTargetOutlier:
+		return AnomalyKind(s), true
+	default:
+		return "", false
+	}
+}
+
+// AnomalySeverity gradúa qué tan lejos de lo normal está una anomalía
+type AnomalySeverity string
+
+const (
+	AnomalySeverityLow    AnomalySeverity = "low"
+	AnomalySeverityMedium AnomalySeverity = "medium"
+	AnomalySeverityHigh   AnomalySeverity = "high"
+)
+
+// ParseAnomalySeverity valida una severidad recibida por query
+func ParseAnomalySeverity(s string) (AnomalySeverity, bool) {
+	switch AnomalySeverity(s) {
+	case AnomalySeverityLow, AnomalySeverityMedium, AnomalySeverityHigh:
+		return AnomalySeverity(s), true
+	default:
+		return "", false
+	}
+}
+
+// Anomaly es una actividad inusual de analistas detectada tras una sincronización
+type Anomaly struct {
+	ID uint64 `gorm:"primaryKey" json:"id,string"`
+	// Fingerprint identifica la anomalía para que detectarla de nuevo la actualice en lugar de duplicarla
+	Fingerprint string          `gorm:"uniqueIndex:ux_anomalies_fingerprint" json:"-"`
+	Kind        AnomalyKind     `json:"kind"`
+	Ticker      string          `json:"ticker"`
+	Severity    AnomalySeverity `json:"severity"`
+	Score       float64         `json:"score"`
+	Summary     string          `json:"summary"`
+	WindowStart time.Time       `json:"window_start"`
+	WindowEnd   time.Time       `json:"window_end"`
+	StockIDs    []uint64        `gorm:"serializer:json" json:"-"`
+	DetectedAt  time.Time       `json:"detected_at"`  // primera detección
+	LastSeenAt  time.Time       `json:"last_seen_at"` // detección más reciente
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	// Records son los registros de stocks que respaldan la anomalía
+	Records []Stock `gorm:"-" json:"records"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Anomaly) TableName() string {
+	return "anomalies"
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// BrokerageReliability resume la credibilidad histórica de una casa de bolsa
+type BrokerageReliability struct {
+	Brokerage         string    `gorm:"primaryKey" json:"brokerage"`
+	Score             float64   `json:"score"`
+	Multiplier        float64   `json:"multiplier"`
+	TotalCalls        int       `json:"total_calls"`
+	DirectionalCalls  int       `json:"directional_calls"`
+	ConfirmedCalls    int       `json:"confirmed_calls"`
+	ContradictedCalls int       `json:"contradicted_calls"`
+	Reversals         int       `json:"reversals"`
+	TickersCovered    int       `json:"tickers_covered"`
+	ConfirmationRate  float64   `json:"confirmation_rate"`
+	ReversalRate      float64   `json:"reversal_rate"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (BrokerageReliability) TableName() string {
+	return "brokerage_reliability"
+}
+
+// BrokerageSummary resume la actividad de una casa de bolsa en el historial de stocks
+type BrokerageSummary struct {
+	Brokerage      string    `json:"brokerage"`
+	TotalCalls     int64     `json:"total_calls"`
+	TickersCovered int64     `json:"tickers_covered"`
+	FirstActivity  time.Time `json:"first_activity"`
+	LastActivity   time.Time `json:"last_activity"`
+	Upgrades       int64     `json:"upgrades"`
+	Downgrades     int64     `json:"downgrades"`
+	TargetRaises   int64     `json:"target_raises"`
+	TargetCuts     int64     `json:"target_cuts"`
+	Initiations    int64     `json:"initiations"`
+	// UpgradeDowngradeRatio es upgrades/downgrades; nil si la firma no tiene downgrades
+	UpgradeDowngradeRatio *float64 `gorm:"-" json:"upgrade_downgrade_ratio"`
+}
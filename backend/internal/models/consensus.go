@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// ConsensusCall es la llamada vigente de una casa de bolsa sobre un ticker
+type ConsensusCall struct {
+	Brokerage    string    `json:"brokerage"`
+	Target       float64   `json:"target"`
+	Currency     string    `json:"currency"`
+	Rating       string    `json:"rating"`
+	RatingBucket string    `json:"rating_bucket"`
+	Time         time.Time `json:"time"`
+}
+
+// RatingDistribution cuenta las firmas por bucket de rating
+type RatingDistribution struct {
+	Buy  int `json:"buy"`
+	Hold int `json:"hold"`
+	Sell int `json:"sell"`
+}
+
+// TickerConsensus agrega el precio objetivo y los ratings vigentes de todas las firmas.
+// Las estadísticas de precio solo usan los objetivos en TargetCurrency, la moneda más usada
+type TickerConsensus struct {
+	Ticker             string             `json:"ticker"`
+	WindowDays         int                `json:"window_days"`
+	CoveringFirms      int                `json:"covering_firms"`
+	TargetCount        int                `json:"target_count"`
+	TargetCurrency     string             `json:"target_currency"`
+	MeanTarget         float64            `json:"mean_target"`
+	MedianTarget       float64            `json:"median_target"`
+	HighTarget         float64            `json:"high_target"`
+	LowTarget          float64            `json:"low_target"`
+	RatingDistribution RatingDistribution `json:"rating_distribution"`
+	Calls              []ConsensusCall    `json:"calls"`
+}
@@ -0,0 +1,74 @@
+package models
+
+import "time"
+
+// EventType es el tipo de un evento del bus interno
+type EventType string
+
+const (
+	EventStockCreated          EventType = "stock.created"
+	EventStockUpdated          EventType = "stock.updated"
+	EventSyncStarted           EventType = "sync.started"
+	EventSyncProgress          EventType = "sync.progress"
+	EventSyncCompleted         EventType = "sync.completed"
+	EventSyncFailed            EventType = "sync.failed"
+	EventRecommendationChanged EventType = "recommendation.changed"
+)
+
+// Event es un evento publicado en el bus interno mientras avanza una sincronización.
+// ID crece de forma monótona y sirve como Last-Event-ID para reanudar un stream
+type Event struct {
+	ID   uint64    `json:"id,string"`
+	Type EventType `json:"type"`
+	Time time.Time `json:"time"`
+	// Ticker y Brokerage permiten filtrar los eventos de stocks; están vacíos en los de sincronización
+	Ticker    string `json:"ticker,omitempty"`
+	Brokerage string `json:"brokerage,omitempty"`
+	Data      any    `json:"data"`
+}
+
+// SyncProgress indica cuántos registros de la API lleva procesados la sincronización en curso
+type SyncProgress struct {
+	Processed int `json:"processed"`
+	Total     int `json:"total"`
+	Created   int `json:"created"`
+	Updated   int `json:"updated"`
+}
+
+// SyncFailure describe por qué no pudo completarse una sincronización
+type SyncFailure struct {
+	Error string `json:"error"`
+}
+
+// SyncSummary describe una sincronización terminada
+type SyncSummary struct {
+	Created    int       `json:"created"`
+	Updated    int       `json:"updated"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// RecommendationChangeKind indica qué cambió en el ranking de recomendaciones
+type RecommendationChangeKind string
+
+const (
+	// RecommendationEntered: el ticker entró al ranking
+	RecommendationEntered RecommendationChangeKind = "entered"
+	// RecommendationLeft: el ticker salió del ranking
+	RecommendationLeft RecommendationChangeKind = "left"
+	// RecommendationConfidenceChanged: el ticker sigue en el ranking con otra confianza
+	RecommendationConfidenceChanged RecommendationChangeKind = "confidence"
+)
+
+// RecommendationChange es el cambio de un ticker en el top de recomendaciones tras una sincronización.
+// Rank y PreviousRank empiezan en 1; se omiten cuando el ticker no estaba o ya no está en el ranking
+type RecommendationChange struct {
+	Ticker             string                   `json:"ticker"`
+	Change             RecommendationChangeKind `json:"change"`
+	Rank               int                      `json:"rank,omitempty"`
+	PreviousRank       int                      `json:"previous_rank,omitempty"`
+	Score              float64                  `json:"score"`
+	Confidence         *ConfidenceLevel         `json:"confidence,omitempty"`
+	PreviousConfidence *ConfidenceLevel         `json:"previous_confidence,omitempty"`
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// CanonicalRating es la taxonomía normalizada a la que se mapean los ratings crudos
+type CanonicalRating string
+
+const (
+	RatingStrongBuy  CanonicalRating = "strong_buy"
+	RatingBuy        CanonicalRating = "buy"
+	RatingHold       CanonicalRating = "hold"
+	RatingSell       CanonicalRating = "sell"
+	RatingStrongSell CanonicalRating = "strong_sell"
+	// RatingUnmapped marca ratings crudos no vacíos que ninguna regla reconoce
+	RatingUnmapped CanonicalRating = "unmapped"
+)
+
+// CanonicalRatings lista los valores válidos de la taxonomía, de más alcista a más bajista
+var CanonicalRatings = []CanonicalRating{
+	RatingStrongBuy,
+	RatingBuy,
+	RatingHold,
+	RatingSell,
+	RatingStrongSell,
+}
+
+// ParseCanonicalRating valida un rating canónico aceptando guiones o espacios como separador
+func ParseCanonicalRating(s string) (CanonicalRating, bool) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	normalized = strings.NewReplacer("-", "_", " ", "_").Replace(normalized)
+	for _, rating := range CanonicalRatings {
+		if string(rating) == normalized {
+			return rating, true
+		}
+	}
+	return "", false
+}
+
+// DefaultScore devuelve el score numérico (escala 0-5) asociado a un rating canónico
+func (r CanonicalRating) DefaultScore() float64 {
+	switch r {
+	case RatingStrongBuy:
+		return 5.0
+	case RatingBuy:
+		return 4.0
+	case RatingHold:
+		return 2.0
+	case RatingSell:
+		return 0.5
+	case RatingStrongSell:
+		return 0.0
+	default:
+		return 2.0
+	}
+}
+
+// RatingMapping es una regla administrada que asigna un rating crudo a la taxonomía canónica
+type RatingMapping struct {
+	RawRating string          `gorm:"primaryKey" json:"raw_rating"`
+	Canonical CanonicalRating `json:"canonical"`
+	Score     float64         `json:"score"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (RatingMapping) TableName() string {
+	return "rating_mappings"
+}
+
+// UnmappedRating agrupa un rating crudo que no pudo normalizarse y cuántas filas lo usan
+type UnmappedRating struct {
+	RawRating string `json:"raw_rating"`
+	Count     int64  `json:"count"`
+}
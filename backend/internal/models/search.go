@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Tipos de coincidencia de la búsqueda, de mayor a menor prioridad
+const (
+	SearchMatchTickerExact    = "ticker_exact"
+	SearchMatchTickerPrefix   = "ticker_prefix"
+	SearchMatchCompany        = "company"
+	SearchMatchCompanyFuzzy   = "company_fuzzy"
+	SearchMatchBrokerage      = "brokerage"
+	SearchMatchBrokerageFuzzy = "brokerage_fuzzy"
+)
+
+// SearchCandidate agrega los registros de un ticker por compañía y brokerage
+type SearchCandidate struct {
+	Ticker    string `json:"ticker"`
+	Company   string `json:"company"`
+	Brokerage string `json:"brokerage"`
+	Count     int64  `json:"count"`
+}
+
+// SearchHighlight marca el tramo del campo que coincidió (posiciones en runas, fin exclusivo)
+type SearchHighlight struct {
+	Field string `json:"field"`
+	Value string `json:"value"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// SearchHit es un ticker encontrado por la búsqueda con su registro más reciente
+type SearchHit struct {
+	Ticker     string            `json:"ticker"`
+	Company    string            `json:"company"`
+	Score      float64           `json:"score"`
+	MatchType  string            `json:"match_type"`
+	Records    int64             `json:"records"`
+	LatestTime time.Time         `json:"latest_time"`
+	Highlights []SearchHighlight `json:"highlights"`
+	Latest     Stock             `json:"-"`
+}
@@ -40,17 +40,26 @@ func (c *ConfidenceLevel) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	level, ok := ParseConfidenceLevel(s)
+	if !ok {
+		level = ConfidenceLow // valor por defecto
+	}
+	*c = level
+	return nil
+}
+
+// ParseConfidenceLevel convierte "low", "medium" o "high" en un ConfidenceLevel
+func ParseConfidenceLevel(s string) (ConfidenceLevel, bool) {
 	switch s {
 	case "low":
-		*c = ConfidenceLow
+		return ConfidenceLow, true
 	case "medium":
-		*c = ConfidenceMedium
+		return ConfidenceMedium, true
 	case "high":
-		*c = ConfidenceHigh
+		return ConfidenceHigh, true
 	default:
-		*c = ConfidenceLow // valor por defecto
+		return ConfidenceLow, false
 	}
-	return nil
 }
 
 type Stock struct {
@@ -66,6 +75,21 @@ type Stock struct {
 	Time       time.Time `gorm:"index" json:"time"`
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Ratings normalizados al ingerir (ver migración 0003)
+	RatingFromCanonical CanonicalRating `json:"rating_from_canonical"`
+	RatingToCanonical   CanonicalRating `gorm:"index" json:"rating_to_canonical"`
+	RatingFromScore     float64         `json:"rating_from_score"`
+	RatingToScore       float64         `json:"rating_to_score"`
+
+	// Tipo de evento derivado de Action al ingerir (ver migración 0004)
+	EventType ActionEvent `gorm:"index" json:"event_type"`
+
+	// Precios objetivo numéricos derivados al ingerir (ver migración 0005); nil si no se pudieron interpretar
+	TargetFromValue *float64 `json:"target_from_value"`
+	TargetToValue   *float64 `json:"target_to_value"`
+	TargetCurrency  string   `json:"target_currency"`
+	TargetChangePct *float64 `json:"target_change_pct"`
 }
 
 type APIResponse struct {
@@ -74,8 +98,77 @@ type APIResponse struct {
 }
 
 type StockRecommendation struct {
-	Stock      Stock           `json:"stock"`
-	Score      float64         `json:"score"`
-	Reason     string          `json:"reason"`
-	Confidence ConfidenceLevel `json:"confidence"`
+	Stock      Stock                  `json:"stock"`
+	Score      float64                `json:"score"`
+	Reason     string                 `json:"reason"`
+	Confidence ConfidenceLevel        `json:"confidence"`
+	Factors    []RecommendationFactor `json:"factors"`
+}
+
+// RecommendationFactor describe el aporte de una feature al score de una recomendación
+type RecommendationFactor struct {
+	Label        string  `json:"label"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Explanation  string  `json:"explanation"`
+}
+
+// ConsensusItem es un registro del historial usado para calcular el consenso
+type ConsensusItem struct {
+	Stock           Stock   `json:"stock"`
+	RecencyWeight   float64 `json:"recency_weight"`
+	BrokerageWeight float64 `json:"brokerage_weight"`
+	Signal          float64 `json:"signal"`
+}
+
+// RecommendationExplanation expone el desglose completo del score de un ticker
+type RecommendationExplanation struct {
+	Ticker              string                 `json:"ticker"`
+	Stock               Stock                  `json:"stock"`
+	Score               float64                `json:"score"`
+	RawScore            float64                `json:"raw_score"`
+	Confidence          ConfidenceLevel        `json:"confidence"`
+	ConfidenceRule      string                 `json:"confidence_rule"`
+	Reason              string                 `json:"reason"`
+	DataQuality         float64                `json:"data_quality"`
+	QualityFactor       float64                `json:"quality_factor"`
+	BrokerageMultiplier float64                `json:"brokerage_multiplier"`
+	Factors             []RecommendationFactor `json:"factors"`
+	History             []ConsensusItem        `json:"history"`
+	HistoryCount        int                    `json:"history_count"`
+}
+
+// FeatureWeight es el peso de una feature dentro del modelo de scoring
+type FeatureWeight struct {
+	Label  string  `json:"label"`
+	Weight float64 `json:"weight"`
+}
+
+// ConfidenceRule es el umbral mínimo que debe cumplir una recomendación para un nivel de confianza
+type ConfidenceRule struct {
+	Level          ConfidenceLevel `json:"level"`
+	MinScore       float64         `json:"min_score"`
+	MinHistory     int             `json:"min_history"`
+	MinDataQuality float64         `json:"min_data_quality"`
+}
+
+// ModelThresholds agrupa los umbrales de corte del modelo
+type ModelThresholds struct {
+	MinimumAcceptedScore float64          `json:"minimum_accepted_score"`
+	MaximumBearishScore  float64          `json:"maximum_bearish_score"`
+	Confidence           []ConfidenceRule `json:"confidence"`
+}
+
+// RecommendationModel describe el modelo que produjo un ranking y los datos que evaluó
+type RecommendationModel struct {
+	Name               string          `json:"name"`
+	Version            string          `json:"version"`
+	Weights            []FeatureWeight `json:"weights"`
+	Thresholds         ModelThresholds `json:"thresholds"`
+	BrokerageWeighting bool            `json:"brokerage_weighting"`
+	LookbackDays       int             `json:"lookback_days"`
+	WindowStart        time.Time       `json:"window_start"`
+	RecordsEvaluated   int             `json:"records_evaluated"`
+	TickersEvaluated   int             `json:"tickers_evaluated"`
 }
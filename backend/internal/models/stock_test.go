@@ -72,3 +72,33 @@ func TestConfidenceLevelUnmarshalJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestParseConfidenceLevel(t *testing.T) {
+	if level, ok := ParseConfidenceLevel("high"); !ok || level != ConfidenceHigh {
+		t.Fatalf("ParseConfidenceLevel(high) = %v, %v", level, ok)
+	}
+	if _, ok := ParseConfidenceLevel("extreme"); ok {
+		t.Fatalf("expected unknown level to be rejected")
+	}
+}
+
+func TestParseCanonicalRating(t *testing.T) {
+	tests := []struct {
+		in   string
+		want CanonicalRating
+		ok   bool
+	}{
+		{in: "strong_buy", want: RatingStrongBuy, ok: true},
+		{in: "Strong-Sell", want: RatingStrongSell, ok: true},
+		{in: " hold ", want: RatingHold, ok: true},
+		{in: "unmapped", ok: false},
+		{in: "moon", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseCanonicalRating(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Fatalf("ParseCanonicalRating(%q) = %q,%v want %q,%v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// TickerSuggestion es una entrada del índice de autocompletado de tickers
+type TickerSuggestion struct {
+	Ticker   string    `json:"ticker"`
+	Company  string    `json:"company"`
+	Records  int64     `json:"records"`
+	LastSeen time.Time `json:"last_seen"`
+	Match    string    `json:"match,omitempty"`
+}
+
+// Company es la entidad canónica de una compañía; varios tickers pueden compartirla
+type Company struct {
+	ID             uint64    `gorm:"primaryKey" json:"id"`
+	Name           string    `json:"name"`
+	NormalizedName string    `gorm:"uniqueIndex:ux_companies_normalized_name" json:"-"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Company) TableName() string {
+	return "companies"
+}
+
+// Ticker resume la actividad de un ticker a partir de sus registros en stocks
+type Ticker struct {
+	Ticker                string          `gorm:"primaryKey" json:"ticker"`
+	CompanyID             *uint64         `json:"company_id"`
+	Company               *Company        `gorm:"foreignKey:CompanyID" json:"company,omitempty"`
+	FirstSeen             time.Time       `json:"first_seen"`
+	LastSeen              time.Time       `json:"last_seen"`
+	RecordCount           int64           `json:"record_count"`
+	LatestStockID         uint64          `json:"latest_stock_id"`
+	LatestRating          string          `json:"latest_rating"`
+	LatestRatingCanonical CanonicalRating `json:"latest_rating_canonical"`
+	LatestBrokerage       string          `json:"latest_brokerage"`
+	CreatedAt             time.Time       `json:"created_at"`
+	UpdatedAt             time.Time       `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Ticker) TableName() string {
+	return "tickers"
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// TimelineBucket es la granularidad de la agregación de eventos del timeline
+type TimelineBucket string
+
+const (
+	TimelineBucketDay   TimelineBucket = "day"
+	TimelineBucketWeek  TimelineBucket = "week"
+	TimelineBucketMonth TimelineBucket = "month"
+)
+
+// ParseTimelineBucket valida la granularidad recibida por query
+func ParseTimelineBucket(s string) (TimelineBucket, bool) {
+	switch TimelineBucket(s) {
+	case TimelineBucketDay, TimelineBucketWeek, TimelineBucketMonth:
+		return TimelineBucket(s), true
+	default:
+		return "", false
+	}
+}
+
+// TargetStep es un punto de la serie escalonada de precio objetivo de una casa de bolsa
+type TargetStep struct {
+	Time           time.Time   `json:"time"`
+	Target         float64     `json:"target"`
+	PreviousTarget *float64    `json:"previous_target"`
+	Currency       string      `json:"currency"`
+	EventType      ActionEvent `json:"event_type"`
+	StockID        uint64      `json:"stock_id,string"`
+}
+
+// BrokerageTargetSeries agrupa los pasos de precio objetivo de una casa de bolsa en orden cronológico
+type BrokerageTargetSeries struct {
+	Brokerage string       `json:"brokerage"`
+	Steps     []TargetStep `json:"steps"`
+}
+
+// RatingChange es un cambio de estado del rating emitido por una casa de bolsa
+type RatingChange struct {
+	Time                time.Time       `json:"time"`
+	Brokerage           string          `json:"brokerage"`
+	RatingFrom          string          `json:"rating_from"`
+	RatingTo            string          `json:"rating_to"`
+	RatingFromCanonical CanonicalRating `json:"rating_from_canonical"`
+	RatingToCanonical   CanonicalRating `json:"rating_to_canonical"`
+	RatingToScore       float64         `json:"rating_to_score"`
+	EventType           ActionEvent     `json:"event_type"`
+	StockID             uint64          `json:"stock_id,string"`
+}
+
+// TimelineEvents cuenta los eventos de un intervalo del timeline
+type TimelineEvents struct {
+	Start       time.Time           `json:"start"`
+	Total       int                 `json:"total"`
+	ByEventType map[ActionEvent]int `json:"by_event_type"`
+}
+
+// TickerTimeline reúne las series de un ticker listas para graficar
+type TickerTimeline struct {
+	Ticker        string                  `json:"ticker"`
+	Company       string                  `json:"company"`
+	From          *time.Time              `json:"from"`
+	To            *time.Time              `json:"to"`
+	Bucket        TimelineBucket          `json:"bucket"`
+	Records       int                     `json:"records"`
+	Targets       []BrokerageTargetSeries `json:"targets"`
+	RatingChanges []RatingChange          `json:"rating_changes"`
+	Events        []TimelineEvents        `json:"events"`
+}
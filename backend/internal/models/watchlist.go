@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Watchlist es una lista de tickers seguida por un usuario
+type Watchlist struct {
+	ID uint64 `gorm:"primaryKey" json:"id,string"`
+	// Owner identifica al dueño (API key hasheada o cabecera de usuario); nunca se expone
+	Owner     string            `gorm:"uniqueIndex:ux_watchlists_owner_name" json:"-"`
+	Name      string            `gorm:"uniqueIndex:ux_watchlists_owner_name" json:"name"`
+	Notes     string            `json:"notes"`
+	Tickers   []WatchlistTicker `gorm:"foreignKey:WatchlistID" json:"tickers"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Watchlist) TableName() string {
+	return "watchlists"
+}
+
+// Symbols devuelve los tickers de la watchlist en el orden en que se agregaron
+func (w Watchlist) Symbols() []string {
+	symbols := make([]string, 0, len(w.Tickers))
+	for _, ticker := range w.Tickers {
+		symbols = append(symbols, ticker.Ticker)
+	}
+	return symbols
+}
+
+// WatchlistTicker es un ticker seguido dentro de una watchlist
+type WatchlistTicker struct {
+	WatchlistID uint64    `gorm:"primaryKey" json:"-"`
+	Ticker      string    `gorm:"primaryKey" json:"ticker"`
+	AddedAt     time.Time `json:"added_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (WatchlistTicker) TableName() string {
+	return "watchlist_tickers"
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// WebhookEventType es el tipo de evento que puede notificarse a un webhook
+type WebhookEventType string
+
+const (
+	WebhookEventStockCreated          = WebhookEventType(EventStockCreated)
+	WebhookEventStockUpdated          = WebhookEventType(EventStockUpdated)
+	WebhookEventSyncCompleted         = WebhookEventType(EventSyncCompleted)
+	WebhookEventRecommendationChanged = WebhookEventType(EventRecommendationChanged)
+)
+
+// WebhookEventTypes lista los eventos soportados en el orden en que se documentan
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventStockCreated,
+	WebhookEventStockUpdated,
+	WebhookEventSyncCompleted,
+	WebhookEventRecommendationChanged,
+}
+
+// ParseWebhookEventType valida un tipo de evento sin distinguir mayúsculas
+func ParseWebhookEventType(s string) (WebhookEventType, bool) {
+	normalized := WebhookEventType(strings.ToLower(strings.TrimSpace(s)))
+	if slices.Contains(WebhookEventTypes, normalized) {
+		return normalized, true
+	}
+	return "", false
+}
+
+// Webhook es una suscripción de un usuario a eventos enviados por POST a su URL
+type Webhook struct {
+	ID    uint64 `gorm:"primaryKey" json:"id,string"`
+	Owner string `gorm:"index:idx_webhooks_owner" json:"-"`
+	URL   string `json:"url"`
+	// Secret firma los payloads; solo se devuelve al crear el webhook o al cambiarlo
+	Secret     string             `json:"secret,omitempty"`
+	EventTypes []WebhookEventType `gorm:"serializer:json" json:"event_types"`
+	Enabled    bool               `json:"enabled"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribes indica si el webhook está activo y suscrito al tipo de evento
+func (w Webhook) Subscribes(eventType WebhookEventType) bool {
+	return w.Enabled && slices.Contains(w.EventTypes, eventType)
+}
+
+// WebhookEvent es el cuerpo JSON que se envía a cada webhook
+type WebhookEvent struct {
+	ID        string           `json:"id"`
+	Type      WebhookEventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      any              `json:"data"`
+}
+
+// WebhookDeliveryStatus es el estado de una entrega en la cola
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryPending espera su primer intento o un reintento en NextAttemptAt
+	WebhookDeliveryPending WebhookDeliveryStatus = "pending"
+	// WebhookDeliveryDelivered recibió una respuesta 2xx
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryFailed agotó los reintentos o el webhook se desactivó
+	WebhookDeliveryFailed WebhookDeliveryStatus = "failed"
+)
+
+// ParseWebhookDeliveryStatus valida un estado de entrega
+func ParseWebhookDeliveryStatus(s string) (WebhookDeliveryStatus, bool) {
+	switch status := WebhookDeliveryStatus(strings.ToLower(strings.TrimSpace(s))); status {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryFailed:
+		return status, true
+	default:
+		return "", false
+	}
+}
+
+// WebhookDelivery es un evento encolado para un webhook junto con el resultado de sus intentos
+type WebhookDelivery struct {
+	ID        uint64           `gorm:"primaryKey" json:"id,string"`
+	WebhookID uint64           `gorm:"index:idx_webhook_deliveries_webhook" json:"webhook_id,string"`
+	EventID   string           `json:"event_id"`
+	EventType WebhookEventType `json:"event_type"`
+	// Payload es el cuerpo exacto que se firma y se envía
+	Payload        string                `json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"index:idx_webhook_deliveries_due" json:"status"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `gorm:"index:idx_webhook_deliveries_due" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at"`
+	ResponseStatus int                   `json:"response_status"`
+	LastError      string                `json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+
+	// Webhook se carga al tomar entregas pendientes de la cola
+	Webhook *Webhook `gorm:"foreignKey:WebhookID" json:"-"`
+}
+
+// TableName fija el nombre de la tabla creada por la migración
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
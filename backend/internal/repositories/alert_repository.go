@@ -0,0 +1,34 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+// AlertFilter narrows ListAlerts; zero values other than Owner are ignored.
+type AlertFilter struct {
+	Owner  string
+	RuleID uint64
+	Ticker string
+	Since  *time.Time
+}
+
+// AlertRepository abstracts persistence for alert rules and the alerts they fire.
+// Rule lookups are scoped by owner; a rule of another owner behaves as missing (ErrNotFound).
+type AlertRepository interface {
+	CreateRule(rule *models.AlertRule) error
+	// ListRules returns the owner's rules by name.
+	ListRules(owner string) ([]models.AlertRule, error)
+	// ListEnabledRules returns the enabled rules of every owner, as evaluated after each sync.
+	ListEnabledRules() ([]models.AlertRule, error)
+	FindRule(owner string, id uint64) (*models.AlertRule, error)
+	// UpdateRule saves name, conditions and enabled.
+	UpdateRule(rule *models.AlertRule) error
+	DeleteRule(owner string, id uint64) error
+
+	// SaveAlerts inserts the alerts, skipping (rule, stock) pairs that already fired, and returns how many were new.
+	SaveAlerts(alerts []models.Alert) (int64, error)
+	// ListAlerts returns the newest alerts first, with the triggering stock loaded.
+	ListAlerts(filter AlertFilter, limit, offset int) ([]models.Alert, int64, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import "github.com/Hitomiblood/StockStream/internal/models"
+
+// AnomalyFilter narrows ListAnomalies; zero values are ignored.
+type AnomalyFilter struct {
+	Kind     models.AnomalyKind
+	Severity models.AnomalySeverity
+	Ticker   string
+}
+
+// AnomalyRepository abstracts persistence for detected anomalies.
+type AnomalyRepository interface {
+	// SaveAnomaly upserts by Fingerprint so re-detecting an anomaly refreshes it instead of duplicating it.
+	SaveAnomaly(anomaly *models.Anomaly) error
+	// ListAnomalies returns the newest anomalies first, with their supporting stock records loaded.
+	ListAnomalies(filter AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error)
+}
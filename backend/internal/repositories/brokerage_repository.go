@@ -0,0 +1,9 @@
+package repositories
+
+import "github.com/Hitomiblood/StockStream/internal/models"
+
+// BrokerageReliabilityRepository abstracts persistence for the learned brokerage credibility table.
+type BrokerageReliabilityRepository interface {
+	ListReliability() ([]models.BrokerageReliability, error)
+	ReplaceReliability(items []models.BrokerageReliability) error
+}
@@ -0,0 +1,111 @@
+package gormrepo
+
+import (
+	"errors"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AlertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (r *AlertRepository) CreateRule(rule *models.AlertRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *AlertRepository) ListRules(owner string) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	if err := r.db.Where("owner = ?", owner).Order("name, id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AlertRepository) ListEnabledRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	if err := r.db.Where("enabled = ?", true).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AlertRepository) FindRule(owner string, id uint64) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	if err := r.db.Where("owner = ? AND id = ?", owner, id).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *AlertRepository) UpdateRule(rule *models.AlertRule) error {
+	result := r.db.Model(&models.AlertRule{}).
+		Where("owner = ? AND id = ?", rule.Owner, rule.ID).
+		Select("name", "conditions", "enabled", "updated_at").
+		Updates(rule)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AlertRepository) DeleteRule(owner string, id uint64) error {
+	result := r.db.Where("owner = ? AND id = ?", owner, id).Delete(&models.AlertRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *AlertRepository) SaveAlerts(alerts []models.Alert) (int64, error) {
+	if len(alerts) == 0 {
+		return 0, nil
+	}
+	result := r.db.Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "rule_id"}, {Name: "stock_id"}},
+			DoNothing: true,
+		}).
+		CreateInBatches(alerts, 200)
+	return result.RowsAffected, result.Error
+}
+
+func (r *AlertRepository) ListAlerts(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error) {
+	q := r.db.Model(&models.Alert{}).Where("owner = ?", filter.Owner)
+	if filter.RuleID != 0 {
+		q = q.Where("rule_id = ?", filter.RuleID)
+	}
+	if filter.Ticker != "" {
+		q = q.Where("ticker = ?", filter.Ticker)
+	}
+	if filter.Since != nil {
+		q = q.Where("fired_at >= ?", *filter.Since)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var alerts []models.Alert
+	if err := q.Preload("Stock").Order("fired_at DESC, id DESC").Limit(limit).Offset(offset).Find(&alerts).Error; err != nil {
+		return nil, 0, err
+	}
+	return alerts, total, nil
+}
@@ -0,0 +1,113 @@
+package gormrepo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedAlertRepo(t *testing.T) (*AlertRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewAlertRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestSaveAlerts_SkipsAlreadyFiredPairs(t *testing.T) {
+	repo, mock, cleanup := newMockedAlertRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "alerts"`) + `.*ON CONFLICT \("rule_id","stock_id"\) DO NOTHING RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	saved, err := repo.SaveAlerts([]models.Alert{
+		{RuleID: 3, RuleName: "Downgrades", Owner: "user:alice", StockID: 10, Ticker: "AAPL", FiredAt: time.Now()},
+		{RuleID: 3, RuleName: "Downgrades", Owner: "user:alice", StockID: 11, Ticker: "MSFT", FiredAt: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("SaveAlerts error: %v", err)
+	}
+	if saved != 1 {
+		t.Fatalf("saved = %d, want 1", saved)
+	}
+
+	if saved, err := repo.SaveAlerts(nil); saved != 0 || err != nil {
+		t.Fatalf("empty batch should be a no-op, got %d, %v", saved, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUpdateRule_ScopedByOwner(t *testing.T) {
+	repo, mock, cleanup := newMockedAlertRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "alert_rules" SET "name"=$1,"conditions"=$2,"enabled"=$3,"updated_at"=$4 WHERE owner = $5 AND id = $6`)).
+		WithArgs("Goldman raises", `{"brokerages":["Goldman Sachs"]}`, false, sqlmock.AnyArg(), "user:bob", 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	rule := &models.AlertRule{
+		ID:         3,
+		Owner:      "user:bob",
+		Name:       "Goldman raises",
+		Conditions: models.AlertConditions{Brokerages: []string{"Goldman Sachs"}},
+		UpdatedAt:  time.Now(),
+	}
+	if err := repo.UpdateRule(rule); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListAlerts_FiltersAndPreloadsStock(t *testing.T) {
+	repo, mock, cleanup := newMockedAlertRepo(t)
+	defer cleanup()
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "alerts" WHERE owner = $1 AND rule_id = $2 AND ticker = $3 AND fired_at >= $4`)).
+		WithArgs("user:alice", 3, "AAPL", since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "alerts" WHERE owner = $1 AND rule_id = $2 AND ticker = $3 AND fired_at >= $4 ORDER BY fired_at DESC, id DESC LIMIT $5`)).
+		WithArgs("user:alice", 3, "AAPL", since, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "rule_id", "stock_id", "ticker"}).AddRow(1, 3, 10, "AAPL"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE "stocks"."id" = $1`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticker", "brokerage"}).AddRow(10, "AAPL", "UBS"))
+
+	alerts, total, err := repo.ListAlerts(repositories.AlertFilter{Owner: "user:alice", RuleID: 3, Ticker: "AAPL", Since: &since}, 20, 0)
+	if err != nil || total != 1 || len(alerts) != 1 {
+		t.Fatalf("ListAlerts total=%d err=%v alerts=%+v", total, err, alerts)
+	}
+	if alerts[0].Stock == nil || alerts[0].Stock.Brokerage != "UBS" {
+		t.Fatalf("stock not preloaded: %+v", alerts[0])
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package gormrepo
+
+import (
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AnomalyRepository struct {
+	db *gorm.DB
+}
+
+func NewAnomalyRepository(db *gorm.DB) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+// SaveAnomaly upserts by fingerprint. detected_at keeps the first detection; last_seen_at tracks the latest one.
+func (r *AnomalyRepository) SaveAnomaly(anomaly *models.Anomaly) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "fingerprint"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"severity", "score", "summary", "window_start", "window_end", "stock_ids", "last_seen_at", "updated_at",
+		}),
+	}).Create(anomaly).Error
+}
+
+func (r *AnomalyRepository) ListAnomalies(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+	q := r.db.Model(&models.Anomaly{})
+	if filter.Kind != "" {
+		q = q.Where("kind = ?", filter.Kind)
+	}
+	if filter.Severity != "" {
+		q = q.Where("severity = ?", filter.Severity)
+	}
+	if filter.Ticker != "" {
+		q = q.Where("ticker = ?", filter.Ticker)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var anomalies []models.Anomaly
+	if err := q.Order("detected_at DESC, id DESC").Limit(limit).Offset(offset).Find(&anomalies).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.loadRecords(anomalies); err != nil {
+		return nil, 0, err
+	}
+	return anomalies, total, nil
+}
+
+// loadRecords fetches the supporting stocks of a page of anomalies with a single query.
+func (r *AnomalyRepository) loadRecords(anomalies []models.Anomaly) error {
+	ids := make([]uint64, 0)
+	for _, anomaly := range anomalies {
+		ids = append(ids, anomaly.StockIDs...)
+	}
+	if len(ids) == 0 {
+		for i := range anomalies {
+			anomalies[i].Records = []models.Stock{}
+		}
+		return nil
+	}
+
+	var stocks []models.Stock
+	if err := r.db.Where("id IN ?", ids).Order("time DESC, id DESC").Find(&stocks).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint64]models.Stock, len(stocks))
+	for _, stock := range stocks {
+		byID[stock.ID] = stock
+	}
+
+	for i := range anomalies {
+		anomalies[i].Records = make([]models.Stock, 0, len(anomalies[i].StockIDs))
+		for _, id := range anomalies[i].StockIDs {
+			if stock, ok := byID[id]; ok {
+				anomalies[i].Records = append(anomalies[i].Records, stock)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package gormrepo
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedAnomalyRepo(t *testing.T) (*AnomalyRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewAnomalyRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestSaveAnomaly_UpsertsByFingerprint(t *testing.T) {
+	repo, mock, cleanup := newMockedAnomalyRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "anomalies"`) + `.*ON CONFLICT \("fingerprint"\) DO UPDATE SET "severity"="excluded"."severity".*"last_seen_at"="excluded"."last_seen_at","updated_at"="excluded"."updated_at" RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectCommit()
+
+	anomaly := &models.Anomaly{
+		Fingerprint: "target_outlier:5",
+		Kind:        models.AnomalyTargetOutlier,
+		Ticker:      "AAPL",
+		Severity:    models.AnomalySeverityHigh,
+		StockIDs:    []uint64{5, 4},
+		WindowStart: time.Now(),
+		WindowEnd:   time.Now(),
+		DetectedAt:  time.Now(),
+		LastSeenAt:  time.Now(),
+	}
+	if err := repo.SaveAnomaly(anomaly); err != nil {
+		t.Fatalf("SaveAnomaly error: %v", err)
+	}
+	if anomaly.ID != 7 {
+		t.Fatalf("ID = %d, want 7", anomaly.ID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListAnomalies_FiltersAndLoadsRecords(t *testing.T) {
+	repo, mock, cleanup := newMockedAnomalyRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "anomalies" WHERE kind = $1 AND ticker = $2`)).
+		WithArgs("activity_spike", "AAPL").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "anomalies" WHERE kind = $1 AND ticker = $2 ORDER BY detected_at DESC, id DESC LIMIT $3`)).
+		WithArgs("activity_spike", "AAPL", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "ticker", "severity", "stock_ids"}).
+			AddRow(1, "activity_spike", "AAPL", "low", `[12,11,99]`))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE id IN ($1,$2,$3) ORDER BY time DESC, id DESC`)).
+		WithArgs(12, 11, 99).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ticker"}).AddRow(12, "AAPL").AddRow(11, "AAPL"))
+
+	anomalies, total, err := repo.ListAnomalies(repositories.AnomalyFilter{Kind: models.AnomalyActivitySpike, Ticker: "AAPL"}, 10, 0)
+	if err != nil {
+		t.Fatalf("ListAnomalies error: %v", err)
+	}
+	if total != 1 || len(anomalies) != 1 {
+		t.Fatalf("total=%d len=%d", total, len(anomalies))
+	}
+	records := anomalies[0].Records
+	if len(records) != 2 || records[0].ID != 12 || records[1].ID != 11 {
+		t.Fatalf("records should follow stock_ids and skip deleted rows: %+v", records)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package gormrepo
+
+import (
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"gorm.io/gorm"
+)
+
+type BrokerageRepository struct {
+	db *gorm.DB
+}
+
+func NewBrokerageRepository(db *gorm.DB) *BrokerageRepository {
+	return &BrokerageRepository{db: db}
+}
+
+func (r *BrokerageRepository) ListReliability() ([]models.BrokerageReliability, error) {
+	var items []models.BrokerageReliability
+	if err := r.db.Order("score DESC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// ReplaceReliability swaps the whole table in a single transaction so readers never see a partial refresh.
+func (r *BrokerageRepository) ReplaceReliability(items []models.BrokerageReliability) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&models.BrokerageReliability{}).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(items, 200).Error
+	})
+}
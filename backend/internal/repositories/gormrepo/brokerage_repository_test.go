@@ -0,0 +1,66 @@
+package gormrepo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedBrokerageRepo(t *testing.T) (*BrokerageRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewBrokerageRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestListReliability_OrdersByScore(t *testing.T) {
+	repo, mock, cleanup := newMockedBrokerageRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"brokerage", "score", "multiplier"}).AddRow("Goldman Sachs", 71.5, 1.13)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "brokerage_reliability" ORDER BY score DESC`)).WillReturnRows(rows)
+
+	items, err := repo.ListReliability()
+	if err != nil {
+		t.Fatalf("ListReliability error: %v", err)
+	}
+	if len(items) != 1 || items[0].Brokerage != "Goldman Sachs" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestReplaceReliability_DeletesAndInsertsInTransaction(t *testing.T) {
+	repo, mock, cleanup := newMockedBrokerageRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "brokerage_reliability" WHERE 1 = 1`)).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "brokerage_reliability"`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ReplaceReliability([]models.BrokerageReliability{{Brokerage: "Goldman Sachs", Score: 71.5, Multiplier: 1.13}})
+	if err != nil {
+		t.Fatalf("ReplaceReliability error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package gormrepo
+
+import (
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RatingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) *RatingRepository {
+	return &RatingRepository{db: db}
+}
+
+func (r *RatingRepository) ListMappings() ([]models.RatingMapping, error) {
+	var items []models.RatingMapping
+	if err := r.db.Order("raw_rating").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SaveMapping upserts by raw_rating so re-posting a value replaces its previous mapping.
+func (r *RatingRepository) SaveMapping(mapping *models.RatingMapping) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "raw_rating"}},
+		DoUpdates: clause.AssignmentColumns([]string{"canonical", "score", "updated_at"}),
+	}).Create(mapping).Error
+}
+
+func (r *RatingRepository) ListUnmapped() ([]models.UnmappedRating, error) {
+	var items []models.UnmappedRating
+	err := r.db.Raw(`SELECT raw_rating, count(*) AS count FROM (
+		SELECT rating_from AS raw_rating FROM stocks WHERE rating_from_canonical = ?
+		UNION ALL
+		SELECT rating_to AS raw_rating FROM stocks WHERE rating_to_canonical = ?
+	) AS unmapped GROUP BY raw_rating ORDER BY count DESC, raw_rating`,
+		models.RatingUnmapped, models.RatingUnmapped).Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *RatingRepository) DistinctRawRatings() ([]string, error) {
+	var ratings []string
+	err := r.db.Raw(`SELECT rating_from FROM stocks WHERE rating_from <> ''
+		UNION
+		SELECT rating_to FROM stocks WHERE rating_to <> ''`).Scan(&ratings).Error
+	if err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+func (r *RatingRepository) ApplyMapping(rawValues []string, canonical models.CanonicalRating, score float64) (int64, error) {
+	if len(rawValues) == 0 {
+		return 0, nil
+	}
+
+	var affected int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		from := tx.Model(&models.Stock{}).Where("rating_from IN ?", rawValues).
+			Updates(map[string]any{"rating_from_canonical": canonical, "rating_from_score": score})
+		if from.Error != nil {
+			return from.Error
+		}
+		to := tx.Model(&models.Stock{}).Where("rating_to IN ?", rawValues).
+			Updates(map[string]any{"rating_to_canonical": canonical, "rating_to_score": score})
+		if to.Error != nil {
+			return to.Error
+		}
+		affected = from.RowsAffected + to.RowsAffected
+		return nil
+	})
+	return affected, err
+}
@@ -0,0 +1,91 @@
+package gormrepo
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedRatingRepo(t *testing.T) (*RatingRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewRatingRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestListUnmapped_AggregatesBothColumns(t *testing.T) {
+	repo, mock, cleanup := newMockedRatingRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"raw_rating", "count"}).AddRow("Top Pick", 3)
+	mock.ExpectQuery(`SELECT raw_rating, count\(\*\) AS count FROM \(.*UNION ALL.*\) AS unmapped GROUP BY raw_rating`).
+		WithArgs(models.RatingUnmapped, models.RatingUnmapped).
+		WillReturnRows(rows)
+
+	items, err := repo.ListUnmapped()
+	if err != nil {
+		t.Fatalf("ListUnmapped error: %v", err)
+	}
+	if len(items) != 1 || items[0].RawRating != "Top Pick" || items[0].Count != 3 {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSaveMapping_Upserts(t *testing.T) {
+	repo, mock, cleanup := newMockedRatingRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "rating_mappings"`) + `.*ON CONFLICT \("raw_rating"\) DO UPDATE`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := repo.SaveMapping(&models.RatingMapping{RawRating: "top pick", Canonical: models.RatingStrongBuy, Score: 5}); err != nil {
+		t.Fatalf("SaveMapping error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestApplyMapping_UpdatesBothColumns(t *testing.T) {
+	repo, mock, cleanup := newMockedRatingRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "stocks" SET .*"rating_from_canonical"=.* WHERE rating_from IN \(\$\d+\)`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "stocks" SET .*"rating_to_canonical"=.* WHERE rating_to IN \(\$\d+\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	updated, err := repo.ApplyMapping([]string{"Top Pick"}, models.RatingStrongBuy, 5)
+	if err != nil {
+		t.Fatalf("ApplyMapping error: %v", err)
+	}
+	if updated != 3 {
+		t.Fatalf("updated = %d, want 3", updated)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
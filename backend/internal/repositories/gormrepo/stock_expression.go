@@ -0,0 +1,71 @@
+package gormrepo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+var expressionComparisons = map[repositories.ExpressionOp]string{
+	repositories.ExprEq: "=",
+	repositories.ExprNe: "<>",
+	repositories.ExprGt: ">",
+	repositories.ExprLt: "<",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// compileStockExpression turns an expression tree into a parameterized WHERE fragment.
+// Column names are only taken from the keysetExpressions whitelist; every literal is bound as an argument.
+// The root is left unparenthesized because GORM already groups multi-condition Where clauses.
+func compileStockExpression(expr *repositories.StockExpression) (string, []any, error) {
+	switch expr.Op {
+	case repositories.ExprAnd, repositories.ExprOr:
+		if len(expr.Children) == 0 {
+			return "", nil, fmt.Errorf("filter expression: %s without operands", expr.Op)
+		}
+		parts := make([]string, 0, len(expr.Children))
+		var args []any
+		for _, child := range expr.Children {
+			part, childArgs, err := compileStockExpression(child)
+			if err != nil {
+				return "", nil, err
+			}
+			if child.Op == repositories.ExprAnd || child.Op == repositories.ExprOr {
+				part = "(" + part + ")"
+			}
+			parts = append(parts, part)
+			args = append(args, childArgs...)
+		}
+		joiner := " AND "
+		if expr.Op == repositories.ExprOr {
+			joiner = " OR "
+		}
+		return strings.Join(parts, joiner), args, nil
+	}
+
+	if _, ok := keysetExpressions[expr.Field]; !ok {
+		return "", nil, fmt.Errorf("filter expression: unsupported field %q", expr.Field)
+	}
+	if len(expr.Values) == 0 {
+		return "", nil, fmt.Errorf("filter expression: %s %s without value", expr.Field, expr.Op)
+	}
+
+	switch expr.Op {
+	case repositories.ExprIn:
+		return expr.Field + " IN ?", []any{expr.Values}, nil
+	case repositories.ExprContains:
+		value, ok := expr.Values[0].(string)
+		if !ok {
+			return "", nil, fmt.Errorf("filter expression: contains on %s needs a string", expr.Field)
+		}
+		return expr.Field + " ILIKE ?", []any{"%" + likeEscaper.Replace(value) + "%"}, nil
+	}
+
+	comparison, ok := expressionComparisons[expr.Op]
+	if !ok {
+		return "", nil, fmt.Errorf("filter expression: unsupported operator %q", expr.Op)
+	}
+	return fmt.Sprintf("%s %s ?", expr.Field, comparison), []any{expr.Values[0]}, nil
+}
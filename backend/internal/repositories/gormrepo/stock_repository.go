@@ -3,6 +3,7 @@ package gormrepo
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/models"
@@ -39,18 +40,33 @@ func (r *StockRepository) Save(stock *models.Stock) error {
 	return r.db.Save(stock).Error
 }
 
-func (r *StockRepository) CountAll() (int64, error) {
+// UpdateDerivedColumns refreshes the ingest-time columns without touching the data that came from the source API.
+func (r *StockRepository) UpdateDerivedColumns(stock *models.Stock) error {
+	return r.db.Model(&models.Stock{}).Where("id = ?", stock.ID).Updates(map[string]any{
+		"rating_from_canonical": stock.RatingFromCanonical,
+		"rating_to_canonical":   stock.RatingToCanonical,
+		"rating_from_score":     stock.RatingFromScore,
+		"rating_to_score":       stock.RatingToScore,
+		"event_type":            stock.EventType,
+		"target_from_value":     stock.TargetFromValue,
+		"target_to_value":       stock.TargetToValue,
+		"target_currency":       stock.TargetCurrency,
+		"target_change_pct":     stock.TargetChangePct,
+	}).Error
+}
+
+func (r *StockRepository) Count(filter repositories.StockFilter) (int64, error) {
 	var total int64
-	if err := r.db.Model(&models.Stock{}).Count(&total).Error; err != nil {
+	if err := applyStockFilter(r.db.Model(&models.Stock{}), filter).Count(&total).Error; err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
-func (r *StockRepository) List(limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
+func (r *StockRepository) List(filter repositories.StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
 	var stocks []models.Stock
 
-	q := r.db.
+	q := applyStockFilter(r.db, filter).
 		Limit(limit).
 		Offset(offset).
 		Order(clause.OrderByColumn{Column: clause.Column{Name: sortField}, Desc: desc})
@@ -61,6 +77,54 @@ func (r *StockRepository) List(limit, offset int, sortField string, desc bool) (
 	return stocks, nil
 }
 
+// keysetExpressions whitelists the sortable columns; nullable numerics are coalesced to NullSortKey.
+var keysetExpressions = map[string]string{
+	"id":                "id",
+	"ticker":            "ticker",
+	"target_from":       "target_from",
+	"target_to":         "target_to",
+	"company":           "company",
+	"action":            "action",
+	"brokerage":         "brokerage",
+	"rating_from":       "rating_from",
+	"rating_to":         "rating_to",
+	"time":              "time",
+	"created_at":        "created_at",
+	"updated_at":        "updated_at",
+	"target_currency":   "target_currency",
+	"target_from_value": fmt.Sprintf("COALESCE(target_from_value, %g)", repositories.NullSortKey),
+	"target_to_value":   fmt.Sprintf("COALESCE(target_to_value, %g)", repositories.NullSortKey),
+	"target_change_pct": fmt.Sprintf("COALESCE(target_change_pct, %g)", repositories.NullSortKey),
+}
+
+// ListPage reads one keyset page using a row-value comparison on (sort key, id), so pages stay
+// stable while rows are inserted and never need an OFFSET scan.
+func (r *StockRepository) ListPage(filter repositories.StockFilter, page repositories.StockPageRequest) ([]models.Stock, error) {
+	expr, ok := keysetExpressions[page.SortField]
+	if !ok {
+		return nil, fmt.Errorf("unsupported keyset sort field %q", page.SortField)
+	}
+
+	scanDesc := page.Desc != page.Backward
+	direction, comparison := "ASC", ">"
+	if scanDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	q := applyStockFilter(r.db, filter)
+	if page.Cursor != nil {
+		q = q.Where(fmt.Sprintf("(%s, id) %s (?, ?)", expr, comparison), page.Cursor.Key, page.Cursor.ID)
+	}
+
+	var stocks []models.Stock
+	if err := q.Order(fmt.Sprintf("%s %s, id %s", expr, direction, direction)).
+		Limit(page.Limit).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
 func (r *StockRepository) FindByID(id uint64) (*models.Stock, error) {
 	var stock models.Stock
 	if err := r.db.First(&stock, id).Error; err != nil {
@@ -80,31 +144,52 @@ func (r *StockRepository) FindByTicker(ticker string) ([]models.Stock, error) {
 	return stocks, nil
 }
 
-func (r *StockRepository) Search(query string, limit int) ([]models.Stock, error) {
+// FindByTickers returns the full history of several tickers in a single query, newest first.
+func (r *StockRepository) FindByTickers(tickers []string) ([]models.Stock, error) {
+	if len(tickers) == 0 {
+		return []models.Stock{}, nil
+	}
+
 	var stocks []models.Stock
-	searchPattern := "%" + query + "%"
+	if err := r.db.Where("ticker IN ?", tickers).Order("time DESC").Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
 
-	if err := r.db.Where("ticker ILIKE ? OR company ILIKE ?", searchPattern, searchPattern).
-		Limit(limit).
-		Order("time DESC").
-		Find(&stocks).Error; err != nil {
+// SearchCandidates aggregates the table by (ticker, company, brokerage) so the service can rank matches in memory.
+func (r *StockRepository) SearchCandidates() ([]models.SearchCandidate, error) {
+	var candidates []models.SearchCandidate
+	if err := r.db.Model(&models.Stock{}).
+		Select("ticker, company, brokerage, count(*) AS count").
+		Group("ticker, company, brokerage").
+		Scan(&candidates).Error; err != nil {
 		return nil, err
 	}
+	return candidates, nil
+}
 
+// LatestByTickers returns the most recent record of each given ticker.
+func (r *StockRepository) LatestByTickers(tickers []string) ([]models.Stock, error) {
+	if len(tickers) == 0 {
+		return []models.Stock{}, nil
+	}
+
+	var stocks []models.Stock
+	if err := r.db.Select("DISTINCT ON (ticker) *").
+		Where("ticker IN ?", tickers).
+		Order("ticker, time DESC, id DESC").
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
 	return stocks, nil
 }
 
-func (r *StockRepository) Filter(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
+func (r *StockRepository) Filter(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
 	var stocks []models.Stock
 	var total int64
 
-	q := r.db.Model(&models.Stock{})
-	if action != "" {
-		q = q.Where("action = ?", action)
-	}
-	if rating != "" {
-		q = q.Where("rating_to = ?", rating)
-	}
+	q := applyStockFilter(r.db.Model(&models.Stock{}), filter)
 
 	if err := q.Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -133,6 +218,172 @@ func (r *StockRepository) DistinctRatings() ([]string, error) {
 	return ratings, nil
 }
 
+func (r *StockRepository) DistinctBrokerages() ([]string, error) {
+	var brokerages []string
+	if err := r.db.Model(&models.Stock{}).
+		Where("brokerage <> ''").
+		Distinct("brokerage").
+		Order("brokerage").
+		Pluck("brokerage", &brokerages).Error; err != nil {
+		return nil, err
+	}
+	return brokerages, nil
+}
+
+// BrokerageActivity aggregates coverage and event counts per brokerage in a single pass.
+// Spellings that differ only in case or surrounding spaces are merged, like the brokerage filter does;
+// the display name is one of the stored spellings.
+func (r *StockRepository) BrokerageActivity() ([]models.BrokerageSummary, error) {
+	var items []models.BrokerageSummary
+	if err := r.db.Model(&models.Stock{}).
+		Select(`max(trim(brokerage)) AS brokerage,
+			count(*) AS total_calls,
+			count(DISTINCT ticker) AS tickers_covered,
+			min(time) AS first_activity,
+			max(time) AS last_activity,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS upgrades,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS downgrades,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS target_raises,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS target_cuts,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS initiations`,
+			models.ActionEventUpgrade, models.ActionEventDowngrade, models.ActionEventTargetRaise,
+			models.ActionEventTargetCut, models.ActionEventInitiation).
+		Where("trim(brokerage) <> ''").
+		Group("lower(trim(brokerage))").
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// sentimentBucketUnits whitelists the date_trunc units; they are interpolated into the query text
+// because the same expression must appear in SELECT and GROUP BY.
+var sentimentBucketUnits = map[models.TimelineBucket]string{
+	models.TimelineBucketDay:   "day",
+	models.TimelineBucketWeek:  "week",
+	models.TimelineBucketMonth: "month",
+}
+
+// SentimentSeries aggregates event counts per time bucket in the database.
+func (r *StockRepository) SentimentSeries(filter repositories.StockFilter, bucket models.TimelineBucket) ([]models.SentimentPoint, error) {
+	unit, ok := sentimentBucketUnits[bucket]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sentiment bucket %q", bucket)
+	}
+
+	var points []models.SentimentPoint
+	q := r.db.Model(&models.Stock{}).
+		Select(fmt.Sprintf(`date_trunc('%s', time AT TIME ZONE 'UTC') AS start,
+			count(*) AS total,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS upgrades,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS downgrades,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS target_raises,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS target_cuts,
+			sum(CASE WHEN event_type = ? THEN 1 ELSE 0 END) AS initiations`, unit),
+			models.ActionEventUpgrade, models.ActionEventDowngrade, models.ActionEventTargetRaise,
+			models.ActionEventTargetCut, models.ActionEventInitiation)
+	q = applyStockFilter(q, filter)
+	if err := q.Group("start").Order("start").Scan(&points).Error; err != nil {
+		return nil, err
+	}
+	for i := range points {
+		points[i].Start = points[i].Start.UTC()
+	}
+	return points, nil
+}
+
+// ratingColumns maps a rating mode to its from/to columns.
+func ratingColumns(mode models.RatingMode) (string, string, error) {
+	switch mode {
+	case models.RatingModeCanonical:
+		return "rating_from_canonical", "rating_to_canonical", nil
+	case models.RatingModeRaw:
+		return "rating_from", "rating_to", nil
+	default:
+		return "", "", fmt.Errorf("unsupported rating mode %q", mode)
+	}
+}
+
+// RatingTransitions groups calls by their rating pair, most frequent first.
+func (r *StockRepository) RatingTransitions(filter repositories.StockFilter, mode models.RatingMode) ([]models.RatingTransition, error) {
+	fromColumn, toColumn, err := ratingColumns(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	var transitions []models.RatingTransition
+	q := r.db.Model(&models.Stock{}).
+		Select(fmt.Sprintf("%s AS rating_from, %s AS rating_to, count(*) AS count", fromColumn, toColumn)).
+		Where(fmt.Sprintf("%s <> '' AND %s <> ''", fromColumn, toColumn))
+	q = applyStockFilter(q, filter)
+	if err := q.Group(fromColumn + ", " + toColumn).Order("count DESC").Scan(&transitions).Error; err != nil {
+		return nil, err
+	}
+	return transitions, nil
+}
+
+// CurrentRatingDistribution takes the latest rated call of each ticker and counts them by rating.
+func (r *StockRepository) CurrentRatingDistribution(mode models.RatingMode) ([]models.RatingShare, error) {
+	_, toColumn, err := ratingColumns(mode)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := r.db.Model(&models.Stock{}).
+		Select(fmt.Sprintf("DISTINCT ON (ticker) %s AS rating", toColumn)).
+		Where(toColumn + " <> ''").
+		Order("ticker, time DESC, id DESC")
+
+	var items []models.RatingShare
+	if err := r.db.Table("(?) AS latest", latest).
+		Select("rating, count(*) AS tickers").
+		Group("rating").
+		Order("tickers DESC, rating").
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// TickerActivity returns one row per ticker with the company of its latest record, the record count
+// and the time of the latest record.
+func (r *StockRepository) TickerActivity() ([]models.TickerSuggestion, error) {
+	var items []models.TickerSuggestion
+	if err := r.db.Model(&models.Stock{}).
+		Select("DISTINCT ON (ticker) ticker, company, count(*) OVER (PARTITION BY ticker) AS records, time AS last_seen").
+		Order("ticker, time DESC, id DESC").
+		Scan(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// DistinctCanonicalRatings lists the normalized target ratings present, skipping rows not yet normalized.
+func (r *StockRepository) DistinctCanonicalRatings() ([]string, error) {
+	var ratings []string
+	if err := r.db.Model(&models.Stock{}).
+		Where("rating_to_canonical <> ''").
+		Distinct("rating_to_canonical").
+		Pluck("rating_to_canonical", &ratings).Error; err != nil {
+		return nil, err
+	}
+	return ratings, nil
+}
+
+// CountByEventType groups classified rows by event type, most frequent first.
+func (r *StockRepository) CountByEventType() ([]models.EventTypeCount, error) {
+	var counts []models.EventTypeCount
+	if err := r.db.Model(&models.Stock{}).
+		Select("event_type, count(*) AS count").
+		Where("event_type <> ''").
+		Group("event_type").
+		Order("count DESC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
 func (r *StockRepository) Latest(limit int) ([]models.Stock, error) {
 	var stocks []models.Stock
 	if err := r.db.Order("created_at DESC").Limit(limit).Find(&stocks).Error; err != nil {
@@ -148,3 +399,129 @@ func (r *StockRepository) FindSince(since time.Time) ([]models.Stock, error) {
 	}
 	return stocks, nil
 }
+
+func (r *StockRepository) FindRatingBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var stocks []models.Stock
+	if err := r.db.
+		Where("id > ?", afterID).
+		Where("(rating_from <> '' AND rating_from_canonical = '') OR (rating_to <> '' AND rating_to_canonical = '')").
+		Order("id").
+		Limit(limit).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// UpdateRatingValues writes only the normalized rating columns so the backfill never clobbers concurrent syncs.
+func (r *StockRepository) UpdateRatingValues(stock *models.Stock) error {
+	return r.db.Model(&models.Stock{}).Where("id = ?", stock.ID).Updates(map[string]any{
+		"rating_from_canonical": stock.RatingFromCanonical,
+		"rating_to_canonical":   stock.RatingToCanonical,
+		"rating_from_score":     stock.RatingFromScore,
+		"rating_to_score":       stock.RatingToScore,
+	}).Error
+}
+
+func (r *StockRepository) FindEventTypeBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var stocks []models.Stock
+	if err := r.db.
+		Where("id > ? AND action <> '' AND event_type = ''", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// UpdateEventType writes only event_type so the backfill never clobbers concurrent syncs.
+func (r *StockRepository) UpdateEventType(stock *models.Stock) error {
+	return r.db.Model(&models.Stock{}).Where("id = ?", stock.ID).Update("event_type", stock.EventType).Error
+}
+
+func (r *StockRepository) FindTargetBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var stocks []models.Stock
+	if err := r.db.
+		Where("id > ? AND target_from_value IS NULL AND target_to_value IS NULL", afterID).
+		Where("target_from <> '' OR target_to <> ''").
+		Order("id").
+		Limit(limit).
+		Find(&stocks).Error; err != nil {
+		return nil, err
+	}
+	return stocks, nil
+}
+
+// UpdateTargetValues writes only the derived target columns so the backfill never clobbers concurrent syncs.
+func (r *StockRepository) UpdateTargetValues(stock *models.Stock) error {
+	return r.db.Model(&models.Stock{}).Where("id = ?", stock.ID).Updates(map[string]any{
+		"target_from_value": stock.TargetFromValue,
+		"target_to_value":   stock.TargetToValue,
+		"target_currency":   stock.TargetCurrency,
+		"target_change_pct": stock.TargetChangePct,
+	}).Error
+}
+
+// applyStockFilter adds the WHERE clauses shared by Filter, List and Count.
+func applyStockFilter(q *gorm.DB, filter repositories.StockFilter) *gorm.DB {
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.Rating != "" {
+		q = q.Where("rating_to = ?", filter.Rating)
+	}
+	if filter.CanonicalRating != "" {
+		q = q.Where("rating_to_canonical = ?", filter.CanonicalRating)
+	}
+	if filter.EventType != "" {
+		q = q.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Currency != "" {
+		q = q.Where("target_currency = ?", filter.Currency)
+	}
+	if filter.TimeFrom != nil {
+		q = q.Where("time >= ?", *filter.TimeFrom)
+	}
+	if filter.TimeTo != nil {
+		q = q.Where("time <= ?", *filter.TimeTo)
+	}
+	if len(filter.Brokerages) > 0 {
+		lowered := make([]string, len(filter.Brokerages))
+		for i, brokerage := range filter.Brokerages {
+			lowered[i] = strings.ToLower(strings.TrimSpace(brokerage))
+		}
+		q = q.Where("lower(trim(brokerage)) IN ?", lowered)
+	}
+	if len(filter.Tickers) > 0 {
+		q = q.Where("ticker IN ?", filter.Tickers)
+	}
+	if filter.Expression != nil {
+		clause, args, err := compileStockExpression(filter.Expression)
+		if err != nil {
+			_ = q.AddError(err)
+		} else {
+			q = q.Where(clause, args...)
+		}
+	}
+
+	ranges := []struct {
+		column string
+		min    *float64
+		max    *float64
+	}{
+		{column: "target_from_value", min: filter.MinTargetFrom, max: filter.MaxTargetFrom},
+		{column: "target_to_value", min: filter.MinTargetTo, max: filter.MaxTargetTo},
+		{column: "target_change_pct", min: filter.MinChangePct, max: filter.MaxChangePct},
+	}
+	for _, rg := range ranges {
+		if rg.min != nil {
+			q = q.Where(rg.column+" >= ?", *rg.min)
+		}
+		if rg.max != nil {
+			q = q.Where(rg.column+" <= ?", *rg.max)
+		}
+	}
+
+	return q
+}
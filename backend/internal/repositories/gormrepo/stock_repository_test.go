@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
 	"github.com/Hitomiblood/StockStream/internal/repositories"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -50,16 +51,16 @@ func TestGetByTickerAndTime_NotFoundMapsDomainError(t *testing.T) {
 	}
 }
 
-func TestCountAll_Success(t *testing.T) {
+func TestCount_Success(t *testing.T) {
 	repo, mock, cleanup := newMockedRepo(t)
 	defer cleanup()
 
 	rows := sqlmock.NewRows([]string{"count"}).AddRow(7)
 	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "stocks"`)).WillReturnRows(rows)
 
-	total, err := repo.CountAll()
+	total, err := repo.Count(repositories.StockFilter{})
 	if err != nil {
-		t.Fatalf("CountAll error: %v", err)
+		t.Fatalf("Count error: %v", err)
 	}
 	if total != 7 {
 		t.Fatalf("total = %d, want 7", total)
@@ -79,7 +80,7 @@ func TestList_Success(t *testing.T) {
 		WithArgs(10).
 		WillReturnRows(rows)
 
-	stocks, err := repo.List(10, 0, "time", true)
+	stocks, err := repo.List(repositories.StockFilter{}, 10, 0, "time", true)
 	if err != nil {
 		t.Fatalf("List error: %v", err)
 	}
@@ -92,6 +93,201 @@ func TestList_Success(t *testing.T) {
 	}
 }
 
+func TestList_RangeFiltersAndNumericSort(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	minTo, maxPct := 100.0, 25.0
+	rows := sqlmock.NewRows([]string{"id", "ticker", "target_to_value"}).AddRow(1, "AAPL", 150.0)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE target_currency = $1 AND target_to_value >= $2 AND target_change_pct <= $3 ORDER BY "target_to_value" LIMIT $4`)).
+		WithArgs("USD", minTo, maxPct, 10).
+		WillReturnRows(rows)
+
+	filter := repositories.StockFilter{MinTargetTo: &minTo, MaxChangePct: &maxPct, Currency: "USD"}
+	stocks, err := repo.List(filter, 10, 0, "target_to_value", false)
+	if err != nil {
+		t.Fatalf("List error: %v", err)
+	}
+	if len(stocks) != 1 || stocks[0].TargetToValue == nil || *stocks[0].TargetToValue != 150 {
+		t.Fatalf("unexpected stocks: %+v", stocks)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCount_TimeBrokerageAndTickerFilters(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "stocks" WHERE event_type = $1 AND time >= $2 AND lower(trim(brokerage)) IN ($3,$4) AND ticker IN ($5)`)).
+		WithArgs("target_raise", from, "goldman sachs", "jpmorgan", "AAPL").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	total, err := repo.Count(repositories.StockFilter{
+		EventType:  models.ActionEventTargetRaise,
+		TimeFrom:   &from,
+		Brokerages: []string{"Goldman Sachs", "JPMorgan"},
+		Tickers:    []string{"AAPL"},
+	})
+	if err != nil || total != 3 {
+		t.Fatalf("Count total=%d err=%v", total, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListPage_KeysetCondition(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	key := time.Date(2026, 2, 1, 12, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "ticker"}).AddRow(7, "AAPL")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE action = $1 AND (time, id) < ($2, $3) ORDER BY time DESC, id DESC LIMIT $4`)).
+		WithArgs("upgraded by", key, uint64(9), 21).
+		WillReturnRows(rows)
+
+	stocks, err := repo.ListPage(repositories.StockFilter{Action: "upgraded by"}, repositories.StockPageRequest{
+		SortField: "time",
+		Desc:      true,
+		Cursor:    &repositories.StockCursor{Key: key, ID: 9},
+		Limit:     21,
+	})
+	if err != nil || len(stocks) != 1 || stocks[0].ID != 7 {
+		t.Fatalf("ListPage err=%v stocks=%+v", err, stocks)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListPage_BackwardNumericSort(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE (COALESCE(target_to_value, -1e+18), id) < ($1, $2) ORDER BY COALESCE(target_to_value, -1e+18) DESC, id DESC LIMIT $3`)).
+		WithArgs(150.0, uint64(3), 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := repo.ListPage(repositories.StockFilter{}, repositories.StockPageRequest{
+		SortField: "target_to_value",
+		Cursor:    &repositories.StockCursor{Key: 150.0, ID: 3},
+		Backward:  true,
+		Limit:     11,
+	})
+	if err != nil {
+		t.Fatalf("ListPage error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCount_FilterExpression(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "stocks" WHERE action = $1 AND (ticker IN ($2,$3) AND (target_change_pct > $4 OR company ILIKE $5))`)).
+		WithArgs("target raised by", "AAPL", "MSFT", 10.0, `%50\%%`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+
+	total, err := repo.Count(repositories.StockFilter{
+		Action: "target raised by",
+		Expression: &repositories.StockExpression{
+			Op: repositories.ExprAnd,
+			Children: []*repositories.StockExpression{
+				{Op: repositories.ExprIn, Field: "ticker", Values: []any{"AAPL", "MSFT"}},
+				{Op: repositories.ExprOr, Children: []*repositories.StockExpression{
+					{Op: repositories.ExprGt, Field: "target_change_pct", Values: []any{10.0}},
+					{Op: repositories.ExprContains, Field: "company", Values: []any{"50%"}},
+				}},
+			},
+		},
+	})
+	if err != nil || total != 2 {
+		t.Fatalf("Count total=%d err=%v", total, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCount_FilterExpressionRejectsUnknownColumn(t *testing.T) {
+	repo, _, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	_, err := repo.Count(repositories.StockFilter{
+		Expression: &repositories.StockExpression{Op: repositories.ExprEq, Field: "1=1; --", Values: []any{"x"}},
+	})
+	if err == nil {
+		t.Fatalf("expected an error for a non-whitelisted column")
+	}
+}
+
+func TestFindRatingBackfillBatch(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"id", "rating_to"}).AddRow(11, "Buy")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE id > $1 AND ((rating_from <> '' AND rating_from_canonical = '') OR (rating_to <> '' AND rating_to_canonical = '')) ORDER BY id LIMIT $2`)).
+		WithArgs(10, 500).
+		WillReturnRows(rows)
+
+	stocks, err := repo.FindRatingBackfillBatch(10, 500)
+	if err != nil || len(stocks) != 1 || stocks[0].ID != 11 {
+		t.Fatalf("FindRatingBackfillBatch err=%v stocks=%+v", err, stocks)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestFindEventTypeBackfillBatch(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"id", "action"}).AddRow(11, "upgraded by")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE id > $1 AND action <> '' AND event_type = '' ORDER BY id LIMIT $2`)).
+		WithArgs(10, 500).
+		WillReturnRows(rows)
+
+	stocks, err := repo.FindEventTypeBackfillBatch(10, 500)
+	if err != nil || len(stocks) != 1 || stocks[0].ID != 11 {
+		t.Fatalf("FindEventTypeBackfillBatch err=%v stocks=%+v", err, stocks)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestFindTargetBackfillBatch(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"id", "target_from", "target_to"}).AddRow(11, "$10", "$12")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE (id > $1 AND target_from_value IS NULL AND target_to_value IS NULL) AND (target_from <> '' OR target_to <> '') ORDER BY id LIMIT $2`)).
+		WithArgs(10, 500).
+		WillReturnRows(rows)
+
+	stocks, err := repo.FindTargetBackfillBatch(10, 500)
+	if err != nil || len(stocks) != 1 || stocks[0].ID != 11 {
+		t.Fatalf("FindTargetBackfillBatch err=%v stocks=%+v", err, stocks)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestFindSince_WrapsError(t *testing.T) {
 	repo, mock, cleanup := newMockedRepo(t)
 	defer cleanup()
@@ -142,21 +338,77 @@ func TestFindByTicker_Success(t *testing.T) {
 	}
 }
 
-func TestSearch_Success(t *testing.T) {
+func TestFindByTickers_SingleQuery(t *testing.T) {
 	repo, mock, cleanup := newMockedRepo(t)
 	defer cleanup()
 
-	rows := sqlmock.NewRows([]string{"id", "ticker"}).AddRow(1, "AAPL")
-	mock.ExpectQuery(`SELECT \* FROM "stocks" WHERE ticker ILIKE \$1 OR company ILIKE \$2 ORDER BY time DESC LIMIT \$3`).
-		WithArgs("%app%", "%app%", 5).
+	rows := sqlmock.NewRows([]string{"id", "ticker"}).AddRow(2, "MSFT").AddRow(1, "AAPL")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "stocks" WHERE ticker IN ($1,$2) ORDER BY time DESC`)).
+		WithArgs("AAPL", "MSFT").
 		WillReturnRows(rows)
 
-	stocks, err := repo.Search("app", 5)
+	stocks, err := repo.FindByTickers([]string{"AAPL", "MSFT"})
+	if err != nil || len(stocks) != 2 {
+		t.Fatalf("FindByTickers error=%v got=%d", err, len(stocks))
+	}
+
+	if empty, err := repo.FindByTickers(nil); err != nil || len(empty) != 0 {
+		t.Fatalf("expected no query for an empty list, got %v %v", empty, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSearchCandidates_Success(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"ticker", "company", "brokerage", "count"}).
+		AddRow("GOOGL", "Alphabet Inc.", "Goldman Sachs", 3)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT ticker, company, brokerage, count(*) AS count FROM "stocks" GROUP BY ticker, company, brokerage`)).
+		WillReturnRows(rows)
+
+	candidates, err := repo.SearchCandidates()
 	if err != nil {
-		t.Fatalf("Search error: %v", err)
+		t.Fatalf("SearchCandidates error: %v", err)
 	}
-	if len(stocks) != 1 {
-		t.Fatalf("expected 1 stock, got %d", len(stocks))
+	if len(candidates) != 1 || candidates[0].Company != "Alphabet Inc." || candidates[0].Count != 3 {
+		t.Fatalf("unexpected candidates: %+v", candidates)
+	}
+}
+
+func TestTickerActivity_Success(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	lastSeen := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"ticker", "company", "records", "last_seen"}).AddRow("AAPL", "Apple Inc.", 12, lastSeen)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (ticker) ticker, company, count(*) OVER (PARTITION BY ticker) AS records, time AS last_seen FROM "stocks" ORDER BY ticker, time DESC, id DESC`)).
+		WillReturnRows(rows)
+
+	items, err := repo.TickerActivity()
+	if err != nil || len(items) != 1 || items[0].Records != 12 || !items[0].LastSeen.Equal(lastSeen) {
+		t.Fatalf("TickerActivity err=%v items=%+v", err, items)
+	}
+}
+
+func TestLatestByTickers_Success(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"id", "ticker"}).AddRow(9, "AAPL").AddRow(4, "MSFT")
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT ON (ticker) * FROM "stocks" WHERE ticker IN ($1,$2) ORDER BY ticker, time DESC, id DESC`)).
+		WithArgs("AAPL", "MSFT").
+		WillReturnRows(rows)
+
+	stocks, err := repo.LatestByTickers([]string{"AAPL", "MSFT"})
+	if err != nil || len(stocks) != 2 {
+		t.Fatalf("LatestByTickers err=%v stocks=%+v", err, stocks)
+	}
+
+	if empty, err := repo.LatestByTickers(nil); err != nil || len(empty) != 0 {
+		t.Fatalf("expected no query for empty tickers, got %v %v", empty, err)
 	}
 }
 
@@ -174,7 +426,7 @@ func TestFilter_Success(t *testing.T) {
 		WithArgs("Upgrade", 10).
 		WillReturnRows(dataRows)
 
-	stocks, total, err := repo.Filter("Upgrade", "", 10, 0)
+	stocks, total, err := repo.Filter(repositories.StockFilter{Action: "Upgrade"}, 10, 0)
 	if err != nil {
 		t.Fatalf("Filter error: %v", err)
 	}
@@ -183,6 +435,164 @@ func TestFilter_Success(t *testing.T) {
 	}
 }
 
+func TestFilter_CanonicalRating(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	countRows := sqlmock.NewRows([]string{"count"}).AddRow(1)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "stocks" WHERE action = \$1 AND rating_to_canonical = \$2`).
+		WithArgs("Upgrade", "strong_buy").
+		WillReturnRows(countRows)
+
+	dataRows := sqlmock.NewRows([]string{"id", "ticker", "rating_to_canonical"}).AddRow(1, "AAPL", "strong_buy")
+	mock.ExpectQuery(`SELECT \* FROM "stocks" WHERE action = \$1 AND rating_to_canonical = \$2 ORDER BY time DESC LIMIT \$3`).
+		WithArgs("Upgrade", "strong_buy", 10).
+		WillReturnRows(dataRows)
+
+	stocks, total, err := repo.Filter(repositories.StockFilter{Action: "Upgrade", CanonicalRating: models.RatingStrongBuy}, 10, 0)
+	if err != nil {
+		t.Fatalf("Filter error: %v", err)
+	}
+	if total != 1 || len(stocks) != 1 || stocks[0].RatingToCanonical != models.RatingStrongBuy {
+		t.Fatalf("unexpected filter result total=%d stocks=%+v", total, stocks)
+	}
+}
+
+func TestCountByEventType(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"event_type", "count"}).AddRow("upgrade", 4).AddRow("target_cut", 2)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT event_type, count(*) AS count FROM "stocks" WHERE event_type <> '' GROUP BY "event_type" ORDER BY count DESC`)).
+		WillReturnRows(rows)
+
+	counts, err := repo.CountByEventType()
+	if err != nil {
+		t.Fatalf("CountByEventType error: %v", err)
+	}
+	if len(counts) != 2 || counts[0].EventType != models.ActionEventUpgrade || counts[0].Count != 4 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestBrokerageActivityAndDistinctBrokerages(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	now := time.Now().UTC()
+	rows := sqlmock.NewRows([]string{"brokerage", "total_calls", "tickers_covered", "first_activity", "last_activity", "upgrades", "downgrades", "target_raises", "target_cuts", "initiations"}).
+		AddRow("Goldman Sachs", 12, 5, now.Add(-48*time.Hour), now, 4, 2, 3, 1, 1)
+	// "Goldman Sachs", "goldman sachs" and "Goldman Sachs " are grouped into a single row
+	mock.ExpectQuery(`SELECT max\(trim\(brokerage\)\) AS brokerage,\s+count\(\*\) AS total_calls,\s+count\(DISTINCT ticker\) AS tickers_covered,.+FROM "stocks" WHERE trim\(brokerage\) <> '' GROUP BY lower\(trim\(brokerage\)\)`).
+		WithArgs("upgrade", "downgrade", "target_raise", "target_cut", "initiation").
+		WillReturnRows(rows)
+
+	items, err := repo.BrokerageActivity()
+	if err != nil {
+		t.Fatalf("BrokerageActivity error: %v", err)
+	}
+	if len(items) != 1 || items[0].TotalCalls != 12 || items[0].TickersCovered != 5 || items[0].Downgrades != 2 || !items[0].LastActivity.Equal(now) {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT DISTINCT "brokerage" FROM "stocks" WHERE brokerage <> '' ORDER BY brokerage`)).
+		WillReturnRows(sqlmock.NewRows([]string{"brokerage"}).AddRow("Barclays").AddRow("Goldman Sachs"))
+
+	brokerages, err := repo.DistinctBrokerages()
+	if err != nil || len(brokerages) != 2 || brokerages[0] != "Barclays" {
+		t.Fatalf("DistinctBrokerages error=%v got=%v", err, brokerages)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestSentimentSeries_GroupsByBucketWithFilters(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	week := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"start", "total", "upgrades", "downgrades", "target_raises", "target_cuts", "initiations"}).
+		AddRow(week, 9, 3, 1, 4, 0, 1)
+	mock.ExpectQuery(`SELECT date_trunc\('week', time AT TIME ZONE 'UTC'\) AS start,\s+count\(\*\) AS total,.+FROM "stocks" WHERE time >= \$6 AND lower\(trim\(brokerage\)\) IN \(\$7\) AND ticker IN \(\$8,\$9\) GROUP BY "start" ORDER BY start`).
+		WithArgs("upgrade", "downgrade", "target_raise", "target_cut", "initiation", from, "goldman sachs", "AAPL", "MSFT").
+		WillReturnRows(rows)
+
+	points, err := repo.SentimentSeries(repositories.StockFilter{
+		TimeFrom:   &from,
+		Brokerages: []string{"Goldman Sachs"},
+		Tickers:    []string{"AAPL", "MSFT"},
+	}, models.TimelineBucketWeek)
+	if err != nil {
+		t.Fatalf("SentimentSeries error: %v", err)
+	}
+	if len(points) != 1 || !points[0].Start.Equal(week) || points[0].Total != 9 || points[0].TargetRaises != 4 {
+		t.Fatalf("unexpected points: %+v", points)
+	}
+
+	if _, err := repo.SentimentSeries(repositories.StockFilter{}, "year"); err == nil {
+		t.Fatalf("expected an error for an unsupported bucket")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestRatingTransitions_CanonicalColumns(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	to := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"rating_from", "rating_to", "count"}).AddRow("hold", "buy", 6).AddRow("buy", "hold", 2)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT rating_from_canonical AS rating_from, rating_to_canonical AS rating_to, count(*) AS count FROM "stocks" WHERE (rating_from_canonical <> '' AND rating_to_canonical <> '') AND time <= $1 GROUP BY rating_from_canonical, rating_to_canonical ORDER BY count DESC`)).
+		WithArgs(to).
+		WillReturnRows(rows)
+
+	transitions, err := repo.RatingTransitions(repositories.StockFilter{TimeTo: &to}, models.RatingModeCanonical)
+	if err != nil {
+		t.Fatalf("RatingTransitions error: %v", err)
+	}
+	if len(transitions) != 2 || transitions[0].RatingFrom != "hold" || transitions[0].RatingTo != "buy" || transitions[0].Count != 6 {
+		t.Fatalf("unexpected transitions: %+v", transitions)
+	}
+
+	if _, err := repo.RatingTransitions(repositories.StockFilter{}, "other"); err == nil {
+		t.Fatalf("expected an error for an unsupported mode")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestCurrentRatingDistribution_LatestPerTicker(t *testing.T) {
+	repo, mock, cleanup := newMockedRepo(t)
+	defer cleanup()
+
+	rows := sqlmock.NewRows([]string{"rating", "tickers"}).AddRow("Buy", 12).AddRow("Hold", 5)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT rating, count(*) AS tickers FROM (SELECT DISTINCT ON (ticker) rating_to AS rating FROM "stocks" WHERE rating_to <> '' ORDER BY ticker, time DESC, id DESC) AS latest GROUP BY "rating" ORDER BY tickers DESC, rating`)).
+		WillReturnRows(rows)
+
+	items, err := repo.CurrentRatingDistribution(models.RatingModeRaw)
+	if err != nil {
+		t.Fatalf("CurrentRatingDistribution error: %v", err)
+	}
+	if len(items) != 2 || items[0].Rating != "Buy" || items[0].Tickers != 12 {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
 func TestDistinctAndLatest(t *testing.T) {
 	repo, mock, cleanup := newMockedRepo(t)
 	defer cleanup()
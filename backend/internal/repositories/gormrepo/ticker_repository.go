@@ -0,0 +1,85 @@
+package gormrepo
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TickerRepository struct {
+	db *gorm.DB
+}
+
+func NewTickerRepository(db *gorm.DB) *TickerRepository {
+	return &TickerRepository{db: db}
+}
+
+// tickerSortColumns whitelists the columns accepted by ListTickers.
+var tickerSortColumns = map[string]string{
+	"ticker":       "ticker",
+	"first_seen":   "first_seen",
+	"last_seen":    "last_seen",
+	"record_count": "record_count",
+}
+
+// SaveTicker upserts the company and the ticker in one transaction so a ticker never points to a missing company.
+func (r *TickerRepository) SaveTicker(ticker *models.Ticker, company *models.Company) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ticker.CompanyID = nil
+		if company != nil {
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "normalized_name"}},
+				DoUpdates: clause.AssignmentColumns([]string{"name", "updated_at"}),
+			}).Create(company).Error; err != nil {
+				return err
+			}
+			ticker.CompanyID = &company.ID
+		}
+
+		return tx.Omit("Company").Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "ticker"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"company_id", "first_seen", "last_seen", "record_count", "latest_stock_id",
+				"latest_rating", "latest_rating_canonical", "latest_brokerage", "updated_at",
+			}),
+		}).Create(ticker).Error
+	})
+}
+
+func (r *TickerRepository) ListTickers(limit, offset int, sortField string, desc bool) ([]models.Ticker, int64, error) {
+	column, ok := tickerSortColumns[sortField]
+	if !ok {
+		return nil, 0, fmt.Errorf("unsupported ticker sort field %q", sortField)
+	}
+
+	var total int64
+	if err := r.db.Model(&models.Ticker{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var tickers []models.Ticker
+	if err := r.db.Preload("Company").
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: desc}).
+		Order("ticker").
+		Limit(limit).
+		Offset(offset).
+		Find(&tickers).Error; err != nil {
+		return nil, 0, err
+	}
+	return tickers, total, nil
+}
+
+func (r *TickerRepository) FindTicker(ticker string) (*models.Ticker, error) {
+	var item models.Ticker
+	if err := r.db.Preload("Company").Where("ticker = ?", ticker).First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &item, nil
+}
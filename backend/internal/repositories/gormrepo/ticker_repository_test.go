@@ -0,0 +1,99 @@
+package gormrepo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedTickerRepo(t *testing.T) (*TickerRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewTickerRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestSaveTicker_UpsertsCompanyThenTicker(t *testing.T) {
+	repo, mock, cleanup := newMockedTickerRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "companies"`) + `.*ON CONFLICT \("normalized_name"\) DO UPDATE SET "name"="excluded"."name".*RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "tickers"`) + `.*ON CONFLICT \("ticker"\) DO UPDATE SET "company_id"="excluded"."company_id"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ticker := &models.Ticker{Ticker: "AAPL", FirstSeen: time.Now(), LastSeen: time.Now(), RecordCount: 3}
+	company := &models.Company{Name: "Apple Inc.", NormalizedName: "apple inc"}
+	if err := repo.SaveTicker(ticker, company); err != nil {
+		t.Fatalf("SaveTicker error: %v", err)
+	}
+	if ticker.CompanyID == nil || *ticker.CompanyID != 42 {
+		t.Fatalf("ticker should reference the upserted company, got %v", ticker.CompanyID)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListTickers_SortsAndPreloadsCompany(t *testing.T) {
+	repo, mock, cleanup := newMockedTickerRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "tickers"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tickers" ORDER BY "record_count" DESC,ticker LIMIT $1`)).
+		WithArgs(10).
+		WillReturnRows(sqlmock.NewRows([]string{"ticker", "company_id", "record_count"}).AddRow("AAPL", 7, 12))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "companies" WHERE "companies"."id" = $1`)).
+		WithArgs(7).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Apple Inc."))
+
+	tickers, total, err := repo.ListTickers(10, 0, "record_count", true)
+	if err != nil || total != 1 || len(tickers) != 1 {
+		t.Fatalf("ListTickers total=%d err=%v tickers=%+v", total, err, tickers)
+	}
+	if tickers[0].Company == nil || tickers[0].Company.Name != "Apple Inc." {
+		t.Fatalf("company not preloaded: %+v", tickers[0])
+	}
+
+	if _, _, err := repo.ListTickers(10, 0, "company_id; --", true); err == nil {
+		t.Fatalf("expected error for a non-whitelisted sort field")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestFindTicker_NotFound(t *testing.T) {
+	repo, mock, cleanup := newMockedTickerRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "tickers" WHERE ticker = $1 ORDER BY "tickers"."ticker" LIMIT $2`)).
+		WithArgs("ZZZZ", 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	_, err := repo.FindTicker("ZZZZ")
+	if !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
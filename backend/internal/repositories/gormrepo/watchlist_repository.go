@@ -0,0 +1,101 @@
+package gormrepo
+
+import (
+	"errors"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WatchlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWatchlistRepository(db *gorm.DB) *WatchlistRepository {
+	return &WatchlistRepository{db: db}
+}
+
+func (r *WatchlistRepository) CreateWatchlist(watchlist *models.Watchlist) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(watchlist).Error; err != nil {
+			return err
+		}
+		return insertWatchlistTickers(tx, watchlist)
+	})
+}
+
+func (r *WatchlistRepository) ListWatchlists(owner string) ([]models.Watchlist, error) {
+	var watchlists []models.Watchlist
+	if err := r.db.Preload("Tickers", orderWatchlistTickers).
+		Where("owner = ?", owner).
+		Order("name").
+		Find(&watchlists).Error; err != nil {
+		return nil, err
+	}
+	return watchlists, nil
+}
+
+func (r *WatchlistRepository) FindWatchlist(owner string, id uint64) (*models.Watchlist, error) {
+	return r.findWatchlist(r.db.Where("owner = ? AND id = ?", owner, id))
+}
+
+func (r *WatchlistRepository) FindWatchlistByName(owner, name string) (*models.Watchlist, error) {
+	return r.findWatchlist(r.db.Where("owner = ? AND name = ?", owner, name))
+}
+
+func (r *WatchlistRepository) findWatchlist(q *gorm.DB) (*models.Watchlist, error) {
+	var watchlist models.Watchlist
+	if err := q.Preload("Tickers", orderWatchlistTickers).First(&watchlist).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (r *WatchlistRepository) UpdateWatchlist(watchlist *models.Watchlist) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Watchlist{}).
+			Where("owner = ? AND id = ?", watchlist.Owner, watchlist.ID).
+			Updates(map[string]any{"name": watchlist.Name, "notes": watchlist.Notes, "updated_at": watchlist.UpdatedAt})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repositories.ErrNotFound
+		}
+		if err := tx.Where("watchlist_id = ?", watchlist.ID).Delete(&models.WatchlistTicker{}).Error; err != nil {
+			return err
+		}
+		return insertWatchlistTickers(tx, watchlist)
+	})
+}
+
+func (r *WatchlistRepository) DeleteWatchlist(owner string, id uint64) error {
+	result := r.db.Where("owner = ? AND id = ?", owner, id).Delete(&models.Watchlist{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+// insertWatchlistTickers writes the ticker rows of a watchlist whose id is already known.
+func insertWatchlistTickers(tx *gorm.DB, watchlist *models.Watchlist) error {
+	if len(watchlist.Tickers) == 0 {
+		return nil
+	}
+	for i := range watchlist.Tickers {
+		watchlist.Tickers[i].WatchlistID = watchlist.ID
+	}
+	return tx.Create(&watchlist.Tickers).Error
+}
+
+func orderWatchlistTickers(db *gorm.DB) *gorm.DB {
+	return db.Order("added_at, ticker")
+}
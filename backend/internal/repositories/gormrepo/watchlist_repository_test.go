@@ -0,0 +1,138 @@
+package gormrepo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedWatchlistRepo(t *testing.T) (*WatchlistRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewWatchlistRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestCreateWatchlist_InsertsWatchlistThenTickers(t *testing.T) {
+	repo, mock, cleanup := newMockedWatchlistRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "watchlists"`) + `.*RETURNING "id"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "watchlist_tickers" ("watchlist_id","ticker","added_at")`)).
+		WithArgs(11, "AAPL", sqlmock.AnyArg(), 11, "MSFT", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	now := time.Now()
+	watchlist := &models.Watchlist{
+		Owner:   "user:alice",
+		Name:    "Tech",
+		Tickers: []models.WatchlistTicker{{Ticker: "AAPL", AddedAt: now}, {Ticker: "MSFT", AddedAt: now}},
+	}
+	if err := repo.CreateWatchlist(watchlist); err != nil {
+		t.Fatalf("CreateWatchlist error: %v", err)
+	}
+	if watchlist.ID != 11 || watchlist.Tickers[1].WatchlistID != 11 {
+		t.Fatalf("ids not propagated: %+v", watchlist)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestFindWatchlist_ScopedByOwner(t *testing.T) {
+	repo, mock, cleanup := newMockedWatchlistRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "watchlists" WHERE owner = $1 AND id = $2 ORDER BY "watchlists"."id" LIMIT $3`)).
+		WithArgs("user:alice", 11, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner", "name"}).AddRow(11, "user:alice", "Tech"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "watchlist_tickers" WHERE "watchlist_tickers"."watchlist_id" = $1 ORDER BY added_at, ticker`)).
+		WithArgs(11).
+		WillReturnRows(sqlmock.NewRows([]string{"watchlist_id", "ticker"}).AddRow(11, "AAPL"))
+
+	watchlist, err := repo.FindWatchlist("user:alice", 11)
+	if err != nil || len(watchlist.Tickers) != 1 || watchlist.Tickers[0].Ticker != "AAPL" {
+		t.Fatalf("FindWatchlist watchlist=%+v err=%v", watchlist, err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "watchlists" WHERE owner = $1 AND id = $2`)).
+		WithArgs("user:bob", 11, 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+	if _, err := repo.FindWatchlist("user:bob", 11); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for another owner, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUpdateWatchlist_ReplacesTickers(t *testing.T) {
+	repo, mock, cleanup := newMockedWatchlistRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "watchlists" SET "name"=$1,"notes"=$2,"updated_at"=$3 WHERE owner = $4 AND id = $5`)).
+		WithArgs("Tech", "long term", sqlmock.AnyArg(), "user:alice", 11).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "watchlist_tickers" WHERE watchlist_id = $1`)).
+		WithArgs(11).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "watchlist_tickers"`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	watchlist := &models.Watchlist{
+		ID:      11,
+		Owner:   "user:alice",
+		Name:    "Tech",
+		Notes:   "long term",
+		Tickers: []models.WatchlistTicker{{Ticker: "NVDA", AddedAt: time.Now()}},
+	}
+	if err := repo.UpdateWatchlist(watchlist); err != nil {
+		t.Fatalf("UpdateWatchlist error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestDeleteWatchlist_NotFound(t *testing.T) {
+	repo, mock, cleanup := newMockedWatchlistRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "watchlists" WHERE owner = $1 AND id = $2`)).
+		WithArgs("user:bob", 11).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	if err := repo.DeleteWatchlist("user:bob", 11); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package gormrepo
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+func (r *WebhookRepository) ListWebhooks(owner string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := r.db.Where("owner = ?", owner).Order("created_at, id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) ListEnabledWebhooks() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	if err := r.db.Where("enabled = ?", true).Order("id").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) FindWebhook(owner string, id uint64) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.Where("owner = ? AND id = ?", owner, id).First(&webhook).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotFound
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) UpdateWebhook(webhook *models.Webhook) error {
+	result := r.db.Model(&models.Webhook{}).
+		Where("owner = ? AND id = ?", webhook.Owner, webhook.ID).
+		Select("url", "secret", "event_types", "enabled", "updated_at").
+		Updates(webhook)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(owner string, id uint64) error {
+	result := r.db.Where("owner = ? AND id = ?", owner, id).Delete(&models.Webhook{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotFound
+	}
+	return nil
+}
+
+func (r *WebhookRepository) EnqueueDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Omit(clause.Associations).CreateInBatches(deliveries, 200).Error
+}
+
+func (r *WebhookRepository) DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at, id").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "last_error", "delivered_at", "updated_at").
+		Updates(delivery).Error
+}
+
+func (r *WebhookRepository) ListDeliveries(webhookID uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	q := r.db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", webhookID)
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := q.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+	return deliveries, total, nil
+}
@@ -0,0 +1,105 @@
+package gormrepo
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func newMockedWebhookRepo(t *testing.T) (*WebhookRepository, sqlmock.Sqlmock, func()) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New error: %v", err)
+	}
+
+	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open error: %v", err)
+	}
+
+	return NewWebhookRepository(gdb), mock, func() { _ = sqlDB.Close() }
+}
+
+func TestDueDeliveries_PendingOldestFirstWithWebhook(t *testing.T) {
+	repo, mock, cleanup := newMockedWebhookRepo(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at, id LIMIT $3`)).
+		WithArgs(models.WebhookDeliveryPending, now, 50).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event_type", "status"}).AddRow(9, 4, "stock.created", "pending"))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhooks" WHERE "webhooks"."id" = $1`)).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "secret", "event_types", "enabled"}).
+			AddRow(4, "https://hooks.example.com", "secret", `["stock.created"]`, true))
+
+	due, err := repo.DueDeliveries(now, 50)
+	if err != nil || len(due) != 1 {
+		t.Fatalf("DueDeliveries len=%d err=%v", len(due), err)
+	}
+	if due[0].Webhook == nil || due[0].Webhook.URL != "https://hooks.example.com" || !due[0].Webhook.Subscribes(models.WebhookEventStockCreated) {
+		t.Fatalf("webhook not preloaded: %+v", due[0].Webhook)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestUpdateWebhook_ScopedByOwner(t *testing.T) {
+	repo, mock, cleanup := newMockedWebhookRepo(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhooks" SET "url"=$1,"secret"=$2,"event_types"=$3,"enabled"=$4,"updated_at"=$5 WHERE owner = $6 AND id = $7`)).
+		WithArgs("https://hooks.example.com", "a-very-long-shared-secret", `["sync.completed"]`, true, sqlmock.AnyArg(), "user:bob", 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	webhook := &models.Webhook{
+		ID:         3,
+		Owner:      "user:bob",
+		URL:        "https://hooks.example.com",
+		Secret:     "a-very-long-shared-secret",
+		EventTypes: []models.WebhookEventType{models.WebhookEventSyncCompleted},
+		Enabled:    true,
+		UpdatedAt:  time.Now(),
+	}
+	if err := repo.UpdateWebhook(webhook); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
+
+func TestListDeliveries_FiltersByStatus(t *testing.T) {
+	repo, mock, cleanup := newMockedWebhookRepo(t)
+	defer cleanup()
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "webhook_deliveries" WHERE webhook_id = $1 AND status = $2`)).
+		WithArgs(4, models.WebhookDeliveryFailed).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE webhook_id = $1 AND status = $2 ORDER BY created_at DESC, id DESC LIMIT $3`)).
+		WithArgs(4, models.WebhookDeliveryFailed, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "status", "attempts", "last_error"}).AddRow(9, 4, "failed", 8, "HTTP 500"))
+
+	deliveries, total, err := repo.ListDeliveries(4, models.WebhookDeliveryFailed, 20, 0)
+	if err != nil || total != 1 || len(deliveries) != 1 || deliveries[0].Attempts != 8 {
+		t.Fatalf("ListDeliveries total=%d err=%v deliveries=%+v", total, err, deliveries)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("expectations: %v", err)
+	}
+}
@@ -0,0 +1,15 @@
+package repositories
+
+import "github.com/Hitomiblood/StockStream/internal/models"
+
+// RatingMappingRepository abstracts persistence for the rating taxonomy overrides.
+type RatingMappingRepository interface {
+	ListMappings() ([]models.RatingMapping, error)
+	SaveMapping(mapping *models.RatingMapping) error
+	// ListUnmapped aggregates raw rating_from/rating_to values that were normalized as unmapped.
+	ListUnmapped() ([]models.UnmappedRating, error)
+	// DistinctRawRatings returns every raw rating string stored in either rating column.
+	DistinctRawRatings() ([]string, error)
+	// ApplyMapping rewrites the normalized columns of stored rows whose raw rating is in rawValues.
+	ApplyMapping(rawValues []string, canonical models.CanonicalRating, score float64) (int64, error)
+}
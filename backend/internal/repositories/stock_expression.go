@@ -0,0 +1,26 @@
+package repositories
+
+// ExpressionOp identifies the kind of a StockExpression node.
+type ExpressionOp string
+
+const (
+	ExprAnd ExpressionOp = "and"
+	ExprOr  ExpressionOp = "or"
+
+	ExprEq       ExpressionOp = "eq"
+	ExprNe       ExpressionOp = "ne"
+	ExprIn       ExpressionOp = "in"
+	ExprGt       ExpressionOp = "gt"
+	ExprLt       ExpressionOp = "lt"
+	ExprContains ExpressionOp = "contains"
+)
+
+// StockExpression is a parsed boolean filter over stock columns.
+// Logical nodes (and/or) only use Children; comparison nodes use Field and Values,
+// where Values already hold typed literals (string, float64, uint64 or time.Time).
+type StockExpression struct {
+	Op       ExpressionOp
+	Field    string
+	Values   []any
+	Children []*StockExpression
+}
@@ -10,23 +10,109 @@ import (
 // ErrNotFound is returned when a repository lookup yields no results.
 var ErrNotFound = errors.New("stock repository: not found")
 
+// StockFilter groups the optional criteria accepted by Filter, List and Count; empty fields are ignored.
+type StockFilter struct {
+	Action          string
+	Rating          string
+	CanonicalRating models.CanonicalRating
+	EventType       models.ActionEvent
+
+	// Inclusive ranges over the numeric target columns; nil bounds are open.
+	MinTargetFrom *float64
+	MaxTargetFrom *float64
+	MinTargetTo   *float64
+	MaxTargetTo   *float64
+	MinChangePct  *float64
+	MaxChangePct  *float64
+	Currency      string
+
+	// Inclusive bounds on the call time; nil bounds are open.
+	TimeFrom *time.Time
+	TimeTo   *time.Time
+	// Brokerages match case-insensitively; Tickers are expected upper-cased.
+	Brokerages []string
+	Tickers    []string
+
+	// Expression is an optional user-built filter ANDed with the fields above.
+	Expression *StockExpression
+}
+
+// NullSortKey stands in for NULL numeric target columns in keyset ordering so every row has a comparable key.
+const NullSortKey = -1e18
+
+// StockCursor is a position in a keyset-ordered listing: the sort key of a row plus its id as tie-breaker.
+type StockCursor struct {
+	Key any
+	ID  uint64
+}
+
+// StockPageRequest asks for up to Limit rows strictly after Cursor in (SortField, id) order.
+// With Backward the scan runs in the opposite direction, returning rows strictly before Cursor,
+// nearest first.
+type StockPageRequest struct {
+	SortField string
+	Desc      bool
+	Cursor    *StockCursor
+	Backward  bool
+	Limit     int
+}
+
 // StockRepository abstracts persistence for stocks (services must not query the DB directly).
 type StockRepository interface {
 	GetByTickerAndTime(ticker string, t time.Time) (*models.Stock, error)
 	Create(stock *models.Stock) error
 	Save(stock *models.Stock) error
+	// UpdateDerivedColumns writes only the columns computed at ingest (ratings, event type and targets).
+	UpdateDerivedColumns(stock *models.Stock) error
 
-	CountAll() (int64, error)
-	List(limit, offset int, sortField string, desc bool) ([]models.Stock, error)
+	Count(filter StockFilter) (int64, error)
+	List(filter StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error)
+	ListPage(filter StockFilter, page StockPageRequest) ([]models.Stock, error)
 
 	FindByID(id uint64) (*models.Stock, error)
 	FindByTicker(ticker string) ([]models.Stock, error)
-	Search(query string, limit int) ([]models.Stock, error)
-	Filter(action, rating string, limit, offset int) ([]models.Stock, int64, error)
+	FindByTickers(tickers []string) ([]models.Stock, error)
+	SearchCandidates() ([]models.SearchCandidate, error)
+	LatestByTickers(tickers []string) ([]models.Stock, error)
+	Filter(filter StockFilter, limit, offset int) ([]models.Stock, int64, error)
 
 	DistinctActions() ([]string, error)
 	DistinctRatings() ([]string, error)
+	DistinctCanonicalRatings() ([]string, error)
+	DistinctBrokerages() ([]string, error)
+	BrokerageActivity() ([]models.BrokerageSummary, error)
+	// SentimentSeries counts analyst events per day, week or month (UTC) for the rows matching filter.
+	// Buckets without rows are omitted.
+	SentimentSeries(filter StockFilter, bucket models.TimelineBucket) ([]models.SentimentPoint, error)
+	// RatingTransitions counts rating_from -> rating_to pairs (raw or canonical) for the rows matching filter,
+	// skipping rows where either side is empty.
+	RatingTransitions(filter StockFilter, mode models.RatingMode) ([]models.RatingTransition, error)
+	// CurrentRatingDistribution counts tickers by the rating of their latest rated call.
+	CurrentRatingDistribution(mode models.RatingMode) ([]models.RatingShare, error)
+	CountByEventType() ([]models.EventTypeCount, error)
+	TickerActivity() ([]models.TickerSuggestion, error)
 	Latest(limit int) ([]models.Stock, error)
 
 	FindSince(since time.Time) ([]models.Stock, error)
 }
+
+// StockRatingRepository exposes the batch access used to backfill normalized rating columns.
+type StockRatingRepository interface {
+	// FindRatingBackfillBatch returns rows with id > afterID that have a raw rating but no canonical one, ordered by id.
+	FindRatingBackfillBatch(afterID uint64, limit int) ([]models.Stock, error)
+	UpdateRatingValues(stock *models.Stock) error
+}
+
+// StockEventTypeRepository exposes the batch access used to backfill the classified event type.
+type StockEventTypeRepository interface {
+	// FindEventTypeBackfillBatch returns rows with id > afterID that have an action but no event type, ordered by id.
+	FindEventTypeBackfillBatch(afterID uint64, limit int) ([]models.Stock, error)
+	UpdateEventType(stock *models.Stock) error
+}
+
+// StockTargetRepository exposes the batch access used to backfill numeric target columns.
+type StockTargetRepository interface {
+	// FindTargetBackfillBatch returns rows with id > afterID whose numeric targets were never populated, ordered by id.
+	FindTargetBackfillBatch(afterID uint64, limit int) ([]models.Stock, error)
+	UpdateTargetValues(stock *models.Stock) error
+}
@@ -0,0 +1,12 @@
+package repositories
+
+import "github.com/Hitomiblood/StockStream/internal/models"
+
+// TickerRepository abstracts persistence for the ticker and company entity tables.
+type TickerRepository interface {
+	// SaveTicker upserts the company by normalized name, links it to the ticker and upserts the ticker.
+	// A nil company leaves the ticker without one.
+	SaveTicker(ticker *models.Ticker, company *models.Company) error
+	ListTickers(limit, offset int, sortField string, desc bool) ([]models.Ticker, int64, error)
+	FindTicker(ticker string) (*models.Ticker, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import "github.com/Hitomiblood/StockStream/internal/models"
+
+// WatchlistRepository abstracts persistence for user-owned watchlists.
+// Every lookup is scoped by owner; a watchlist of another owner behaves as missing (ErrNotFound).
+type WatchlistRepository interface {
+	// CreateWatchlist inserts the watchlist together with its tickers.
+	CreateWatchlist(watchlist *models.Watchlist) error
+	// ListWatchlists returns the owner's watchlists by name, with their tickers loaded.
+	ListWatchlists(owner string) ([]models.Watchlist, error)
+	FindWatchlist(owner string, id uint64) (*models.Watchlist, error)
+	// FindWatchlistByName is used to keep names unique per owner.
+	FindWatchlistByName(owner, name string) (*models.Watchlist, error)
+	// UpdateWatchlist saves name and notes and replaces the ticker set.
+	UpdateWatchlist(watchlist *models.Watchlist) error
+	DeleteWatchlist(owner string, id uint64) error
+}
@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+// WebhookRepository abstracts persistence for webhook subscriptions and their delivery queue.
+// Webhook lookups are scoped by owner; a webhook of another owner behaves as missing (ErrNotFound).
+type WebhookRepository interface {
+	CreateWebhook(webhook *models.Webhook) error
+	ListWebhooks(owner string) ([]models.Webhook, error)
+	// ListEnabledWebhooks returns the enabled webhooks of every owner, as notified on each event.
+	ListEnabledWebhooks() ([]models.Webhook, error)
+	FindWebhook(owner string, id uint64) (*models.Webhook, error)
+	// UpdateWebhook saves url, secret, event types and enabled.
+	UpdateWebhook(webhook *models.Webhook) error
+	// DeleteWebhook removes the webhook together with its deliveries.
+	DeleteWebhook(owner string, id uint64) error
+
+	EnqueueDeliveries(deliveries []models.WebhookDelivery) error
+	// DueDeliveries returns pending deliveries whose next attempt is at or before now,
+	// oldest first, with their webhook loaded.
+	DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error)
+	// UpdateDelivery saves the outcome of an attempt.
+	UpdateDelivery(delivery *models.WebhookDelivery) error
+	// ListDeliveries returns the newest deliveries of a webhook first; an empty status means any.
+	ListDeliveries(webhookID uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error)
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"log"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const eventTypeBackfillBatchSize = 500
+
+// ActionClassifier clasifica el texto libre de Action en un tipo de evento canónico.
+// Usa la misma tabla actionSignals que el scoring para que ambos nunca diverjan.
+type ActionClassifier struct{}
+
+// NewActionClassifier crea una nueva instancia del clasificador de acciones
+func NewActionClassifier() *ActionClassifier {
+	return &ActionClassifier{}
+}
+
+// Classify devuelve el tipo de evento de una acción; "" si está vacía y ActionEventOther si no se reconoce
+func (c *ActionClassifier) Classify(action string) models.ActionEvent {
+	normalized := normalizeText(action)
+	if normalized == "" {
+		return ""
+	}
+	if rule, ok := matchActionSignal(normalized); ok {
+		return rule.event
+	}
+	return models.ActionEventOther
+}
+
+// Enrich implementa StockEnricher rellenando EventType
+func (c *ActionClassifier) Enrich(stock *models.Stock) {
+	stock.EventType = c.Classify(stock.Action)
+}
+
+// Backfill recorre por lotes los registros con acción pero sin tipo de evento y los clasifica.
+// Devuelve cuántos registros se actualizaron.
+func (c *ActionClassifier) Backfill(repo repositories.StockEventTypeRepository) (int, error) {
+	updated := 0
+	var afterID uint64
+	for {
+		batch, err := repo.FindEventTypeBackfillBatch(afterID, eventTypeBackfillBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			stock := batch[i]
+			afterID = stock.ID
+			c.Enrich(&stock)
+			if err := repo.UpdateEventType(&stock); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		log.Printf("🔄 Event type backfill: %d records updated (last id %d)", updated, afterID)
+	}
+	return updated, nil
+}
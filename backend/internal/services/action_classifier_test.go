@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+func TestActionClassifier_Classify(t *testing.T) {
+	c := NewActionClassifier()
+
+	tests := []struct {
+		action string
+		want   models.ActionEvent
+	}{
+		{action: "target raised by", want: models.ActionEventTargetRaise},
+		{action: "Target Lowered By", want: models.ActionEventTargetCut},
+		{action: "upgraded by", want: models.ActionEventUpgrade},
+		{action: "downgraded by", want: models.ActionEventDowngrade},
+		{action: "initiated by", want: models.ActionEventInitiation},
+		{action: "reiterated by", want: models.ActionEventReiteration},
+		{action: "maintains", want: models.ActionEventReiteration},
+		{action: "coverage suspended", want: models.ActionEventCoverageDrop},
+		{action: "target set by", want: models.ActionEventOther},
+		{action: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := c.Classify(tt.action); got != tt.want {
+			t.Fatalf("Classify(%q) = %q, want %q", tt.action, got, tt.want)
+		}
+	}
+}
+
+func TestActionClassifier_EnrichOnlyRefreshesDerivedColumns(t *testing.T) {
+	stock := models.Stock{Ticker: "AAPL", Action: "upgraded by"}
+	NewActionClassifier().Enrich(&stock)
+	if stock.EventType != models.ActionEventUpgrade {
+		t.Fatalf("EventType = %q, want upgrade", stock.EventType)
+	}
+
+	svc := NewStockService(&fakeFetcher{}, &fakeRepo{})
+	legacy := models.Stock{Ticker: "AAPL", Action: "upgraded by"}
+	if svc.hasChanges(&legacy, &stock) || !hasDerivedChanges(&legacy, &stock) {
+		t.Fatalf("unclassified stored row should only need its derived columns refreshed")
+	}
+}
+
+type fakeEventTypeRepo struct {
+	rows    []models.Stock
+	updates map[uint64]models.Stock
+}
+
+func (f *fakeEventTypeRepo) FindEventTypeBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var out []models.Stock
+	for _, row := range f.rows {
+		if row.ID > afterID && len(out) < limit {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeEventTypeRepo) UpdateEventType(stock *models.Stock) error {
+	f.updates[stock.ID] = *stock
+	return nil
+}
+
+func TestActionClassifier_Backfill(t *testing.T) {
+	repo := &fakeEventTypeRepo{
+		rows: []models.Stock{
+			{ID: 1, Action: "upgraded by"},
+			{ID: 2, Action: "target lowered by"},
+			{ID: 3, Action: "target set by"},
+		},
+		updates: map[uint64]models.Stock{},
+	}
+
+	updated, err := NewActionClassifier().Backfill(repo)
+	if err != nil {
+		t.Fatalf("Backfill error: %v", err)
+	}
+	if updated != 3 {
+		t.Fatalf("updated = %d, want 3", updated)
+	}
+	if repo.updates[1].EventType != models.ActionEventUpgrade || repo.updates[2].EventType != models.ActionEventTargetCut || repo.updates[3].EventType != models.ActionEventOther {
+		t.Fatalf("unexpected backfilled rows: %+v", repo.updates)
+	}
+}
@@ -0,0 +1,325 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const maxAlertRuleNameLength = 100
+
+// ErrInvalidAlertRule se devuelve cuando el nombre o las condiciones de una regla no son válidos
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertRuleUpdate describe un cambio parcial de una regla; los campos nil se conservan
+type AlertRuleUpdate struct {
+	Name       *string
+	Conditions *models.AlertConditions
+	Enabled    *bool
+}
+
+// AlertEngine administra las reglas de alerta y las evalúa contra los registros de cada sincronización
+type AlertEngine struct {
+	repo       repositories.AlertRepository
+	watchlists repositories.WatchlistRepository
+	now        func() time.Time
+}
+
+// NewAlertEngine crea una nueva instancia del motor de alertas
+func NewAlertEngine(repo repositories.AlertRepository, watchlists repositories.WatchlistRepository) *AlertEngine {
+	return &AlertEngine{repo: repo, watchlists: watchlists, now: time.Now}
+}
+
+// ListRules devuelve las reglas del dueño ordenadas por nombre
+func (e *AlertEngine) ListRules(owner string) ([]models.AlertRule, error) {
+	rules, err := e.repo.ListRules(owner)
+	if err != nil {
+		return nil, err
+	}
+	if rules == nil {
+		rules = []models.AlertRule{}
+	}
+	return rules, nil
+}
+
+// GetRule devuelve una regla del dueño o ErrNotFound
+func (e *AlertEngine) GetRule(owner string, id uint64) (*models.AlertRule, error) {
+	return e.repo.FindRule(owner, id)
+}
+
+// CreateRule valida y guarda una nueva regla
+func (e *AlertEngine) CreateRule(owner, name string, conditions models.AlertConditions, enabled bool) (*models.AlertRule, error) {
+	name, err := validateAlertRuleName(name)
+	if err != nil {
+		return nil, err
+	}
+	conditions, err = e.normalizeConditions(owner, conditions)
+	if err != nil {
+		return nil, err
+	}
+
+	now := e.now()
+	rule := &models.AlertRule{
+		Owner:      owner,
+		Name:       name,
+		Conditions: conditions,
+		Enabled:    enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := e.repo.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule aplica un cambio parcial; si llegan condiciones reemplazan a las anteriores
+func (e *AlertEngine) UpdateRule(owner string, id uint64, update AlertRuleUpdate) (*models.AlertRule, error) {
+	rule, err := e.repo.FindRule(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name, err := validateAlertRuleName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		rule.Name = name
+	}
+	if update.Conditions != nil {
+		conditions, err := e.normalizeConditions(owner, *update.Conditions)
+		if err != nil {
+			return nil, err
+		}
+		rule.Conditions = conditions
+	}
+	if update.Enabled != nil {
+		rule.Enabled = *update.Enabled
+	}
+
+	rule.UpdatedAt = e.now()
+	if err := e.repo.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// DeleteRule elimina la regla; las alertas que ya disparó se conservan
+func (e *AlertEngine) DeleteRule(owner string, id uint64) error {
+	return e.repo.DeleteRule(owner, id)
+}
+
+// ListAlerts devuelve las alertas disparadas para el dueño del filtro, de la más reciente a la más antigua
+func (e *AlertEngine) ListAlerts(filter repositories.AlertFilter, limit, offset int) ([]models.Alert, int64, error) {
+	alerts, total, err := e.repo.ListAlerts(filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if alerts == nil {
+		alerts = []models.Alert{}
+	}
+	return alerts, total, nil
+}
+
+// Evaluate compara cada regla activa con los registros recibidos y guarda las alertas nuevas
+func (e *AlertEngine) Evaluate(stocks []models.Stock) (int64, error) {
+	if len(stocks) == 0 {
+		return 0, nil
+	}
+
+	rules, err := e.repo.ListEnabledRules()
+	if err != nil {
+		return 0, err
+	}
+
+	firedAt := e.now()
+	alerts := make([]models.Alert, 0)
+	for _, rule := range rules {
+		tickers, err := e.ruleTickers(rule)
+		if err != nil {
+			return 0, err
+		}
+		for _, stock := range stocks {
+			if !alertMatches(rule.Conditions, tickers, stock) {
+				continue
+			}
+			alerts = append(alerts, models.Alert{
+				RuleID:   rule.ID,
+				RuleName: rule.Name,
+				Owner:    rule.Owner,
+				StockID:  stock.ID,
+				Ticker:   stock.Ticker,
+				Summary:  alertSummary(stock),
+				FiredAt:  firedAt,
+			})
+		}
+	}
+	return e.repo.SaveAlerts(alerts)
+}
+
+// AfterSync implementa SyncListener evaluando las reglas contra los registros nuevos o modificados
+func (e *AlertEngine) AfterSync(changed []models.Stock) {
+	if len(changed) == 0 {
+		return
+	}
+
+	fired, err := e.Evaluate(changed)
+	if err != nil {
+		log.Printf("⚠️  Alert evaluation failed: %v", err)
+		return
+	}
+	log.Printf("✅ Alert evaluation completed: %d alerts fired over %d records", fired, len(changed))
+}
+
+// ruleTickers resuelve el conjunto de tickers de la regla; nil significa sin restricción.
+// Si la watchlist referenciada ya no existe la regla no coincide con ningún registro
+func (e *AlertEngine) ruleTickers(rule models.AlertRule) (map[string]bool, error) {
+	conditions := rule.Conditions
+	if len(conditions.Tickers) == 0 && conditions.WatchlistID == 0 {
+		return nil, nil
+	}
+
+	tickers := make(map[string]bool, len(conditions.Tickers))
+	for _, ticker := range conditions.Tickers {
+		tickers[ticker] = true
+	}
+	if conditions.WatchlistID == 0 {
+		return tickers, nil
+	}
+
+	watchlist, err := e.watchlists.FindWatchlist(rule.Owner, conditions.WatchlistID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return map[string]bool{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	inWatchlist := make(map[string]bool, len(watchlist.Tickers))
+	for _, ticker := range watchlist.Symbols() {
+		if len(conditions.Tickers) == 0 || tickers[ticker] {
+			inWatchlist[ticker] = true
+		}
+	}
+	return inWatchlist, nil
+}
+
+// normalizeConditions valida las condiciones y las deja en forma canónica
+func (e *AlertEngine) normalizeConditions(owner string, conditions models.AlertConditions) (models.AlertConditions, error) {
+	tickers, err := normalizeTickerList(conditions.Tickers)
+	if err != nil {
+		return conditions, fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+	}
+	conditions.Tickers = tickers
+
+	brokerages := make([]string, 0, len(conditions.Brokerages))
+	for _, brokerage := range conditions.Brokerages {
+		if brokerage = strings.TrimSpace(brokerage); brokerage != "" {
+			brokerages = append(brokerages, brokerage)
+		}
+	}
+	conditions.Brokerages = brokerages
+
+	for i, raw := range conditions.EventTypes {
+		event, ok := models.ParseActionEvent(string(raw))
+		if !ok {
+			return conditions, fmt.Errorf("%w: invalid event type %q", ErrInvalidAlertRule, raw)
+		}
+		conditions.EventTypes[i] = event
+	}
+	for _, ratings := range [][]models.CanonicalRating{conditions.RatingFrom, conditions.RatingTo} {
+		for i, raw := range ratings {
+			rating, ok := models.ParseCanonicalRating(string(raw))
+			if !ok {
+				return conditions, fmt.Errorf("%w: invalid canonical rating %q", ErrInvalidAlertRule, raw)
+			}
+			ratings[i] = rating
+		}
+	}
+
+	if conditions.MinTargetChangePct != nil && conditions.MaxTargetChangePct != nil &&
+		*conditions.MinTargetChangePct > *conditions.MaxTargetChangePct {
+		return conditions, fmt.Errorf("%w: min_target_change_pct cannot exceed max_target_change_pct", ErrInvalidAlertRule)
+	}
+
+	if conditions.WatchlistID != 0 {
+		if _, err := e.watchlists.FindWatchlist(owner, conditions.WatchlistID); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return conditions, fmt.Errorf("%w: watchlist %d not found", ErrInvalidAlertRule, conditions.WatchlistID)
+			}
+			return conditions, err
+		}
+	}
+
+	if conditions.IsEmpty() {
+		return conditions, fmt.Errorf("%w: at least one condition is required", ErrInvalidAlertRule)
+	}
+	return conditions, nil
+}
+
+func validateAlertRuleName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+	if utf8.RuneCountInString(name) > maxAlertRuleNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidAlertRule, maxAlertRuleNameLength)
+	}
+	return name, nil
+}
+
+// alertMatches indica si un registro cumple todas las condiciones de la regla
+func alertMatches(conditions models.AlertConditions, tickers map[string]bool, stock models.Stock) bool {
+	if tickers != nil && !tickers[stock.Ticker] {
+		return false
+	}
+	if len(conditions.Brokerages) > 0 && !containsFold(conditions.Brokerages, brokerageKey(stock.Brokerage)) {
+		return false
+	}
+	if len(conditions.EventTypes) > 0 && !slices.Contains(conditions.EventTypes, stock.EventType) {
+		return false
+	}
+	if len(conditions.RatingFrom) > 0 && !slices.Contains(conditions.RatingFrom, stock.RatingFromCanonical) {
+		return false
+	}
+	if len(conditions.RatingTo) > 0 && !slices.Contains(conditions.RatingTo, stock.RatingToCanonical) {
+		return false
+	}
+	if conditions.MinTargetChangePct != nil || conditions.MaxTargetChangePct != nil {
+		if stock.TargetChangePct == nil {
+			return false
+		}
+		change := *stock.TargetChangePct
+		if conditions.MinTargetChangePct != nil && change < *conditions.MinTargetChangePct {
+			return false
+		}
+		if conditions.MaxTargetChangePct != nil && change > *conditions.MaxTargetChangePct {
+			return false
+		}
+	}
+	return true
+}
+
+// alertSummary describe el registro que disparó la alerta en una línea
+func alertSummary(stock models.Stock) string {
+	action := strings.ToLower(strings.TrimSpace(stock.Action))
+	if !strings.HasSuffix(action, " by") {
+		action += " by"
+	}
+	summary := fmt.Sprintf("%s %s %s", stock.Ticker, action, strings.TrimSpace(stock.Brokerage))
+	if stock.RatingFrom != "" && stock.RatingTo != "" && stock.RatingFrom != stock.RatingTo {
+		summary += fmt.Sprintf(": %s → %s", stock.RatingFrom, stock.RatingTo)
+	}
+	if stock.TargetChangePct != nil {
+		summary += fmt.Sprintf(", target %s → %s (%+.1f%%)", stock.TargetFrom, stock.TargetTo, *stock.TargetChangePct)
+	}
+	return summary
+}
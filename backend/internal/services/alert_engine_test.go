@@ -0,0 +1,199 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+type fakeAlertRepo struct {
+	rules  map[uint64]models.AlertRule
+	nextID uint64
+	fired  map[[2]uint64]models.Alert
+}
+
+func (f *fakeAlertRepo) CreateRule(rule *models.AlertRule) error {
+	if f.rules == nil {
+		f.rules = map[uint64]models.AlertRule{}
+	}
+	f.nextID++
+	rule.ID = f.nextID
+	f.rules[rule.ID] = *rule
+	return nil
+}
+
+func (f *fakeAlertRepo) ListRules(owner string) ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	for _, rule := range f.rules {
+		if rule.Owner == owner {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeAlertRepo) ListEnabledRules() ([]models.AlertRule, error) {
+	var rules []models.AlertRule
+	for id := uint64(1); id <= f.nextID; id++ {
+		if rule, ok := f.rules[id]; ok && rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeAlertRepo) FindRule(owner string, id uint64) (*models.AlertRule, error) {
+	rule, ok := f.rules[id]
+	if !ok || rule.Owner != owner {
+		return nil, repositories.ErrNotFound
+	}
+	return &rule, nil
+}
+
+func (f *fakeAlertRepo) UpdateRule(rule *models.AlertRule) error {
+	if _, err := f.FindRule(rule.Owner, rule.ID); err != nil {
+		return err
+	}
+	f.rules[rule.ID] = *rule
+	return nil
+}
+
+func (f *fakeAlertRepo) DeleteRule(owner string, id uint64) error {
+	if _, err := f.FindRule(owner, id); err != nil {
+		return err
+	}
+	delete(f.rules, id)
+	return nil
+}
+
+func (f *fakeAlertRepo) SaveAlerts(alerts []models.Alert) (int64, error) {
+	if f.fired == nil {
+		f.fired = map[[2]uint64]models.Alert{}
+	}
+	var saved int64
+	for _, alert := range alerts {
+		key := [2]uint64{alert.RuleID, alert.StockID}
+		if _, ok := f.fired[key]; ok {
+			continue
+		}
+		f.fired[key] = alert
+		saved++
+	}
+	return saved, nil
+}
+
+func (f *fakeAlertRepo) ListAlerts(repositories.AlertFilter, int, int) ([]models.Alert, int64, error) {
+	return nil, 0, nil
+}
+
+func pct(value float64) *float64 {
+	return &value
+}
+
+func TestAlertEngine_EvaluatesWatchlistDowngradeAndTargetRaise(t *testing.T) {
+	watchlists := &fakeWatchlistRepo{}
+	mine := &models.Watchlist{Owner: "user:alice", Name: "Mine", Tickers: []models.WatchlistTicker{{Ticker: "AAPL"}, {Ticker: "MSFT"}}}
+	_ = watchlists.CreateWatchlist(mine)
+
+	repo := &fakeAlertRepo{}
+	engine := NewAlertEngine(repo, watchlists)
+	downgrades, err := engine.CreateRule("user:alice", "Buy to sell on my watchlist", models.AlertConditions{
+		WatchlistID: mine.ID,
+		RatingFrom:  []models.CanonicalRating{"Buy"},
+		RatingTo:    []models.CanonicalRating{"strong-sell", "sell"},
+	}, true)
+	if err != nil {
+		t.Fatalf("CreateRule error: %v", err)
+	}
+	if downgrades.Conditions.RatingFrom[0] != models.RatingBuy || downgrades.Conditions.RatingTo[0] != models.RatingStrongSell {
+		t.Fatalf("ratings should be canonicalized: %+v", downgrades.Conditions)
+	}
+	if _, err := engine.CreateRule("user:bob", "Goldman raises", models.AlertConditions{
+		Brokerages:         []string{"goldman sachs"},
+		MinTargetChangePct: pct(15),
+	}, true); err != nil {
+		t.Fatalf("CreateRule error: %v", err)
+	}
+	if _, err := engine.CreateRule("user:bob", "Paused", models.AlertConditions{Tickers: []string{"aapl"}}, false); err != nil {
+		t.Fatalf("CreateRule error: %v", err)
+	}
+
+	changed := []models.Stock{
+		{ID: 1, Ticker: "AAPL", Brokerage: "UBS", Action: "Downgraded by", RatingFrom: "Buy", RatingTo: "Sell", RatingFromCanonical: models.RatingBuy, RatingToCanonical: models.RatingSell},
+		{ID: 2, Ticker: "TSLA", Brokerage: "UBS", Action: "Downgraded by", RatingFromCanonical: models.RatingBuy, RatingToCanonical: models.RatingSell},
+		{ID: 3, Ticker: "NVDA", Brokerage: "Goldman Sachs", Action: "Target raised by", TargetFrom: "$100.00", TargetTo: "$120.00", TargetChangePct: pct(20)},
+		{ID: 4, Ticker: "AMD", Brokerage: "Goldman Sachs", Action: "Target raised by", TargetChangePct: pct(10)},
+		{ID: 5, Ticker: "MSFT", Brokerage: "Goldman Sachs", Action: "Reiterated by"},
+	}
+	fired, err := engine.Evaluate(changed)
+	if err != nil {
+		t.Fatalf("Evaluate error: %v", err)
+	}
+	if fired != 2 {
+		t.Fatalf("fired = %d, want 2 (%+v)", fired, repo.fired)
+	}
+
+	downgrade, ok := repo.fired[[2]uint64{downgrades.ID, 1}]
+	if !ok || downgrade.Owner != "user:alice" || downgrade.RuleName != "Buy to sell on my watchlist" {
+		t.Fatalf("missing watchlist downgrade alert: %+v", repo.fired)
+	}
+	raise, ok := repo.fired[[2]uint64{2, 3}]
+	if !ok || !strings.HasPrefix(raise.Summary, "NVDA target raised by Goldman Sachs, target $100.00 → $120.00 (+20.0%)") {
+		t.Fatalf("missing target raise alert: %+v", repo.fired)
+	}
+
+	// Un registro actualizado que vuelve a cumplir la regla no la dispara de nuevo
+	if fired, _ := engine.Evaluate(changed[:1]); fired != 0 {
+		t.Fatalf("re-evaluating the same record fired %d alerts", fired)
+	}
+}
+
+func TestAlertEngine_RejectsInvalidRules(t *testing.T) {
+	engine := NewAlertEngine(&fakeAlertRepo{}, &fakeWatchlistRepo{})
+
+	tests := map[string]models.AlertConditions{
+		"no conditions":   {},
+		"bad event":       {EventTypes: []models.ActionEvent{"split"}},
+		"bad rating":      {RatingTo: []models.CanonicalRating{"moon"}},
+		"inverted range":  {MinTargetChangePct: pct(20), MaxTargetChangePct: pct(10)},
+		"other watchlist": {WatchlistID: 99},
+	}
+	for name, conditions := range tests {
+		if _, err := engine.CreateRule("user:alice", "Rule", conditions, true); !errors.Is(err, ErrInvalidAlertRule) {
+			t.Fatalf("%s: expected ErrInvalidAlertRule, got %v", name, err)
+		}
+	}
+
+	rule, _ := engine.CreateRule("user:alice", "Rule", models.AlertConditions{EventTypes: []models.ActionEvent{"target-raise"}}, true)
+	disabled := false
+	updated, err := engine.UpdateRule("user:alice", rule.ID, AlertRuleUpdate{Enabled: &disabled})
+	if err != nil || updated.Enabled || updated.Conditions.EventTypes[0] != models.ActionEventTargetRaise {
+		t.Fatalf("UpdateRule rule=%+v err=%v", updated, err)
+	}
+	if _, err := engine.UpdateRule("user:bob", rule.ID, AlertRuleUpdate{Enabled: &disabled}); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("another owner must not see the rule, got %v", err)
+	}
+}
+
+func TestAlertEngine_DeletedWatchlistMatchesNothing(t *testing.T) {
+	watchlists := &fakeWatchlistRepo{}
+	mine := &models.Watchlist{Owner: "user:alice", Name: "Mine", Tickers: []models.WatchlistTicker{{Ticker: "AAPL"}}}
+	_ = watchlists.CreateWatchlist(mine)
+
+	repo := &fakeAlertRepo{}
+	engine := NewAlertEngine(repo, watchlists)
+	engine.now = func() time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC) }
+	if _, err := engine.CreateRule("user:alice", "Mine", models.AlertConditions{WatchlistID: mine.ID}, true); err != nil {
+		t.Fatalf("CreateRule error: %v", err)
+	}
+	_ = watchlists.DeleteWatchlist("user:alice", mine.ID)
+
+	fired, err := engine.Evaluate([]models.Stock{{ID: 1, Ticker: "AAPL"}})
+	if err != nil || fired != 0 {
+		t.Fatalf("fired=%d err=%v, want no alerts", fired, err)
+	}
+}
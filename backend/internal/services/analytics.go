@@ -0,0 +1,181 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// AnalyticsService calcula vistas agregadas del mercado sobre el historial de stocks
+type AnalyticsService struct {
+	repo repositories.StockRepository
+}
+
+// NewAnalyticsService crea una nueva instancia del servicio de analítica
+func NewAnalyticsService(repo repositories.StockRepository) *AnalyticsService {
+	return &AnalyticsService{repo: repo}
+}
+
+// GetSentiment devuelve el momentum de upgrades/downgrades por intervalo. Los conteos se agregan en
+// la base de datos; aquí solo se completan los intervalos vacíos entre el primero y el último con
+// datos y se calcula el índice de sentimiento neto.
+func (a *AnalyticsService) GetSentiment(filter repositories.StockFilter, bucket models.TimelineBucket) (*models.SentimentSeries, error) {
+	points, err := a.repo.SentimentSeries(filter, bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	series := &models.SentimentSeries{
+		Bucket:     bucket,
+		From:       filter.TimeFrom,
+		To:         filter.TimeTo,
+		Brokerages: filter.Brokerages,
+		Tickers:    filter.Tickers,
+		Points:     []models.SentimentPoint{},
+	}
+	if len(points) == 0 {
+		return series, nil
+	}
+
+	byStart := make(map[int64]models.SentimentPoint, len(points))
+	for _, point := range points {
+		start := timelineBucketStart(point.Start, bucket)
+		byStart[start.Unix()] = point
+
+		series.Summary.Total += point.Total
+		series.Summary.Upgrades += point.Upgrades
+		series.Summary.Downgrades += point.Downgrades
+		series.Summary.TargetRaises += point.TargetRaises
+		series.Summary.TargetCuts += point.TargetCuts
+		series.Summary.Initiations += point.Initiations
+	}
+
+	first := timelineBucketStart(points[0].Start, bucket)
+	last := timelineBucketStart(points[len(points)-1].Start, bucket)
+	for start := first; !start.After(last); start = nextTimelineBucket(start, bucket) {
+		point, ok := byStart[start.Unix()]
+		if !ok {
+			point = models.SentimentPoint{}
+		}
+		point.Start = start
+		point.NetSentiment = netSentiment(point)
+		series.Points = append(series.Points, point)
+	}
+
+	series.Summary.Start = first
+	series.Summary.NetSentiment = netSentiment(series.Summary)
+	return series, nil
+}
+
+// GetRatingTransitions arma la matriz de transiciones de rating (cruda o canónica) del período.
+// En modo canónico los ejes siguen la taxonomía de más alcista a más bajista; en modo crudo,
+// la frecuencia de cada rating.
+func (a *AnalyticsService) GetRatingTransitions(filter repositories.StockFilter, mode models.RatingMode) (*models.RatingTransitionMatrix, error) {
+	transitions, err := a.repo.RatingTransitions(filter, mode)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix := &models.RatingTransitionMatrix{
+		Mode:        mode,
+		From:        filter.TimeFrom,
+		To:          filter.TimeTo,
+		Ratings:     []string{},
+		Counts:      [][]int64{},
+		Transitions: []models.RatingTransition{},
+	}
+
+	rowTotals := make(map[string]int64)
+	frequency := make(map[string]int64)
+	for _, transition := range transitions {
+		matrix.Total += transition.Count
+		rowTotals[transition.RatingFrom] += transition.Count
+		frequency[transition.RatingFrom] += transition.Count
+		frequency[transition.RatingTo] += transition.Count
+	}
+	for _, transition := range transitions {
+		transition.Share = math.Round(float64(transition.Count)/float64(rowTotals[transition.RatingFrom])*10000) / 10000
+		matrix.Transitions = append(matrix.Transitions, transition)
+	}
+
+	matrix.Ratings = ratingAxis(mode, frequency)
+	position := make(map[string]int, len(matrix.Ratings))
+	for i, rating := range matrix.Ratings {
+		position[rating] = i
+		matrix.Counts = append(matrix.Counts, make([]int64, len(matrix.Ratings)))
+	}
+	for _, transition := range transitions {
+		matrix.Counts[position[transition.RatingFrom]][position[transition.RatingTo]] += transition.Count
+	}
+	return matrix, nil
+}
+
+// GetRatingDistribution cuenta los tickers según el rating de su última llamada con rating
+func (a *AnalyticsService) GetRatingDistribution(mode models.RatingMode) ([]models.RatingShare, int64, error) {
+	items, err := a.repo.CurrentRatingDistribution(mode)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	for _, item := range items {
+		total += item.Tickers
+	}
+	for i := range items {
+		items[i].Share = math.Round(float64(items[i].Tickers)/float64(total)*10000) / 10000
+	}
+	if items == nil {
+		items = []models.RatingShare{}
+	}
+	return items, total, nil
+}
+
+// ratingAxis ordena las etiquetas de la matriz. En modo canónico sigue la taxonomía y deja
+// unmapped al final; el resto de etiquetas (todas en modo crudo) va por frecuencia descendente.
+func ratingAxis(mode models.RatingMode, frequency map[string]int64) []string {
+	head := make([]string, 0)
+	tail := make([]string, 0)
+	rest := make([]string, 0, len(frequency))
+	if mode == models.RatingModeCanonical {
+		for _, rating := range models.CanonicalRatings {
+			if _, ok := frequency[string(rating)]; ok {
+				head = append(head, string(rating))
+			}
+		}
+		if _, ok := frequency[string(models.RatingUnmapped)]; ok {
+			tail = append(tail, string(models.RatingUnmapped))
+		}
+	}
+
+	placed := make(map[string]struct{}, len(head)+len(tail))
+	for _, rating := range append(head, tail...) {
+		placed[rating] = struct{}{}
+	}
+	for rating := range frequency {
+		if _, ok := placed[rating]; !ok {
+			rest = append(rest, rating)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		if frequency[rest[i]] != frequency[rest[j]] {
+			return frequency[rest[i]] > frequency[rest[j]]
+		}
+		return rest[i] < rest[j]
+	})
+
+	axis := append(head, rest...)
+	return append(axis, tail...)
+}
+
+// netSentiment compara los eventos alcistas (upgrades y subidas de target) con los bajistas
+// (downgrades y recortes) en una escala de -100 a 100; las iniciaciones no tienen dirección
+func netSentiment(point models.SentimentPoint) float64 {
+	positive := point.Upgrades + point.TargetRaises
+	negative := point.Downgrades + point.TargetCuts
+	if positive+negative == 0 {
+		return 0
+	}
+	return math.Round(float64(positive-negative)/float64(positive+negative)*10000) / 100
+}
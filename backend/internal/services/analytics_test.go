@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func TestGetSentiment_FillsGapsAndComputesIndex(t *testing.T) {
+	var gotFilter repositories.StockFilter
+	var gotBucket models.TimelineBucket
+	svc := NewAnalyticsService(&fakeRepo{
+		sentimentSeriesFn: func(filter repositories.StockFilter, bucket models.TimelineBucket) ([]models.SentimentPoint, error) {
+			gotFilter, gotBucket = filter, bucket
+			return []models.SentimentPoint{
+				{Start: time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), Total: 6, Upgrades: 3, Downgrades: 1, TargetRaises: 1, TargetCuts: 1},
+				{Start: time.Date(2026, 1, 19, 0, 0, 0, 0, time.UTC), Total: 2, Downgrades: 1, Initiations: 1},
+			}, nil
+		},
+	})
+
+	filter := repositories.StockFilter{Tickers: []string{"AAPL"}}
+	series, err := svc.GetSentiment(filter, models.TimelineBucketWeek)
+	if err != nil {
+		t.Fatalf("GetSentiment error: %v", err)
+	}
+	if gotBucket != models.TimelineBucketWeek || len(gotFilter.Tickers) != 1 {
+		t.Fatalf("repository got bucket=%q filter=%+v", gotBucket, gotFilter)
+	}
+
+	if len(series.Points) != 3 {
+		t.Fatalf("points = %+v, want 3 including the empty week", series.Points)
+	}
+	if series.Points[0].NetSentiment != 33.33 {
+		t.Fatalf("first week net sentiment = %v, want 33.33", series.Points[0].NetSentiment)
+	}
+	gap := series.Points[1]
+	if !gap.Start.Equal(time.Date(2026, 1, 12, 0, 0, 0, 0, time.UTC)) || gap.Total != 0 || gap.NetSentiment != 0 {
+		t.Fatalf("unexpected empty week: %+v", gap)
+	}
+	if series.Points[2].NetSentiment != -100 {
+		t.Fatalf("downgrade-only week net sentiment = %v, want -100", series.Points[2].NetSentiment)
+	}
+
+	if series.Summary.Total != 8 || series.Summary.Downgrades != 2 || series.Summary.NetSentiment != 14.29 {
+		t.Fatalf("unexpected summary: %+v", series.Summary)
+	}
+}
+
+func TestGetSentiment_EmptyAndError(t *testing.T) {
+	series, err := NewAnalyticsService(&fakeRepo{}).GetSentiment(repositories.StockFilter{}, models.TimelineBucketDay)
+	if err != nil || series.Points == nil || len(series.Points) != 0 {
+		t.Fatalf("expected an empty, non-nil series: %+v err=%v", series, err)
+	}
+
+	failing := NewAnalyticsService(&fakeRepo{
+		sentimentSeriesFn: func(repositories.StockFilter, models.TimelineBucket) ([]models.SentimentPoint, error) {
+			return nil, errors.New("db")
+		},
+	})
+	if _, err := failing.GetSentiment(repositories.StockFilter{}, models.TimelineBucketDay); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestGetRatingTransitions_MatrixAndShares(t *testing.T) {
+	svc := NewAnalyticsService(&fakeRepo{
+		ratingTransitionsFn: func(filter repositories.StockFilter, mode models.RatingMode) ([]models.RatingTransition, error) {
+			return []models.RatingTransition{
+				{RatingFrom: "hold", RatingTo: "buy", Count: 6},
+				{RatingFrom: "hold", RatingTo: "hold", Count: 3},
+				{RatingFrom: "buy", RatingTo: "hold", Count: 2},
+				{RatingFrom: "hold", RatingTo: "sell", Count: 1},
+				{RatingFrom: "unmapped", RatingTo: "buy", Count: 1},
+			}, nil
+		},
+	})
+
+	matrix, err := svc.GetRatingTransitions(repositories.StockFilter{}, models.RatingModeCanonical)
+	if err != nil {
+		t.Fatalf("GetRatingTransitions error: %v", err)
+	}
+	if got := strings.Join(matrix.Ratings, ","); got != "buy,hold,sell,unmapped" {
+		t.Fatalf("canonical axis = %s", got)
+	}
+	if matrix.Total != 13 || matrix.Counts[1][0] != 6 || matrix.Counts[1][2] != 1 || matrix.Counts[3][0] != 1 {
+		t.Fatalf("unexpected matrix: total=%d counts=%v", matrix.Total, matrix.Counts)
+	}
+	if matrix.Transitions[0].Share != 0.6 || matrix.Transitions[2].Share != 1 {
+		t.Fatalf("shares should be relative to the origin row: %+v", matrix.Transitions)
+	}
+}
+
+func TestGetRatingTransitions_RawAxisByFrequency(t *testing.T) {
+	svc := NewAnalyticsService(&fakeRepo{
+		ratingTransitionsFn: func(filter repositories.StockFilter, mode models.RatingMode) ([]models.RatingTransition, error) {
+			if mode != models.RatingModeRaw {
+				t.Fatalf("mode = %q", mode)
+			}
+			return []models.RatingTransition{
+				{RatingFrom: "Neutral", RatingTo: "Outperform", Count: 4},
+				{RatingFrom: "Outperform", RatingTo: "Market Perform", Count: 1},
+			}, nil
+		},
+	})
+
+	matrix, err := svc.GetRatingTransitions(repositories.StockFilter{}, models.RatingModeRaw)
+	if err != nil {
+		t.Fatalf("GetRatingTransitions error: %v", err)
+	}
+	if got := strings.Join(matrix.Ratings, ","); got != "Outperform,Neutral,Market Perform" {
+		t.Fatalf("raw axis = %s", got)
+	}
+
+	empty, err := NewAnalyticsService(&fakeRepo{}).GetRatingTransitions(repositories.StockFilter{}, models.RatingModeRaw)
+	if err != nil || empty.Total != 0 || empty.Ratings == nil || empty.Transitions == nil {
+		t.Fatalf("expected an empty matrix: %+v err=%v", empty, err)
+	}
+}
+
+func TestGetRatingDistribution(t *testing.T) {
+	svc := NewAnalyticsService(&fakeRepo{
+		ratingDistributionFn: func(mode models.RatingMode) ([]models.RatingShare, error) {
+			return []models.RatingShare{{Rating: "buy", Tickers: 6}, {Rating: "hold", Tickers: 3}, {Rating: "sell", Tickers: 1}}, nil
+		},
+	})
+
+	items, total, err := svc.GetRatingDistribution(models.RatingModeCanonical)
+	if err != nil || total != 10 || items[0].Share != 0.6 || items[2].Share != 0.1 {
+		t.Fatalf("items=%+v total=%d err=%v", items, total, err)
+	}
+
+	items, total, err = NewAnalyticsService(&fakeRepo{}).GetRatingDistribution(models.RatingModeRaw)
+	if err != nil || total != 0 || items == nil {
+		t.Fatalf("expected an empty, non-nil distribution: %+v err=%v", items, err)
+	}
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// Umbrales del detector de anomalías
+const (
+	spikeWindowDays   = 7
+	spikeBaselineDays = 90
+	spikeMinFirms     = 3
+	spikeMinRatio     = 3.0
+	spikeMediumRatio  = 4.0
+	spikeHighRatio    = 6.0
+
+	outlierWindowDays      = 90
+	outlierMinTargets      = 4
+	outlierMinDeviation    = 0.35
+	outlierMediumDeviation = 0.6
+	outlierHighDeviation   = 1.0
+)
+
+// AnomalyDetector busca actividad inusual de analistas y la persiste para consultarla después
+type AnomalyDetector struct {
+	stocks repositories.StockRepository
+	repo   repositories.AnomalyRepository
+	now    func() time.Time
+}
+
+// NewAnomalyDetector crea una nueva instancia del detector de anomalías
+func NewAnomalyDetector(stocks repositories.StockRepository, repo repositories.AnomalyRepository) *AnomalyDetector {
+	return &AnomalyDetector{stocks: stocks, repo: repo, now: time.Now}
+}
+
+// DetectTickers analiza el historial de cada ticker y guarda las anomalías encontradas
+func (d *AnomalyDetector) DetectTickers(tickers []string) (int, error) {
+	saved := 0
+	for _, ticker := range tickers {
+		history, err := d.stocks.FindByTicker(ticker)
+		if err != nil {
+			return saved, err
+		}
+
+		for _, anomaly := range detectAnomalies(ticker, history, d.now()) {
+			if err := d.repo.SaveAnomaly(&anomaly); err != nil {
+				return saved, err
+			}
+			saved++
+		}
+	}
+	return saved, nil
+}
+
+// DetectAll analiza todos los tickers presentes en stocks (para datos previos a la migración)
+func (d *AnomalyDetector) DetectAll() (int, error) {
+	activity, err := d.stocks.TickerActivity()
+	if err != nil {
+		return 0, err
+	}
+
+	tickers := make([]string, len(activity))
+	for i, item := range activity {
+		tickers[i] = item.Ticker
+	}
+	return d.DetectTickers(tickers)
+}
+
+// AfterSync implementa SyncListener analizando solo los tickers que cambiaron
+func (d *AnomalyDetector) AfterSync(changed []models.Stock) {
+	seen := make(map[string]struct{})
+	tickers := make([]string, 0)
+	for _, stock := range changed {
+		if _, ok := seen[stock.Ticker]; ok {
+			continue
+		}
+		seen[stock.Ticker] = struct{}{}
+		tickers = append(tickers, stock.Ticker)
+	}
+	if len(tickers) == 0 {
+		return
+	}
+
+	saved, err := d.DetectTickers(tickers)
+	if err != nil {
+		log.Printf("⚠️  Anomaly detection failed after %d anomalies: %v", saved, err)
+		return
+	}
+	log.Printf("✅ Anomaly detection completed: %d anomalies across %d tickers", saved, len(tickers))
+}
+
+// ListAnomalies lista las anomalías detectadas, las más recientes primero
+func (d *AnomalyDetector) ListAnomalies(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+	filter.Ticker = strings.ToUpper(strings.TrimSpace(filter.Ticker))
+	return d.repo.ListAnomalies(filter, limit, offset)
+}
+
+// detectAnomalies evalúa el historial de un ticker respecto de su llamada más reciente
+func detectAnomalies(ticker string, history []models.Stock, detectedAt time.Time) []models.Anomaly {
+	if len(history) == 0 {
+		return nil
+	}
+
+	latest := history[0].Time
+	for _, stock := range history {
+		if stock.Time.After(latest) {
+			latest = stock.Time
+		}
+	}
+
+	anomalies := make([]models.Anomaly, 0)
+	if spike, ok := detectActivitySpike(ticker, history, latest); ok {
+		anomalies = append(anomalies, spike)
+	}
+	anomalies = append(anomalies, detectTargetOutliers(ticker, history, latest)...)
+
+	for i := range anomalies {
+		anomalies[i].DetectedAt = detectedAt
+		anomalies[i].LastSeenAt = detectedAt
+	}
+	return anomalies
+}
+
+// detectActivitySpike compara las llamadas de la última semana con el ritmo semanal de los
+// 90 días previos; exige además varias firmas distintas para no marcar a una sola muy activa
+func detectActivitySpike(ticker string, history []models.Stock, latest time.Time) (models.Anomaly, bool) {
+	windowStart := latest.AddDate(0, 0, -spikeWindowDays)
+	baselineStart := windowStart.AddDate(0, 0, -spikeBaselineDays)
+
+	window := make([]models.Stock, 0)
+	firms := make(map[string]struct{})
+	baseline := 0
+	for _, stock := range history {
+		switch {
+		case stock.Time.After(windowStart):
+			window = append(window, stock)
+			if key := brokerageKey(stock.Brokerage); key != "" {
+				firms[key] = struct{}{}
+			}
+		case !stock.Time.Before(baselineStart):
+			baseline++
+		}
+	}
+
+	expected := float64(baseline) / (float64(spikeBaselineDays) / float64(spikeWindowDays))
+	ratio := float64(len(window)) / math.Max(expected, 1)
+	if len(firms) < spikeMinFirms || ratio < spikeMinRatio {
+		return models.Anomaly{}, false
+	}
+
+	sort.Slice(window, func(i, j int) bool { return window[i].Time.After(window[j].Time) })
+	ids := make([]uint64, len(window))
+	for i, stock := range window {
+		ids[i] = stock.ID
+	}
+
+	return models.Anomaly{
+		Fingerprint: fmt.Sprintf("%s:%s:%s", models.AnomalyActivitySpike, ticker, timelineBucketStart(latest, models.TimelineBucketWeek).Format("2006-01-02")),
+		Kind:        models.AnomalyActivitySpike,
+		Ticker:      ticker,
+		Severity:    anomalySeverity(ratio, spikeMediumRatio, spikeHighRatio),
+		Score:       math.Round(ratio*100) / 100,
+		Summary: fmt.Sprintf("%d firms made %d calls on %s within %d days, %.1fx its usual activity",
+			len(firms), len(window), ticker, spikeWindowDays, ratio),
+		WindowStart: windowStart,
+		WindowEnd:   latest,
+		StockIDs:    ids,
+	}, true
+}
+
+// detectTargetOutliers compara el precio objetivo vigente de cada firma con la mediana del resto.
+// Solo se comparan objetivos en la misma moneda
+func detectTargetOutliers(ticker string, history []models.Stock, latest time.Time) []models.Anomaly {
+	since := latest.AddDate(0, 0, -outlierWindowDays)
+	byCurrency := make(map[string][]models.Stock)
+	for _, stock := range latestCallsByBrokerage(history, since) {
+		if _, target := stockTargetPrices(stock); target > 0 {
+			currency := stockTargetCurrency(stock)
+			byCurrency[currency] = append(byCurrency[currency], stock)
+		}
+	}
+
+	currencies := make([]string, 0, len(byCurrency))
+	for currency := range byCurrency {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	anomalies := make([]models.Anomaly, 0)
+	for _, currency := range currencies {
+		anomalies = append(anomalies, targetOutliersIn(ticker, byCurrency[currency], since, latest)...)
+	}
+	return anomalies
+}
+
+// targetOutliersIn busca outliers entre las llamadas vigentes de una misma moneda
+func targetOutliersIn(ticker string, calls []models.Stock, since, latest time.Time) []models.Anomaly {
+	if len(calls) < outlierMinTargets {
+		return nil
+	}
+	sort.Slice(calls, func(i, j int) bool { return brokerageKey(calls[i].Brokerage) < brokerageKey(calls[j].Brokerage) })
+
+	anomalies := make([]models.Anomaly, 0)
+	for i, call := range calls {
+		_, target := stockTargetPrices(call)
+		others := make([]float64, 0, len(calls)-1)
+		ids := []uint64{call.ID}
+		for j, other := range calls {
+			if j == i {
+				continue
+			}
+			_, otherTarget := stockTargetPrices(other)
+			others = append(others, otherTarget)
+			ids = append(ids, other.ID)
+		}
+
+		// La desviación se mide sobre el menor de los dos valores para que sea simétrica:
+		// un objetivo 10 veces por debajo de la mediana pesa igual que uno 10 veces por encima
+		reference := median(others)
+		deviation := (target - reference) / math.Min(target, reference)
+		if math.Abs(deviation) < outlierMinDeviation {
+			continue
+		}
+
+		direction, gap := "above", (target-reference)/reference
+		if deviation < 0 {
+			direction, gap = "below", (reference-target)/reference
+		}
+		anomalies = append(anomalies, models.Anomaly{
+			Fingerprint: fmt.Sprintf("%s:%d", models.AnomalyTargetOutlier, call.ID),
+			Kind:        models.AnomalyTargetOutlier,
+			Ticker:      ticker,
+			Severity:    anomalySeverity(math.Abs(deviation), outlierMediumDeviation, outlierHighDeviation),
+			Score:       math.Round(deviation*1000) / 10,
+			Summary: fmt.Sprintf("%s target %.2f on %s is %.0f%% %s the %.2f median of %d other firms",
+				strings.TrimSpace(call.Brokerage), target, ticker, gap*100, direction, reference, len(others)),
+			WindowStart: since,
+			WindowEnd:   latest,
+			StockIDs:    ids,
+		})
+	}
+	return anomalies
+}
+
+func anomalySeverity(value, medium, high float64) models.AnomalySeverity {
+	switch {
+	case value >= high:
+		return models.AnomalySeverityHigh
+	case value >= medium:
+		return models.AnomalySeverityMedium
+	default:
+		return models.AnomalySeverityLow
+	}
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+	return sorted[middle]
+}
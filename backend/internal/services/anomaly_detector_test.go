@@ -0,0 +1,186 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+type fakeAnomalyRepo struct {
+	saved   map[string]models.Anomaly
+	saveErr error
+	listFn  func(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error)
+}
+
+func (f *fakeAnomalyRepo) SaveAnomaly(anomaly *models.Anomaly) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	if f.saved == nil {
+		f.saved = map[string]models.Anomaly{}
+	}
+	f.saved[anomaly.Fingerprint] = *anomaly
+	return nil
+}
+
+func (f *fakeAnomalyRepo) ListAnomalies(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+	if f.listFn != nil {
+		return f.listFn(filter, limit, offset)
+	}
+	return nil, 0, nil
+}
+
+func targetCall(id uint64, brokerage string, at time.Time, target float64) models.Stock {
+	return models.Stock{ID: id, Ticker: "AAPL", Brokerage: brokerage, Time: at, TargetToValue: &target}
+}
+
+func TestDetectActivitySpike(t *testing.T) {
+	latest := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	history := []models.Stock{
+		{ID: 10, Brokerage: "Goldman Sachs", Time: latest},
+		{ID: 9, Brokerage: "Barclays", Time: latest.AddDate(0, 0, -1)},
+		{ID: 8, Brokerage: "UBS", Time: latest.AddDate(0, 0, -2)},
+		{ID: 7, Brokerage: "goldman sachs", Time: latest.AddDate(0, 0, -3)},
+		// Ritmo habitual: una llamada cada ~45 días, fuera de la ventana
+		{ID: 2, Brokerage: "UBS", Time: latest.AddDate(0, 0, -40)},
+		{ID: 1, Brokerage: "Barclays", Time: latest.AddDate(0, 0, -85)},
+	}
+
+	spike, ok := detectActivitySpike("AAPL", history, latest)
+	if !ok {
+		t.Fatalf("expected a spike")
+	}
+	if spike.Score != 4 || spike.Severity != models.AnomalySeverityMedium {
+		t.Fatalf("score/severity = %v/%s, want 4/medium", spike.Score, spike.Severity)
+	}
+	if len(spike.StockIDs) != 4 || spike.StockIDs[0] != 10 {
+		t.Fatalf("supporting records = %v", spike.StockIDs)
+	}
+	if spike.Fingerprint != "activity_spike:AAPL:2026-03-02" {
+		t.Fatalf("fingerprint = %q, want one per ticker and week", spike.Fingerprint)
+	}
+
+	// Dos firmas no alcanzan para considerarlo una ráfaga de cobertura
+	if _, ok := detectActivitySpike("AAPL", history[:2], latest); ok {
+		t.Fatalf("two firms should not be a spike")
+	}
+
+	// Con actividad habitual alta, la misma semana no es inusual
+	busy := append([]models.Stock{}, history...)
+	for i := 0; i < 30; i++ {
+		busy = append(busy, models.Stock{ID: uint64(100 + i), Brokerage: "UBS", Time: latest.AddDate(0, 0, -10-2*i)})
+	}
+	if _, ok := detectActivitySpike("AAPL", busy, latest); ok {
+		t.Fatalf("a busy baseline should absorb the week")
+	}
+}
+
+func TestDetectTargetOutliers(t *testing.T) {
+	latest := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	history := []models.Stock{
+		targetCall(5, "Goldman Sachs", latest, 400),
+		targetCall(4, "Barclays", latest.AddDate(0, 0, -5), 200),
+		targetCall(3, "UBS", latest.AddDate(0, 0, -9), 210),
+		targetCall(2, "Morgan Stanley", latest.AddDate(0, 0, -20), 190),
+		// Llamada vieja de Goldman reemplazada por la más reciente
+		targetCall(1, "Goldman Sachs", latest.AddDate(0, 0, -30), 205),
+	}
+
+	anomalies := detectTargetOutliers("AAPL", history, latest)
+	if len(anomalies) != 1 {
+		t.Fatalf("expected one outlier, got %+v", anomalies)
+	}
+	outlier := anomalies[0]
+	if outlier.Fingerprint != "target_outlier:5" || outlier.Severity != models.AnomalySeverityHigh || outlier.Score != 100 {
+		t.Fatalf("unexpected outlier: %+v", outlier)
+	}
+	if len(outlier.StockIDs) != 4 || outlier.StockIDs[0] != 5 {
+		t.Fatalf("supporting records should start with the outlier: %v", outlier.StockIDs)
+	}
+
+	// Un objetivo muy por debajo de la mediana es tan severo como uno muy por encima
+	below := append([]models.Stock{targetCall(6, "Goldman Sachs", latest, 20)}, history[1:4]...)
+	anomalies = detectTargetOutliers("AAPL", below, latest)
+	if len(anomalies) != 1 || anomalies[0].Severity != models.AnomalySeverityHigh || anomalies[0].Score != -900 {
+		t.Fatalf("expected a high-severity outlier below the median, got %+v", anomalies)
+	}
+	if !strings.Contains(anomalies[0].Summary, "90% below the 200.00 median") {
+		t.Fatalf("unexpected summary: %q", anomalies[0].Summary)
+	}
+
+	// Un objetivo en otra moneda no se compara con la mediana en dólares
+	euro := targetCall(7, "Deutsche Bank", latest, 40)
+	euro.TargetCurrency = "EUR"
+	mixed := append([]models.Stock{euro}, history[1:4]...)
+	for i := 1; i < len(mixed); i++ {
+		mixed[i].TargetCurrency = "USD"
+	}
+	if got := detectTargetOutliers("AAPL", mixed, latest); len(got) != 0 {
+		t.Fatalf("cross-currency targets must not be compared: %+v", got)
+	}
+
+	if got := detectTargetOutliers("AAPL", history[1:4], latest); len(got) != 0 {
+		t.Fatalf("fewer than %d targets should not be evaluated: %+v", outlierMinTargets, got)
+	}
+}
+
+func TestAnomalyDetector_AfterSyncPersists(t *testing.T) {
+	latest := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	requested := make([]string, 0)
+	stocks := &fakeRepo{
+		findByTickerFn: func(ticker string) ([]models.Stock, error) {
+			requested = append(requested, ticker)
+			if ticker != "AAPL" {
+				return nil, nil
+			}
+			return []models.Stock{
+				targetCall(5, "Goldman Sachs", latest, 400),
+				targetCall(4, "Barclays", latest.AddDate(0, 0, -5), 200),
+				targetCall(3, "UBS", latest.AddDate(0, 0, -3), 210),
+				targetCall(2, "Morgan Stanley", latest.AddDate(0, 0, -20), 190),
+			}, nil
+		},
+	}
+	repo := &fakeAnomalyRepo{}
+	detector := NewAnomalyDetector(stocks, repo)
+	detectedAt := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+	detector.now = func() time.Time { return detectedAt }
+
+	detector.AfterSync([]models.Stock{{Ticker: "AAPL"}, {Ticker: "MSFT"}, {Ticker: "AAPL"}})
+	if len(requested) != 2 {
+		t.Fatalf("each changed ticker should be analysed once, got %v", requested)
+	}
+	saved, ok := repo.saved["activity_spike:AAPL:2026-03-02"]
+	if !ok || !saved.DetectedAt.Equal(detectedAt) || !saved.LastSeenAt.Equal(detectedAt) {
+		t.Fatalf("expected the spike to be saved with the detection time: %+v", repo.saved)
+	}
+	if _, ok := repo.saved["target_outlier:5"]; !ok {
+		t.Fatalf("expected the outlier to be saved: %+v", repo.saved)
+	}
+
+	failing := NewAnomalyDetector(stocks, &fakeAnomalyRepo{saveErr: errors.New("db")})
+	if _, err := failing.DetectTickers([]string{"AAPL"}); err == nil {
+		t.Fatalf("expected save error")
+	}
+}
+
+func TestAnomalyDetector_ListNormalizesTicker(t *testing.T) {
+	var got repositories.AnomalyFilter
+	detector := NewAnomalyDetector(&fakeRepo{}, &fakeAnomalyRepo{
+		listFn: func(filter repositories.AnomalyFilter, limit, offset int) ([]models.Anomaly, int64, error) {
+			got = filter
+			return []models.Anomaly{}, 0, nil
+		},
+	})
+
+	if _, _, err := detector.ListAnomalies(repositories.AnomalyFilter{Ticker: " aapl "}, 50, 0); err != nil {
+		t.Fatalf("ListAnomalies error: %v", err)
+	}
+	if got.Ticker != "AAPL" {
+		t.Fatalf("ticker = %q", got.Ticker)
+	}
+}
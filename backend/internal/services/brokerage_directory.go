@@ -0,0 +1,86 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// brokerageSummaryLess define los órdenes aceptados por ListBrokerages (ascendentes)
+var brokerageSummaryLess = map[string]func(a, b models.BrokerageSummary) bool{
+	"brokerage": func(a, b models.BrokerageSummary) bool {
+		return strings.ToLower(a.Brokerage) < strings.ToLower(b.Brokerage)
+	},
+	"total_calls":     func(a, b models.BrokerageSummary) bool { return a.TotalCalls < b.TotalCalls },
+	"tickers_covered": func(a, b models.BrokerageSummary) bool { return a.TickersCovered < b.TickersCovered },
+	"last_activity":   func(a, b models.BrokerageSummary) bool { return a.LastActivity.Before(b.LastActivity) },
+}
+
+// BrokerageDirectory expone el directorio de casas de bolsa y su historial de llamadas
+type BrokerageDirectory struct {
+	stocks repositories.StockRepository
+}
+
+// NewBrokerageDirectory crea una nueva instancia del directorio de brokerages
+func NewBrokerageDirectory(stocks repositories.StockRepository) *BrokerageDirectory {
+	return &BrokerageDirectory{stocks: stocks}
+}
+
+// ListBrokerages lista cada casa de bolsa con su cobertura, última actividad y relación upgrades/downgrades.
+// Un sortBy desconocido ordena por total_calls.
+func (d *BrokerageDirectory) ListBrokerages(sortBy, order string) ([]models.BrokerageSummary, error) {
+	items, err := d.stocks.BrokerageActivity()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range items {
+		if items[i].Downgrades > 0 {
+			ratio := math.Round(float64(items[i].Upgrades)/float64(items[i].Downgrades)*100) / 100
+			items[i].UpgradeDowngradeRatio = &ratio
+		}
+	}
+
+	less, ok := brokerageSummaryLess[sortBy]
+	if !ok {
+		less = brokerageSummaryLess["total_calls"]
+	}
+	desc := normalizeSortOrder(order)
+	sort.SliceStable(items, func(i, j int) bool {
+		if desc {
+			return less(items[j], items[i])
+		}
+		return less(items[i], items[j])
+	})
+	return items, nil
+}
+
+// GetBrokerageCalls devuelve el historial paginado de una casa de bolsa (sin distinguir mayúsculas),
+// combinado con los demás filtros del listado. Devuelve ErrNotFound si la firma no tiene llamadas.
+func (d *BrokerageDirectory) GetBrokerageCalls(name string, filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+	filter.Brokerages = []string{strings.TrimSpace(name)}
+
+	total, err := d.stocks.Count(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if total == 0 {
+		all, err := d.stocks.Count(repositories.StockFilter{Brokerages: filter.Brokerages})
+		if err != nil {
+			return nil, 0, err
+		}
+		if all == 0 {
+			return nil, 0, repositories.ErrNotFound
+		}
+		return []models.Stock{}, 0, nil
+	}
+
+	stocks, err := d.stocks.List(filter, limit, offset, "time", true)
+	if err != nil {
+		return nil, 0, err
+	}
+	return stocks, total, nil
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func TestListBrokerages_RatioAndSort(t *testing.T) {
+	now := time.Now()
+	repo := &fakeRepo{
+		brokerageActivityFn: func() ([]models.BrokerageSummary, error) {
+			return []models.BrokerageSummary{
+				{Brokerage: "Barclays", TotalCalls: 3, TickersCovered: 3, LastActivity: now, Upgrades: 2},
+				{Brokerage: "Goldman Sachs", TotalCalls: 10, TickersCovered: 2, LastActivity: now.Add(-time.Hour), Upgrades: 2, Downgrades: 3},
+			}, nil
+		},
+	}
+	directory := NewBrokerageDirectory(repo)
+
+	items, err := directory.ListBrokerages("", "")
+	if err != nil {
+		t.Fatalf("ListBrokerages error: %v", err)
+	}
+	if items[0].Brokerage != "Goldman Sachs" {
+		t.Fatalf("default sort should be total_calls desc, got %+v", items)
+	}
+	if items[0].UpgradeDowngradeRatio == nil || *items[0].UpgradeDowngradeRatio != 0.67 {
+		t.Fatalf("ratio = %v, want 0.67", items[0].UpgradeDowngradeRatio)
+	}
+	if items[1].UpgradeDowngradeRatio != nil {
+		t.Fatalf("ratio without downgrades should be nil, got %v", *items[1].UpgradeDowngradeRatio)
+	}
+
+	items, _ = directory.ListBrokerages("last_activity", "desc")
+	if items[0].Brokerage != "Barclays" {
+		t.Fatalf("last_activity desc got %+v", items)
+	}
+	items, _ = directory.ListBrokerages("brokerage", "asc")
+	if items[0].Brokerage != "Barclays" {
+		t.Fatalf("brokerage asc got %+v", items)
+	}
+
+	failing := NewBrokerageDirectory(&fakeRepo{
+		brokerageActivityFn: func() ([]models.BrokerageSummary, error) { return nil, errors.New("db") },
+	})
+	if _, err := failing.ListBrokerages("", ""); err == nil {
+		t.Fatalf("expected error")
+	}
+}
+
+func TestGetBrokerageCalls(t *testing.T) {
+	var listed repositories.StockFilter
+	repo := &fakeRepo{
+		countFn: func(filter repositories.StockFilter) (int64, error) {
+			if filter.Brokerages[0] == "Nobody" {
+				return 0, nil
+			}
+			if filter.EventType == models.ActionEventDowngrade {
+				return 0, nil
+			}
+			return 2, nil
+		},
+		listFn: func(filter repositories.StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
+			listed = filter
+			if sortField != "time" || !desc {
+				t.Fatalf("calls should be newest first, got %s desc=%v", sortField, desc)
+			}
+			return []models.Stock{{Ticker: "AAPL"}, {Ticker: "MSFT"}}, nil
+		},
+	}
+	directory := NewBrokerageDirectory(repo)
+
+	stocks, total, err := directory.GetBrokerageCalls(" Goldman Sachs ", repositories.StockFilter{Brokerages: []string{"Other"}}, 50, 0)
+	if err != nil || total != 2 || len(stocks) != 2 {
+		t.Fatalf("GetBrokerageCalls err=%v total=%d len=%d", err, total, len(stocks))
+	}
+	if len(listed.Brokerages) != 1 || listed.Brokerages[0] != "Goldman Sachs" {
+		t.Fatalf("brokerage filter = %v", listed.Brokerages)
+	}
+
+	if _, _, err := directory.GetBrokerageCalls("Nobody", repositories.StockFilter{}, 50, 0); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	stocks, total, err = directory.GetBrokerageCalls("Goldman Sachs", repositories.StockFilter{EventType: models.ActionEventDowngrade}, 50, 0)
+	if err != nil || total != 0 || len(stocks) != 0 {
+		t.Fatalf("filtered-out calls should be an empty page, err=%v total=%d", err, total)
+	}
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const (
+	reliabilityConfirmationWindow = 30 * 24 * time.Hour
+	reliabilityReversalWindow     = 90 * 24 * time.Hour
+	reliabilityLookback           = 365 * 24 * time.Hour
+	directionalSignalThreshold    = 55.0
+	reliabilityCoverageSaturation = 50.0
+	minReliabilityMultiplier      = 0.7
+	maxReliabilityMultiplier      = 1.3
+)
+
+// BrokerageWeigher devuelve el multiplicador de credibilidad de una casa de bolsa
+type BrokerageWeigher interface {
+	Multiplier(brokerage string) float64
+}
+
+type ReliabilityService struct {
+	stocks repositories.StockRepository
+	repo   repositories.BrokerageReliabilityRepository
+	now    func() time.Time
+
+	mu          sync.RWMutex
+	multipliers map[string]float64
+}
+
+type reliabilityAccumulator struct {
+	name         string
+	calls        int
+	directional  int
+	confirmed    int
+	contradicted int
+	reversals    int
+	tickers      map[string]struct{}
+}
+
+// NewReliabilityService crea una nueva instancia del servicio de credibilidad de brokerages
+func NewReliabilityService(stocks repositories.StockRepository, repo repositories.BrokerageReliabilityRepository) *ReliabilityService {
+	return &ReliabilityService{
+		stocks:      stocks,
+		repo:        repo,
+		now:         time.Now,
+		multipliers: map[string]float64{},
+	}
+}
+
+// Load carga en memoria los multiplicadores persistidos en la última actualización
+func (s *ReliabilityService) Load() error {
+	items, err := s.repo.ListReliability()
+	if err != nil {
+		return err
+	}
+	s.setMultipliers(items)
+	return nil
+}
+
+// Refresh recalcula la credibilidad con las llamadas del último año y la persiste
+func (s *ReliabilityService) Refresh() ([]models.BrokerageReliability, error) {
+	log.Println("🔄 Refreshing brokerage reliability...")
+
+	now := s.now()
+	history, err := s.stocks.FindSince(now.Add(-reliabilityLookback))
+	if err != nil {
+		return nil, err
+	}
+
+	items := computeReliability(history, now)
+	if err := s.repo.ReplaceReliability(items); err != nil {
+		return nil, err
+	}
+	s.setMultipliers(items)
+
+	log.Printf("✅ Brokerage reliability refreshed for %d brokerages", len(items))
+	return items, nil
+}
+
+// StartPeriodicRefresh recalcula la tabla cada interval hasta que stop se cierre
+func (s *ReliabilityService) StartPeriodicRefresh(interval time.Duration, stop <-chan struct{}) {
+	if _, err := s.Refresh(); err != nil {
+		log.Printf("⚠️  Brokerage reliability refresh failed: %v", err)
+	}
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Refresh(); err != nil {
+				log.Printf("⚠️  Brokerage reliability refresh failed: %v", err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// GetReliability obtiene la tabla de credibilidad ordenada por score
+func (s *ReliabilityService) GetReliability() ([]models.BrokerageReliability, error) {
+	return s.repo.ListReliability()
+}
+
+// Multiplier devuelve el multiplicador de una casa de bolsa (1.0 si no hay datos)
+func (s *ReliabilityService) Multiplier(brokerage string) float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if value, ok := s.multipliers[brokerageKey(brokerage)]; ok {
+		return value
+	}
+	return 1.0
+}
+
+func (s *ReliabilityService) setMultipliers(items []models.BrokerageReliability) {
+	multipliers := make(map[string]float64, len(items))
+	for _, item := range items {
+		multipliers[brokerageKey(item.Brokerage)] = item.Multiplier
+	}
+
+	s.mu.Lock()
+	s.multipliers = multipliers
+	s.mu.Unlock()
+}
+
+// computeReliability mide, por casa de bolsa, cuántas de sus llamadas direccionales
+// fueron confirmadas por otras firmas, cuántas revirtió ella misma y su cobertura
+func computeReliability(history []models.Stock, now time.Time) []models.BrokerageReliability {
+	byTicker := make(map[string][]models.Stock)
+	for _, stock := range history {
+		byTicker[stock.Ticker] = append(byTicker[stock.Ticker], stock)
+	}
+
+	accumulators := make(map[string]*reliabilityAccumulator)
+	for ticker, calls := range byTicker {
+		sort.Slice(calls, func(i, j int) bool {
+			return calls[i].Time.Before(calls[j].Time)
+		})
+
+		for i, call := range calls {
+			key := brokerageKey(call.Brokerage)
+			if key == "" {
+				continue
+			}
+
+			acc, ok := accumulators[key]
+			if !ok {
+				acc = &reliabilityAccumulator{name: strings.TrimSpace(call.Brokerage), tickers: map[string]struct{}{}}
+				accumulators[key] = acc
+			}
+			acc.calls++
+			acc.tickers[ticker] = struct{}{}
+
+			direction := callDirection(call)
+			if direction == 0 {
+				continue
+			}
+			acc.directional++
+
+			// La siguiente llamada direccional de otra firma confirma o contradice.
+			for _, next := range calls[i+1:] {
+				if next.Time.Sub(call.Time) > reliabilityConfirmationWindow {
+					break
+				}
+				nextDirection := callDirection(next)
+				if brokerageKey(next.Brokerage) == key || nextDirection == 0 {
+					continue
+				}
+				if nextDirection == direction {
+					acc.confirmed++
+				} else {
+					acc.contradicted++
+				}
+				break
+			}
+
+			// La siguiente llamada direccional propia en sentido opuesto es una reversión.
+			for _, next := range calls[i+1:] {
+				if next.Time.Sub(call.Time) > reliabilityReversalWindow {
+					break
+				}
+				nextDirection := callDirection(next)
+				if brokerageKey(next.Brokerage) != key || nextDirection == 0 {
+					continue
+				}
+				if nextDirection != direction {
+					acc.reversals++
+				}
+				break
+			}
+		}
+	}
+
+	items := make([]models.BrokerageReliability, 0, len(accumulators))
+	for _, acc := range accumulators {
+		items = append(items, acc.toReliability(now))
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Score == items[j].Score {
+			return items[i].Brokerage < items[j].Brokerage
+		}
+		return items[i].Score > items[j].Score
+	})
+	return items
+}
+
+func (acc *reliabilityAccumulator) toReliability(now time.Time) models.BrokerageReliability {
+	// Suavizado de Laplace para no premiar ni castigar firmas con pocas llamadas.
+	confirmationRate := float64(acc.confirmed+1) / float64(acc.confirmed+acc.contradicted+2)
+	reversalRate := float64(acc.reversals) / float64(acc.directional+2)
+	coverage := math.Min(1, math.Log1p(float64(len(acc.tickers)))/math.Log1p(reliabilityCoverageSaturation))
+
+	score := clamp(confirmationRate*0.6+coverage*0.25+(1-reversalRate)*0.15, 0, 1)
+	multiplier := minReliabilityMultiplier + score*(maxReliabilityMultiplier-minReliabilityMultiplier)
+
+	return models.BrokerageReliability{
+		Brokerage:         acc.name,
+		Score:             score * 100,
+		Multiplier:        multiplier,
+		TotalCalls:        acc.calls,
+		DirectionalCalls:  acc.directional,
+		ConfirmedCalls:    acc.confirmed,
+		ContradictedCalls: acc.contradicted,
+		Reversals:         acc.reversals,
+		TickersCovered:    len(acc.tickers),
+		ConfirmationRate:  confirmationRate,
+		ReversalRate:      reversalRate,
+		UpdatedAt:         now,
+	}
+}
+
+// callDirection clasifica una llamada como alcista (1), bajista (-1) o neutral (0)
+func callDirection(stock models.Stock) int {
+	signal := actionSignal(stock.Action)
+	if signal >= directionalSignalThreshold {
+		return 1
+	}
+	if signal <= -directionalSignalThreshold {
+		return -1
+	}
+	return 0
+}
+
+func brokerageKey(brokerage string) string {
+	return strings.ToLower(strings.TrimSpace(brokerage))
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+type fakeReliabilityRepo struct {
+	items    []models.BrokerageReliability
+	replaced []models.BrokerageReliability
+	err      error
+}
+
+func (f *fakeReliabilityRepo) ListReliability() ([]models.BrokerageReliability, error) {
+	return f.items, f.err
+}
+func (f *fakeReliabilityRepo) ReplaceReliability(items []models.BrokerageReliability) error {
+	f.replaced = items
+	return f.err
+}
+
+type fixedWeigher map[string]float64
+
+func (w fixedWeigher) Multiplier(brokerage string) float64 {
+	if value, ok := w[brokerage]; ok {
+		return value
+	}
+	return 1.0
+}
+
+func TestComputeReliability_ConfirmationsAndReversals(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := []models.Stock{
+		// Good Bank se adelanta y es confirmada por Other en ambos tickers.
+		{Ticker: "AAA", Brokerage: "Good Bank", Action: "upgraded by", Time: base},
+		{Ticker: "AAA", Brokerage: "Other", Action: "target raised by", Time: base.AddDate(0, 0, 3)},
+		{Ticker: "BBB", Brokerage: "Good Bank", Action: "downgraded by", Time: base},
+		{Ticker: "BBB", Brokerage: "Other", Action: "target lowered by", Time: base.AddDate(0, 0, 2)},
+		// Flip Bank es contradicha y además se revierte.
+		{Ticker: "CCC", Brokerage: "Flip Bank", Action: "upgraded by", Time: base},
+		{Ticker: "CCC", Brokerage: "Other", Action: "downgraded by", Time: base.AddDate(0, 0, 1)},
+		{Ticker: "CCC", Brokerage: "Flip Bank", Action: "downgraded by", Time: base.AddDate(0, 0, 10)},
+	}
+
+	items := computeReliability(history, base)
+	byName := map[string]models.BrokerageReliability{}
+	for _, item := range items {
+		byName[item.Brokerage] = item
+	}
+
+	good, flip := byName["Good Bank"], byName["Flip Bank"]
+	if good.ConfirmedCalls != 2 || good.ContradictedCalls != 0 || good.TickersCovered != 2 {
+		t.Fatalf("unexpected Good Bank stats: %+v", good)
+	}
+	if flip.Reversals != 1 || flip.ContradictedCalls != 1 {
+		t.Fatalf("unexpected Flip Bank stats: %+v", flip)
+	}
+	if good.Multiplier <= 1.0 || flip.Multiplier >= good.Multiplier {
+		t.Fatalf("expected Good Bank to outrank Flip Bank: good=%v flip=%v", good.Multiplier, flip.Multiplier)
+	}
+	if items[0].Brokerage != "Good Bank" {
+		t.Fatalf("expected table sorted by score, got %+v", items)
+	}
+	for _, item := range items {
+		if item.Multiplier < minReliabilityMultiplier || item.Multiplier > maxReliabilityMultiplier {
+			t.Fatalf("multiplier out of range: %+v", item)
+		}
+	}
+}
+
+func TestReliabilityService_RefreshPersistsAndCaches(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var since time.Time
+	stocks := &fakeRepo{findSinceFn: func(requested time.Time) ([]models.Stock, error) {
+		since = requested
+		return []models.Stock{
+			{Ticker: "AAA", Brokerage: "Good Bank", Action: "upgraded by", Time: base},
+			{Ticker: "AAA", Brokerage: "Other", Action: "upgraded by", Time: base.AddDate(0, 0, 1)},
+		}, nil
+	}}
+	repo := &fakeReliabilityRepo{}
+
+	svc := NewReliabilityService(stocks, repo)
+	svc.now = func() time.Time { return base.AddDate(0, 1, 0) }
+	if got := svc.Multiplier("Good Bank"); got != 1.0 {
+		t.Fatalf("expected neutral multiplier before refresh, got %v", got)
+	}
+
+	items, err := svc.Refresh()
+	if err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+	if len(repo.replaced) != len(items) || len(items) != 2 {
+		t.Fatalf("expected 2 persisted items, got %d/%d", len(repo.replaced), len(items))
+	}
+	if want := base.AddDate(0, 1, 0).Add(-reliabilityLookback); !since.Equal(want) {
+		t.Fatalf("history requested since %v, want the one-year horizon %v", since, want)
+	}
+	if got := svc.Multiplier("  good bank "); got == 1.0 {
+		t.Fatalf("expected cached multiplier for Good Bank, got %v", got)
+	}
+
+	repo.err = errors.New("db down")
+	if err := svc.Load(); err == nil {
+		t.Fatalf("expected Load error, got nil")
+	}
+}
+
+func TestRecommendationService_AppliesBrokerageMultiplier(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	stock := models.Stock{Ticker: "AAA", Brokerage: "Trusted", TargetFrom: "$100", TargetTo: "$110", Action: "Target raised", RatingFrom: "Hold", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -1)}
+
+	plain := NewRecommendationService(&recoRepo{})
+	plain.now = func() time.Time { return fixedNow }
+	weighted := NewRecommendationServiceWithReliability(&recoRepo{}, fixedWeigher{"Trusted": 1.2})
+	weighted.now = func() time.Time { return fixedNow }
+
+	plainScore := plain.calculateScore(stock, []models.Stock{stock})
+	weightedScore := weighted.calculateScore(stock, []models.Stock{stock})
+	if weightedScore.finalScore <= plainScore.finalScore {
+		t.Fatalf("expected trusted brokerage to lift score: plain=%v weighted=%v", plainScore.finalScore, weightedScore.finalScore)
+	}
+	if weightedScore.features.BrokerageWeight != 1.2 {
+		t.Fatalf("BrokerageWeight = %v, want 1.2", weightedScore.features.BrokerageWeight)
+	}
+}
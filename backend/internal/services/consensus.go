@@ -0,0 +1,230 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const (
+	defaultConsensusWindowDays = 90
+	ratingBucketBuy            = "buy"
+	ratingBucketHold           = "hold"
+	ratingBucketSell           = "sell"
+)
+
+type ConsensusService struct {
+	repo repositories.StockRepository
+	now  func() time.Time
+}
+
+// NewConsensusService crea una nueva instancia del servicio de consenso
+func NewConsensusService(repo repositories.StockRepository) *ConsensusService {
+	return &ConsensusService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// GetConsensus calcula el consenso de precio objetivo y ratings de un ticker
+// usando la última llamada de cada casa de bolsa dentro de la ventana
+func (cs *ConsensusService) GetConsensus(ticker string, windowDays int) (*models.TickerConsensus, error) {
+	if windowDays <= 0 {
+		windowDays = defaultConsensusWindowDays
+	}
+
+	history, err := cs.repo.FindByTicker(strings.ToUpper(strings.TrimSpace(ticker)))
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+
+	since := cs.now().AddDate(0, 0, -windowDays)
+	consensus := buildConsensus(history[0].Ticker, history, since)
+	consensus.WindowDays = windowDays
+	return &consensus, nil
+}
+
+// GetConsensusForTickers calcula el consenso de varios tickers con una sola consulta del historial.
+// Devuelve los resultados en el orden de tickers y omite los que no tienen registros.
+func (cs *ConsensusService) GetConsensusForTickers(tickers []string, windowDays int) ([]models.TickerConsensus, error) {
+	if windowDays <= 0 {
+		windowDays = defaultConsensusWindowDays
+	}
+
+	history, err := cs.repo.FindByTickers(tickers)
+	if err != nil {
+		return nil, err
+	}
+	byTicker := make(map[string][]models.Stock, len(tickers))
+	for _, stock := range history {
+		byTicker[stock.Ticker] = append(byTicker[stock.Ticker], stock)
+	}
+
+	since := cs.now().AddDate(0, 0, -windowDays)
+	items := make([]models.TickerConsensus, 0, len(tickers))
+	for _, ticker := range tickers {
+		calls, ok := byTicker[ticker]
+		if !ok {
+			continue
+		}
+		consensus := buildConsensus(ticker, calls, since)
+		consensus.WindowDays = windowDays
+		items = append(items, consensus)
+	}
+	return items, nil
+}
+
+// buildConsensus agrega la última llamada por brokerage posterior a since
+func buildConsensus(ticker string, history []models.Stock, since time.Time) models.TickerConsensus {
+	latestByBrokerage := latestCallsByBrokerage(history, since)
+
+	consensus := models.TickerConsensus{
+		Ticker: ticker,
+		Calls:  make([]models.ConsensusCall, 0, len(latestByBrokerage)),
+	}
+
+	targetsByCurrency := make(map[string][]float64)
+	for _, stock := range latestByBrokerage {
+		_, target := stockTargetPrices(stock)
+		currency := stockTargetCurrency(stock)
+		bucket := ratingBucket(stock)
+
+		switch bucket {
+		case ratingBucketBuy:
+			consensus.RatingDistribution.Buy++
+		case ratingBucketHold:
+			consensus.RatingDistribution.Hold++
+		case ratingBucketSell:
+			consensus.RatingDistribution.Sell++
+		}
+
+		if target > 0 {
+			targetsByCurrency[currency] = append(targetsByCurrency[currency], target)
+		}
+
+		consensus.Calls = append(consensus.Calls, models.ConsensusCall{
+			Brokerage:    strings.TrimSpace(stock.Brokerage),
+			Target:       target,
+			Currency:     currency,
+			Rating:       stock.RatingTo,
+			RatingBucket: bucket,
+			Time:         stock.Time,
+		})
+	}
+
+	sort.Slice(consensus.Calls, func(i, j int) bool {
+		return consensus.Calls[i].Time.After(consensus.Calls[j].Time)
+	})
+
+	// Promediar precios en monedas distintas no tiene sentido: se usa la moneda con más objetivos
+	// y, en empate, la de la llamada más reciente
+	chosen := false
+	for _, call := range consensus.Calls {
+		if call.Target <= 0 {
+			continue
+		}
+		if !chosen || len(targetsByCurrency[call.Currency]) > len(targetsByCurrency[consensus.TargetCurrency]) {
+			consensus.TargetCurrency = call.Currency
+			chosen = true
+		}
+	}
+	targets := targetsByCurrency[consensus.TargetCurrency]
+
+	consensus.CoveringFirms = len(latestByBrokerage)
+	consensus.TargetCount = len(targets)
+	if len(targets) > 0 {
+		sort.Float64s(targets)
+		sum := 0.0
+		for _, target := range targets {
+			sum += target
+		}
+		consensus.MeanTarget = sum / float64(len(targets))
+		consensus.LowTarget = targets[0]
+		consensus.HighTarget = targets[len(targets)-1]
+
+		middle := len(targets) / 2
+		if len(targets)%2 == 0 {
+			consensus.MedianTarget = (targets[middle-1] + targets[middle]) / 2
+		} else {
+			consensus.MedianTarget = targets[middle]
+		}
+	}
+
+	return consensus
+}
+
+// latestCallsByBrokerage devuelve la última llamada de cada brokerage posterior a since
+func latestCallsByBrokerage(history []models.Stock, since time.Time) map[string]models.Stock {
+	latestByBrokerage := make(map[string]models.Stock)
+	for _, stock := range history {
+		if stock.Time.Before(since) {
+			continue
+		}
+		key := brokerageKey(stock.Brokerage)
+		if key == "" {
+			continue
+		}
+		if current, ok := latestByBrokerage[key]; !ok || stock.Time.After(current.Time) {
+			latestByBrokerage[key] = stock
+		}
+	}
+	return latestByBrokerage
+}
+
+// consensusSignal convierte el consenso en una señal [-100, 100]: balance de ratings
+// entre firmas combinado con la dirección media de los cambios de target de la ventana
+func consensusSignal(history []models.Stock, since time.Time) float64 {
+	if len(history) == 0 {
+		return 0
+	}
+
+	consensus := buildConsensus(history[0].Ticker, history, since)
+	rated := consensus.RatingDistribution.Buy + consensus.RatingDistribution.Hold + consensus.RatingDistribution.Sell
+	if rated == 0 {
+		return 0
+	}
+	ratingBalance := float64(consensus.RatingDistribution.Buy-consensus.RatingDistribution.Sell) / float64(rated) * 100
+
+	changes := 0.0
+	changeCount := 0
+	for _, stock := range history {
+		if stock.Time.Before(since) {
+			continue
+		}
+		from, to := stockTargetPrices(stock)
+		if from > 0 && to > 0 {
+			changes += (to - from) / from * 100
+			changeCount++
+		}
+	}
+
+	targetTrend := 0.0
+	if changeCount > 0 {
+		targetTrend = math.Tanh(changes/float64(changeCount)/14.0) * 100
+	}
+
+	return clamp(ratingBalance*0.6+targetTrend*0.4, -100, 100)
+}
+
+// ratingBucket agrupa el rating destino en buy/hold/sell usando su score normalizado
+func ratingBucket(stock models.Stock) string {
+	if strings.TrimSpace(stock.RatingTo) == "" {
+		return ""
+	}
+
+	_, score := stockRatingScores(stock)
+	if score >= 3.0 {
+		return ratingBucketBuy
+	}
+	if score <= 1.0 {
+		return ratingBucketSell
+	}
+	return ratingBucketHold
+}
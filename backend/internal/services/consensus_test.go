@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func TestGetConsensus_LatestCallPerBrokerage(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{findByTickerFn: func(ticker string) ([]models.Stock, error) {
+		if ticker != "AAPL" {
+			t.Fatalf("unexpected ticker %q", ticker)
+		}
+		return []models.Stock{
+			{Ticker: "AAPL", Brokerage: "Alpha", TargetTo: "$120", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -1)},
+			{Ticker: "AAPL", Brokerage: "Alpha", TargetTo: "$100", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -20)},
+			{Ticker: "AAPL", Brokerage: "Beta", TargetTo: "$90", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -5)},
+			{Ticker: "AAPL", Brokerage: "Gamma", TargetTo: "$150.00", RatingTo: "Underperform", Time: fixedNow.AddDate(0, 0, -10)},
+			{Ticker: "AAPL", Brokerage: "Stale", TargetTo: "$300", RatingTo: "Buy", Time: fixedNow.AddDate(0, -6, 0)},
+		}, nil
+	}}
+
+	svc := NewConsensusService(repo)
+	svc.now = func() time.Time { return fixedNow }
+
+	consensus, err := svc.GetConsensus(" aapl ", 90)
+	if err != nil {
+		t.Fatalf("GetConsensus error: %v", err)
+	}
+	if consensus.CoveringFirms != 3 || consensus.TargetCount != 3 {
+		t.Fatalf("unexpected coverage: %+v", consensus)
+	}
+	if consensus.HighTarget != 150 || consensus.LowTarget != 90 || consensus.MedianTarget != 120 || consensus.MeanTarget != 120 {
+		t.Fatalf("unexpected targets: %+v", consensus)
+	}
+	if consensus.RatingDistribution != (models.RatingDistribution{Buy: 1, Hold: 1, Sell: 1}) {
+		t.Fatalf("unexpected distribution: %+v", consensus.RatingDistribution)
+	}
+	if consensus.Calls[0].Brokerage != "Alpha" || consensus.Calls[0].Target != 120 {
+		t.Fatalf("expected latest Alpha call first, got %+v", consensus.Calls[0])
+	}
+}
+
+func TestBuildConsensus_AggregatesDominantCurrencyOnly(t *testing.T) {
+	now := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	history := []models.Stock{
+		{Ticker: "SAP", Brokerage: "Alpha", TargetTo: "€200", RatingTo: "Buy", Time: now.AddDate(0, 0, -1)},
+		{Ticker: "SAP", Brokerage: "Beta", TargetTo: "$210", RatingTo: "Buy", Time: now.AddDate(0, 0, -2)},
+		{Ticker: "SAP", Brokerage: "Gamma", TargetTo: "$230", RatingTo: "Hold", Time: now.AddDate(0, 0, -3)},
+	}
+
+	consensus := buildConsensus("SAP", history, now.AddDate(0, 0, -90))
+	if consensus.TargetCurrency != "USD" || consensus.TargetCount != 2 || consensus.CoveringFirms != 3 {
+		t.Fatalf("unexpected coverage: %+v", consensus)
+	}
+	if consensus.LowTarget != 210 || consensus.HighTarget != 230 || consensus.MeanTarget != 220 {
+		t.Fatalf("EUR target must not enter the USD statistics: %+v", consensus)
+	}
+	if consensus.Calls[0].Currency != "EUR" || consensus.Calls[0].Target != 200 {
+		t.Fatalf("calls should keep their own currency: %+v", consensus.Calls[0])
+	}
+
+	// En empate gana la moneda de la llamada más reciente
+	tie := buildConsensus("SAP", history[:2], now.AddDate(0, 0, -90))
+	if tie.TargetCurrency != "EUR" || tie.TargetCount != 1 || tie.MedianTarget != 200 {
+		t.Fatalf("tie should go to the latest call's currency: %+v", tie)
+	}
+}
+
+func TestGetConsensus_NotFoundAndError(t *testing.T) {
+	svc := NewConsensusService(&fakeRepo{})
+	if _, err := svc.GetConsensus("NONE", 0); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	svc = NewConsensusService(&fakeRepo{findByTickerFn: func(string) ([]models.Stock, error) {
+		return nil, errors.New("db down")
+	}})
+	if _, err := svc.GetConsensus("AAPL", 30); err == nil {
+		t.Fatalf("expected error, got nil")
+	}
+}
+
+func TestConsensusSignal_Direction(t *testing.T) {
+	now := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	bullish := []models.Stock{
+		{Ticker: "AAA", Brokerage: "A", TargetFrom: "$100", TargetTo: "$120", RatingTo: "Buy", Time: now},
+		{Ticker: "AAA", Brokerage: "B", TargetFrom: "$100", TargetTo: "$115", RatingTo: "Outperform", Time: now},
+	}
+	bearish := []models.Stock{
+		{Ticker: "BBB", Brokerage: "A", TargetFrom: "$100", TargetTo: "$70", RatingTo: "Sell", Time: now},
+	}
+
+	since := now.AddDate(0, 0, -90)
+	if got := consensusSignal(bullish, since); got <= 0 {
+		t.Fatalf("expected bullish signal, got %v", got)
+	}
+	if got := consensusSignal(bearish, since); got >= 0 {
+		t.Fatalf("expected bearish signal, got %v", got)
+	}
+	if got := consensusSignal(nil, since); got != 0 {
+		t.Fatalf("expected neutral signal for empty history, got %v", got)
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"sync"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+const eventSubscriberBuffer = 256
+
+// EventFilter restringe los eventos de un ticker a ciertos tickers y brokerages.
+// Los eventos sin ticker o sin brokerage (sincronización) no se filtran por ese criterio
+type EventFilter struct {
+	Tickers    []string
+	Brokerages []string
+}
+
+// Matches indica si el evento pasa el filtro
+func (f EventFilter) Matches(event models.Event) bool {
+	if event.Ticker != "" && len(f.Tickers) > 0 && !containsFold(f.Tickers, event.Ticker) {
+		return false
+	}
+	if event.Brokerage != "" && len(f.Brokerages) > 0 && !containsFold(f.Brokerages, brokerageKey(event.Brokerage)) {
+		return false
+	}
+	return true
+}
+
+// EventBus reparte en memoria los eventos de sincronización entre los suscriptores conectados
+// y guarda los últimos en un buffer circular para que un cliente pueda reanudar desde su Last-Event-ID
+type EventBus struct {
+	mu          sync.Mutex
+	now         func() time.Time
+	lastID      uint64
+	buffer      []models.Event
+	start       int
+	count       int
+	subscribers map[*EventSubscription]struct{}
+}
+
+// EventSubscription recibe los eventos publicados después de suscribirse.
+// Si el suscriptor no consume a tiempo y su buffer se llena, el canal se cierra
+type EventSubscription struct {
+	bus    *EventBus
+	events chan models.Event
+}
+
+// NewEventBus crea un bus que conserva los últimos size eventos
+func NewEventBus(size int) *EventBus {
+	now := time.Now
+	return &EventBus{
+		now: now,
+		// Los IDs parten del reloj para que tras un reinicio sigan siendo mayores que los ya entregados
+		lastID:      uint64(now().UnixMicro()),
+		buffer:      make([]models.Event, max(size, 1)),
+		subscribers: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// Publish asigna ID y hora al evento, lo guarda en el buffer y lo entrega a cada suscriptor sin bloquear
+func (b *EventBus) Publish(event models.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	event.ID = b.lastID
+	if event.Time.IsZero() {
+		event.Time = b.now()
+	}
+
+	if b.count < len(b.buffer) {
+		b.buffer[(b.start+b.count)%len(b.buffer)] = event
+		b.count++
+	} else {
+		b.buffer[b.start] = event
+		b.start = (b.start + 1) % len(b.buffer)
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			// Suscriptor lento: se desconecta y puede reanudar desde el buffer
+			delete(b.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe registra un suscriptor. Con lastEventID distinto de cero devuelve además los eventos
+// posteriores que siguen en el buffer; complete es false si alguno ya se descartó
+func (b *EventBus) Subscribe(lastEventID uint64) (*EventSubscription, []models.Event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub := &EventSubscription{bus: b, events: make(chan models.Event, eventSubscriberBuffer)}
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == 0 {
+		return sub, nil, true
+	}
+
+	replay := make([]models.Event, 0)
+	for i := 0; i < b.count; i++ {
+		if event := b.buffer[(b.start+i)%len(b.buffer)]; event.ID > lastEventID {
+			replay = append(replay, event)
+		}
+	}
+
+	oldest := b.lastID + 1
+	if b.count > 0 {
+		oldest = b.buffer[b.start].ID
+	}
+	complete := lastEventID <= b.lastID && lastEventID+1 >= oldest
+	return sub, replay, complete
+}
+
+// Events devuelve el canal de eventos; se cierra con Close o si el suscriptor se queda atrás
+func (s *EventSubscription) Events() <-chan models.Event {
+	return s.events
+}
+
+// Close da de baja al suscriptor
+func (s *EventSubscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+
+	if _, ok := s.bus.subscribers[s]; ok {
+		delete(s.bus.subscribers, s)
+		close(s.events)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+func TestEventBus_ReplaysFromLastEventID(t *testing.T) {
+	bus := NewEventBus(3)
+	first, _, _ := bus.Subscribe(0)
+	defer first.Close()
+
+	for _, ticker := range []string{"AAPL", "MSFT", "NVDA", "TSLA"} {
+		bus.Publish(models.Event{Type: models.EventStockCreated, Ticker: ticker})
+	}
+
+	ids := make([]uint64, 0, 4)
+	for i := 0; i < 4; i++ {
+		event := <-first.Events()
+		if event.Time.IsZero() || (i > 0 && event.ID != ids[i-1]+1) {
+			t.Fatalf("event %d not sequenced: %+v", i, event)
+		}
+		ids = append(ids, event.ID)
+	}
+
+	// El buffer conserva MSFT, NVDA y TSLA: reanudar desde MSFT es completo
+	resumed, replay, complete := bus.Subscribe(ids[1])
+	defer resumed.Close()
+	if !complete || len(replay) != 2 || replay[0].Ticker != "NVDA" || replay[1].Ticker != "TSLA" {
+		t.Fatalf("resume after MSFT: complete=%v replay=%+v", complete, replay)
+	}
+
+	// AAPL ya salió del buffer: desde antes de AAPL faltan eventos
+	lagging, replay, complete := bus.Subscribe(ids[0] - 1)
+	defer lagging.Close()
+	if complete || len(replay) != 3 {
+		t.Fatalf("resume before AAPL: complete=%v replay=%d events", complete, len(replay))
+	}
+
+	// Un id mayor que el último publicado (p. ej. de otro proceso) tampoco es reanudable
+	if _, replay, complete := bus.Subscribe(ids[3] + 10); complete || len(replay) != 0 {
+		t.Fatalf("future id: complete=%v replay=%+v", complete, replay)
+	}
+}
+
+func TestEventBus_DisconnectsSlowSubscribers(t *testing.T) {
+	bus := NewEventBus(10)
+	slow, _, _ := bus.Subscribe(0)
+	fast, _, _ := bus.Subscribe(0)
+	defer fast.Close()
+
+	for i := 0; i < eventSubscriberBuffer+1; i++ {
+		bus.Publish(models.Event{Type: models.EventSyncProgress})
+		<-fast.Events()
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	if received != eventSubscriberBuffer {
+		t.Fatalf("slow subscriber got %d events before being closed, want %d", received, eventSubscriberBuffer)
+	}
+	slow.Close() // cerrar dos veces no debe entrar en pánico
+
+	bus.Publish(models.Event{Type: models.EventSyncCompleted})
+	if event := <-fast.Events(); event.Type != models.EventSyncCompleted {
+		t.Fatalf("fast subscriber should keep receiving, got %+v", event)
+	}
+}
+
+func TestEventFilter_Matches(t *testing.T) {
+	filter := EventFilter{Tickers: []string{"AAPL"}, Brokerages: []string{"goldman sachs"}}
+
+	tests := []struct {
+		event models.Event
+		want  bool
+	}{
+		{models.Event{Type: models.EventStockCreated, Ticker: "AAPL", Brokerage: "Goldman Sachs"}, true},
+		{models.Event{Type: models.EventStockCreated, Ticker: "AAPL", Brokerage: "UBS"}, false},
+		{models.Event{Type: models.EventStockUpdated, Ticker: "MSFT", Brokerage: "Goldman Sachs"}, false},
+		{models.Event{Type: models.EventSyncProgress}, true},
+		{models.Event{Type: models.EventRecommendationChanged, Ticker: "AAPL"}, true},
+	}
+	for _, tc := range tests {
+		if got := filter.Matches(tc.event); got != tc.want {
+			t.Fatalf("Matches(%+v) = %v, want %v", tc.event, got, tc.want)
+		}
+	}
+}
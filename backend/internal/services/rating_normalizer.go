@@ -0,0 +1,165 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const ratingBackfillBatchSize = 500
+
+// ErrInvalidRatingMapping se devuelve cuando una regla de mapeo no es válida
+var ErrInvalidRatingMapping = errors.New("invalid rating mapping")
+
+// RatingNormalizer traduce ratings libres a la taxonomía canónica.
+// Las reglas administradas (tabla rating_mappings) tienen prioridad sobre ratingSignals.
+type RatingNormalizer struct {
+	repo repositories.RatingMappingRepository
+
+	mu     sync.RWMutex
+	custom map[string]models.RatingMapping
+}
+
+// NewRatingNormalizer crea una nueva instancia del normalizador de ratings
+func NewRatingNormalizer(repo repositories.RatingMappingRepository) *RatingNormalizer {
+	return &RatingNormalizer{
+		repo:   repo,
+		custom: map[string]models.RatingMapping{},
+	}
+}
+
+// Load carga en memoria las reglas administradas
+func (n *RatingNormalizer) Load() error {
+	items, err := n.repo.ListMappings()
+	if err != nil {
+		return err
+	}
+
+	custom := make(map[string]models.RatingMapping, len(items))
+	for _, item := range items {
+		custom[normalizeText(item.RawRating)] = item
+	}
+
+	n.mu.Lock()
+	n.custom = custom
+	n.mu.Unlock()
+	return nil
+}
+
+// Normalize devuelve el rating canónico y su score (escala 0-5).
+// Un rating vacío devuelve "" y uno no reconocido devuelve RatingUnmapped.
+func (n *RatingNormalizer) Normalize(raw string) (models.CanonicalRating, float64) {
+	key := normalizeText(raw)
+	if key == "" {
+		return "", 0
+	}
+
+	n.mu.RLock()
+	mapping, ok := n.custom[key]
+	n.mu.RUnlock()
+	if ok {
+		return mapping.Canonical, mapping.Score
+	}
+
+	if signal, ok := matchRatingSignal(key); ok {
+		return signal.canonical, signal.score
+	}
+	return models.RatingUnmapped, defaultUnknownRating
+}
+
+// Enrich implementa StockEnricher rellenando las columnas normalizadas del stock
+func (n *RatingNormalizer) Enrich(stock *models.Stock) {
+	stock.RatingFromCanonical, stock.RatingFromScore = n.Normalize(stock.RatingFrom)
+	stock.RatingToCanonical, stock.RatingToScore = n.Normalize(stock.RatingTo)
+}
+
+// Backfill recorre por lotes los registros con rating crudo sin normalizar y los completa.
+// Devuelve cuántos registros se actualizaron.
+func (n *RatingNormalizer) Backfill(repo repositories.StockRatingRepository) (int, error) {
+	updated := 0
+	var afterID uint64
+	for {
+		batch, err := repo.FindRatingBackfillBatch(afterID, ratingBackfillBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			stock := batch[i]
+			afterID = stock.ID
+			n.Enrich(&stock)
+			if err := repo.UpdateRatingValues(&stock); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		log.Printf("🔄 Rating backfill: %d records updated (last id %d)", updated, afterID)
+	}
+	return updated, nil
+}
+
+// ListMappings devuelve las reglas administradas
+func (n *RatingNormalizer) ListMappings() ([]models.RatingMapping, error) {
+	return n.repo.ListMappings()
+}
+
+// ListUnmapped devuelve los ratings crudos que ninguna regla reconoce, con su frecuencia
+func (n *RatingNormalizer) ListUnmapped() ([]models.UnmappedRating, error) {
+	return n.repo.ListUnmapped()
+}
+
+// AddMapping guarda una regla y la aplica a los registros existentes con ese rating crudo.
+// Si score es nil se usa el score por defecto del rating canónico.
+// Devuelve la regla guardada y la cantidad de columnas de rating actualizadas.
+func (n *RatingNormalizer) AddMapping(raw string, canonical models.CanonicalRating, score *float64) (*models.RatingMapping, int64, error) {
+	key := normalizeText(raw)
+	if key == "" {
+		return nil, 0, fmt.Errorf("%w: raw rating is required", ErrInvalidRatingMapping)
+	}
+	if _, ok := models.ParseCanonicalRating(string(canonical)); !ok {
+		return nil, 0, fmt.Errorf("%w: unknown canonical rating %q", ErrInvalidRatingMapping, canonical)
+	}
+
+	value := canonical.DefaultScore()
+	if score != nil {
+		if *score < 0 || *score > 5 {
+			return nil, 0, fmt.Errorf("%w: score must be between 0 and 5", ErrInvalidRatingMapping)
+		}
+		value = *score
+	}
+
+	mapping := &models.RatingMapping{RawRating: key, Canonical: canonical, Score: value}
+	if err := n.repo.SaveMapping(mapping); err != nil {
+		return nil, 0, err
+	}
+
+	n.mu.Lock()
+	n.custom[key] = *mapping
+	n.mu.Unlock()
+
+	stored, err := n.repo.DistinctRawRatings()
+	if err != nil {
+		return nil, 0, err
+	}
+	matching := make([]string, 0)
+	for _, rawValue := range stored {
+		if normalizeText(rawValue) == key {
+			matching = append(matching, rawValue)
+		}
+	}
+
+	updated, err := n.repo.ApplyMapping(matching, canonical, value)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	log.Printf("✅ Rating mapping saved: %q -> %s (%d columns updated)", key, canonical, updated)
+	return mapping, updated, nil
+}
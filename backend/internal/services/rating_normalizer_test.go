@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+type fakeRatingRepo struct {
+	mappings []models.RatingMapping
+	raw      []string
+	applied  []string
+	saveErr  error
+}
+
+func (f *fakeRatingRepo) ListMappings() ([]models.RatingMapping, error) { return f.mappings, nil }
+func (f *fakeRatingRepo) SaveMapping(mapping *models.RatingMapping) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	f.mappings = append(f.mappings, *mapping)
+	return nil
+}
+func (f *fakeRatingRepo) ListUnmapped() ([]models.UnmappedRating, error) { return nil, nil }
+func (f *fakeRatingRepo) DistinctRawRatings() ([]string, error)          { return f.raw, nil }
+func (f *fakeRatingRepo) ApplyMapping(rawValues []string, _ models.CanonicalRating, _ float64) (int64, error) {
+	f.applied = rawValues
+	return int64(len(rawValues)), nil
+}
+
+func TestRatingNormalizer_BuiltInRules(t *testing.T) {
+	n := NewRatingNormalizer(&fakeRatingRepo{})
+
+	tests := []struct {
+		raw       string
+		canonical models.CanonicalRating
+	}{
+		{raw: "Strong-Buy", canonical: models.RatingStrongBuy},
+		{raw: "Sector Outperform", canonical: models.RatingBuy},
+		{raw: "Outperform", canonical: models.RatingBuy},
+		{raw: "Equal Weight", canonical: models.RatingHold},
+		{raw: "Underperform", canonical: models.RatingSell},
+		{raw: "Strong Sell", canonical: models.RatingStrongSell},
+		{raw: "Top Pick", canonical: models.RatingUnmapped},
+		{raw: "  ", canonical: ""},
+	}
+
+	for _, tt := range tests {
+		got, _ := n.Normalize(tt.raw)
+		if got != tt.canonical {
+			t.Fatalf("Normalize(%q) = %q, want %q", tt.raw, got, tt.canonical)
+		}
+	}
+}
+
+func TestRatingNormalizer_CustomMappingOverridesAndBackfills(t *testing.T) {
+	repo := &fakeRatingRepo{raw: []string{"Top Pick", "top-pick", "Buy"}}
+	n := NewRatingNormalizer(repo)
+
+	mapping, updated, err := n.AddMapping(" Top Pick ", models.RatingStrongBuy, nil)
+	if err != nil {
+		t.Fatalf("AddMapping error: %v", err)
+	}
+	if mapping.RawRating != "top pick" || mapping.Score != 5.0 {
+		t.Fatalf("unexpected mapping: %+v", mapping)
+	}
+	if updated != 2 || len(repo.applied) != 2 {
+		t.Fatalf("expected both raw spellings backfilled, got %v", repo.applied)
+	}
+
+	stock := models.Stock{RatingFrom: "Hold", RatingTo: "TOP PICK"}
+	n.Enrich(&stock)
+	if stock.RatingToCanonical != models.RatingStrongBuy || stock.RatingToScore != 5.0 {
+		t.Fatalf("custom mapping not applied: %+v", stock)
+	}
+	if stock.RatingFromCanonical != models.RatingHold {
+		t.Fatalf("built-in rule not applied: %+v", stock)
+	}
+
+	// Una instancia nueva recupera las reglas desde el repositorio
+	reloaded := NewRatingNormalizer(repo)
+	if err := reloaded.Load(); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	if got, _ := reloaded.Normalize("Top Pick"); got != models.RatingStrongBuy {
+		t.Fatalf("reloaded Normalize = %q", got)
+	}
+}
+
+type fakeRatingBackfillRepo struct {
+	rows    []models.Stock
+	updates map[uint64]models.Stock
+}
+
+func (f *fakeRatingBackfillRepo) FindRatingBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var out []models.Stock
+	for _, row := range f.rows {
+		if row.ID > afterID && len(out) < limit {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRatingBackfillRepo) UpdateRatingValues(stock *models.Stock) error {
+	f.updates[stock.ID] = *stock
+	return nil
+}
+
+func TestRatingNormalizer_Backfill(t *testing.T) {
+	rows := make([]models.Stock, 0, ratingBackfillBatchSize+1)
+	for i := 1; i <= ratingBackfillBatchSize+1; i++ {
+		rows = append(rows, models.Stock{ID: uint64(i), RatingFrom: "Hold", RatingTo: "Outperform"})
+	}
+	repo := &fakeRatingBackfillRepo{rows: rows, updates: map[uint64]models.Stock{}}
+
+	updated, err := NewRatingNormalizer(&fakeRatingRepo{}).Backfill(repo)
+	if err != nil {
+		t.Fatalf("Backfill error: %v", err)
+	}
+	if updated != len(rows) || len(repo.updates) != len(rows) {
+		t.Fatalf("updated = %d, want %d across batches", updated, len(rows))
+	}
+	last := repo.updates[uint64(len(rows))]
+	if last.RatingFromCanonical != models.RatingHold || last.RatingToCanonical != models.RatingBuy || last.RatingToScore == 0 {
+		t.Fatalf("unexpected backfilled row: %+v", last)
+	}
+}
+
+func TestRatingNormalizer_AddMappingValidation(t *testing.T) {
+	n := NewRatingNormalizer(&fakeRatingRepo{})
+	badScore := 7.0
+
+	if _, _, err := n.AddMapping("", models.RatingBuy, nil); !errors.Is(err, ErrInvalidRatingMapping) {
+		t.Fatalf("expected ErrInvalidRatingMapping for empty raw, got %v", err)
+	}
+	if _, _, err := n.AddMapping("x", models.RatingUnmapped, nil); !errors.Is(err, ErrInvalidRatingMapping) {
+		t.Fatalf("expected ErrInvalidRatingMapping for unmapped canonical, got %v", err)
+	}
+	if _, _, err := n.AddMapping("x", models.RatingBuy, &badScore); !errors.Is(err, ErrInvalidRatingMapping) {
+		t.Fatalf("expected ErrInvalidRatingMapping for score, got %v", err)
+	}
+}
+
+func TestStockRatingScores_PrefersNormalizedColumns(t *testing.T) {
+	stock := models.Stock{RatingFrom: "Buy", RatingTo: "Top Pick", RatingToCanonical: models.RatingStrongBuy, RatingToScore: 5.0}
+
+	from, to := stockRatingScores(stock)
+	if from != 4.0 {
+		t.Fatalf("expected raw fallback for unnormalized rating_from, got %v", from)
+	}
+	if to != 5.0 {
+		t.Fatalf("expected stored score for rating_to, got %v", to)
+	}
+}
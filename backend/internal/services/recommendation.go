@@ -15,8 +15,9 @@ import (
 )
 
 type RecommendationService struct {
-	repo repositories.StockRepository
-	now  func() time.Time
+	repo    repositories.StockRepository
+	weigher BrokerageWeigher
+	now     func() time.Time
 }
 
 type featureVector struct {
@@ -27,7 +28,9 @@ type featureVector struct {
 	RatingChange      float64
 	RecentActivity    float64
 	HistoryConsensus  float64
+	StreetConsensus   float64
 	DataQuality       float64
+	BrokerageWeight   float64
 }
 
 type priceFluctuation struct {
@@ -37,6 +40,17 @@ type priceFluctuation struct {
 	HasPricePair  bool
 }
 
+type scoreBreakdown struct {
+	features       featureVector
+	weighted       []weightedFeature
+	rawScore       float64
+	qualityFactor  float64
+	finalScore     float64
+	confidence     models.ConfidenceLevel
+	confidenceRule string
+	reason         string
+}
+
 type weightedFeature struct {
 	label        string
 	weight       float64
@@ -49,66 +63,83 @@ type weightedFeature struct {
 
 var noiseTextRegex = regexp.MustCompile(`[^a-z0-9\s]+`)
 
+const (
+	recommendationModelName    = "weighted-feature-scoring"
+	recommendationModelVersion = "2.0.0"
+)
+
+var confidenceRules = []models.ConfidenceRule{
+	{Level: models.ConfidenceHigh, MinScore: 74, MinHistory: 4, MinDataQuality: 72},
+	{Level: models.ConfidenceMedium, MinScore: 58, MinHistory: 2, MinDataQuality: 48},
+}
+
 const (
 	minimumAcceptedScore = 55.0
+	maximumBearishScore  = 45.0
 	maxHistoryItems      = 8
 	defaultUnknownRating = 2.0
 )
 
-var ratingSignals = []struct {
-	pattern string
-	score   float64
-}{
-	{pattern: "strong buy", score: 5.0},
-	{pattern: "speculative buy", score: 4.5},
-	{pattern: "market outperform", score: 4.0},
-	{pattern: "sector outperform", score: 4.0},
-	{pattern: "outperformer", score: 4.0},
-	{pattern: "buy", score: 4.0},
-	{pattern: "overweight", score: 3.5},
-	{pattern: "outperform", score: 3.5},
-	{pattern: "positive", score: 3.5},
-	{pattern: "accumulate", score: 3.0},
-	{pattern: "equal weight", score: 2.0},
-	{pattern: "in line", score: 2.0},
-	{pattern: "market perform", score: 2.0},
-	{pattern: "sector perform", score: 2.0},
-	{pattern: "neutral", score: 2.0},
-	{pattern: "hold", score: 2.0},
-	{pattern: "cautious", score: 1.5},
-	{pattern: "underweight", score: 1.0},
-	{pattern: "underperform", score: 0.5},
-	{pattern: "sector underperform", score: 0.5},
-	{pattern: "reduce", score: 0.5},
-	{pattern: "sell", score: 0.0},
-	{pattern: "strong sell", score: 0.0},
-}
-
-var actionSignals = []struct {
+type ratingSignal struct {
+	pattern   string
+	score     float64
+	canonical models.CanonicalRating
+}
+
+var ratingSignals = []ratingSignal{
+	{pattern: "strong buy", score: 5.0, canonical: models.RatingStrongBuy},
+	{pattern: "speculative buy", score: 4.5, canonical: models.RatingBuy},
+	{pattern: "market outperform", score: 4.0, canonical: models.RatingBuy},
+	{pattern: "sector outperform", score: 4.0, canonical: models.RatingBuy},
+	{pattern: "outperformer", score: 4.0, canonical: models.RatingBuy},
+	{pattern: "buy", score: 4.0, canonical: models.RatingBuy},
+	{pattern: "overweight", score: 3.5, canonical: models.RatingBuy},
+	{pattern: "outperform", score: 3.5, canonical: models.RatingBuy},
+	{pattern: "positive", score: 3.5, canonical: models.RatingBuy},
+	{pattern: "accumulate", score: 3.0, canonical: models.RatingBuy},
+	{pattern: "equal weight", score: 2.0, canonical: models.RatingHold},
+	{pattern: "in line", score: 2.0, canonical: models.RatingHold},
+	{pattern: "market perform", score: 2.0, canonical: models.RatingHold},
+	{pattern: "sector perform", score: 2.0, canonical: models.RatingHold},
+	{pattern: "neutral", score: 2.0, canonical: models.RatingHold},
+	{pattern: "hold", score: 2.0, canonical: models.RatingHold},
+	{pattern: "cautious", score: 1.5, canonical: models.RatingHold},
+	{pattern: "underweight", score: 1.0, canonical: models.RatingSell},
+	{pattern: "underperform", score: 0.5, canonical: models.RatingSell},
+	{pattern: "sector underperform", score: 0.5, canonical: models.RatingSell},
+	{pattern: "reduce", score: 0.5, canonical: models.RatingSell},
+	{pattern: "sell", score: 0.0, canonical: models.RatingSell},
+	{pattern: "strong sell", score: 0.0, canonical: models.RatingStrongSell},
+}
+
+type actionSignalRule struct {
 	pattern string
 	score   float64
-}{
-	{pattern: "target raised by", score: 92},
-	{pattern: "target raised", score: 90},
-	{pattern: "raises target", score: 90},
-	{pattern: "raise target", score: 90},
-	{pattern: "upgraded", score: 75},
-	{pattern: "upgrade", score: 75},
-	{pattern: "initiated with buy", score: 65},
-	{pattern: "initiated", score: 30},
-	{pattern: "reiterated buy", score: 40},
-	{pattern: "reiterated", score: 15},
-	{pattern: "maintains buy", score: 35},
-	{pattern: "maintained buy", score: 35},
-	{pattern: "maintains", score: 10},
-	{pattern: "target lowered by", score: -92},
-	{pattern: "target lowered", score: -90},
-	{pattern: "lowers target", score: -90},
-	{pattern: "lower target", score: -90},
-	{pattern: "downgraded", score: -75},
-	{pattern: "downgrade", score: -75},
-	{pattern: "suspended", score: -60},
-	{pattern: "removed", score: -50},
+	event   models.ActionEvent
+}
+
+var actionSignals = []actionSignalRule{
+	{pattern: "target raised by", score: 92, event: models.ActionEventTargetRaise},
+	{pattern: "target raised", score: 90, event: models.ActionEventTargetRaise},
+	{pattern: "raises target", score: 90, event: models.ActionEventTargetRaise},
+	{pattern: "raise target", score: 90, event: models.ActionEventTargetRaise},
+	{pattern: "upgraded", score: 75, event: models.ActionEventUpgrade},
+	{pattern: "upgrade", score: 75, event: models.ActionEventUpgrade},
+	{pattern: "initiated with buy", score: 65, event: models.ActionEventInitiation},
+	{pattern: "initiated", score: 30, event: models.ActionEventInitiation},
+	{pattern: "reiterated buy", score: 40, event: models.ActionEventReiteration},
+	{pattern: "reiterated", score: 15, event: models.ActionEventReiteration},
+	{pattern: "maintains buy", score: 35, event: models.ActionEventReiteration},
+	{pattern: "maintained buy", score: 35, event: models.ActionEventReiteration},
+	{pattern: "maintains", score: 10, event: models.ActionEventReiteration},
+	{pattern: "target lowered by", score: -92, event: models.ActionEventTargetCut},
+	{pattern: "target lowered", score: -90, event: models.ActionEventTargetCut},
+	{pattern: "lowers target", score: -90, event: models.ActionEventTargetCut},
+	{pattern: "lower target", score: -90, event: models.ActionEventTargetCut},
+	{pattern: "downgraded", score: -75, event: models.ActionEventDowngrade},
+	{pattern: "downgrade", score: -75, event: models.ActionEventDowngrade},
+	{pattern: "suspended", score: -60, event: models.ActionEventCoverageDrop},
+	{pattern: "removed", score: -50, event: models.ActionEventCoverageDrop},
 }
 
 // NewRecommendationService crea una nueva instancia del servicio de recomendaciones
@@ -119,40 +150,109 @@ func NewRecommendationService(repo repositories.StockRepository) *Recommendation
 	}
 }
 
+// NewRecommendationServiceWithReliability crea el servicio ponderando cada llamada
+// por la credibilidad histórica de su casa de bolsa
+func NewRecommendationServiceWithReliability(repo repositories.StockRepository, weigher BrokerageWeigher) *RecommendationService {
+	rs := NewRecommendationService(repo)
+	rs.weigher = weigher
+	return rs
+}
+
 // GetRecommendations obtiene las mejores recomendaciones de inversión
-func (rs *RecommendationService) GetRecommendations(limit int) ([]models.StockRecommendation, error) {
+func (rs *RecommendationService) GetRecommendations(filter RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
 	log.Println("🔍 Calculating stock recommendations...")
 
-	var stocks []models.Stock
+	recommendations, model, err := rs.scoreTickers(filter)
+	if err != nil {
+		return nil, model, err
+	}
+
+	// Ordenar por score descendente
+	recommendations = rs.sortRecommendations(recommendations)
+
+	// Filtrar recomendaciones con score suficientemente bueno. Con un score mínimo
+	// explícito no se rellena con resultados por debajo del umbral.
+	threshold := minimumAcceptedScore
+	if filter.MinScore > 0 {
+		threshold = filter.MinScore
+	}
+	filtered := make([]models.StockRecommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		if rec.Score >= threshold {
+			filtered = append(filtered, rec)
+		}
+	}
+
+	if len(filtered) > 0 || filter.MinScore > 0 {
+		recommendations = filtered
+	}
+
+	// Limitar resultados
+	if filter.Limit > 0 && len(recommendations) > filter.Limit {
+		recommendations = recommendations[:filter.Limit]
+	}
 
-	// Buscar primero en la ventana reciente y luego ampliar si no hay datos.
-	thirtyDaysAgo := rs.now().AddDate(0, 0, -30)
-	var err error
-	stocks, err = rs.repo.FindSince(thirtyDaysAgo)
+	log.Printf("✅ Generated %d recommendations", len(recommendations))
+	return recommendations, model, nil
+}
+
+// GetBearishRecommendations obtiene los tickers con las señales más negativas (vender/evitar)
+func (rs *RecommendationService) GetBearishRecommendations(filter RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+	log.Println("🔍 Calculating bearish signals...")
+
+	// El score mínimo solo tiene sentido en la lista alcista.
+	filter.MinScore = 0
+	recommendations, model, err := rs.scoreTickers(filter)
 	if err != nil {
-		return nil, err
+		return nil, model, err
 	}
 
-	if len(stocks) == 0 {
-		ninetyDaysAgo := rs.now().AddDate(0, 0, -90)
-		stocks, err = rs.repo.FindSince(ninetyDaysAgo)
-		if err != nil {
-			return nil, err
+	// Ordenar por score ascendente: primero las señales más bajistas
+	sort.Slice(recommendations, func(i, j int) bool {
+		return recommendations[i].Score < recommendations[j].Score
+	})
+
+	// A diferencia de la lista alcista no se rellena con tickers neutrales:
+	// solo se reportan señales realmente negativas.
+	filtered := make([]models.StockRecommendation, 0, len(recommendations))
+	for _, rec := range recommendations {
+		if rec.Score <= maximumBearishScore {
+			filtered = append(filtered, rec)
 		}
 	}
 
-	if len(stocks) == 0 {
-		return []models.StockRecommendation{}, nil
+	if filter.Limit > 0 && len(filtered) > filter.Limit {
+		filtered = filtered[:filter.Limit]
+	}
+
+	log.Printf("✅ Generated %d bearish signals", len(filtered))
+	return filtered, model, nil
+}
+
+// scoreTickers calcula el score del registro más reciente de cada ticker de la ventana
+// que pasa los filtros de registros y de confianza
+func (rs *RecommendationService) scoreTickers(filter RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+	stocks, lookbackDays, err := rs.loadWindow(filter.LookbackDays)
+	if err != nil {
+		return nil, models.RecommendationModel{}, err
 	}
 
+	stocks = filter.filterStocks(stocks)
+
 	// Agrupar stocks por ticker
 	stocksByTicker := make(map[string][]models.Stock)
 	for _, stock := range stocks {
 		stocksByTicker[stock.Ticker] = append(stocksByTicker[stock.Ticker], stock)
 	}
 
+	model := rs.ModelMetadata()
+	model.LookbackDays = lookbackDays
+	model.WindowStart = rs.now().AddDate(0, 0, -lookbackDays)
+	model.RecordsEvaluated = len(stocks)
+	model.TickersEvaluated = len(stocksByTicker)
+
 	// Calcular score para cada ticker
-	var recommendations []models.StockRecommendation
+	recommendations := make([]models.StockRecommendation, 0, len(stocksByTicker))
 
 	for _, tickerStocks := range stocksByTicker {
 		if len(tickerStocks) == 0 {
@@ -164,60 +264,154 @@ func (rs *RecommendationService) GetRecommendations(limit int) ([]models.StockRe
 		})
 		latestStock := tickerStocks[0]
 
-		score, reason, confidence := rs.calculateScore(latestStock, tickerStocks)
-		recommendations = append(recommendations, models.StockRecommendation{
+		breakdown := rs.calculateScore(latestStock, tickerStocks)
+		rec := models.StockRecommendation{
 			Stock:      latestStock,
-			Score:      score,
-			Reason:     reason,
-			Confidence: confidence,
-		})
+			Score:      breakdown.finalScore,
+			Reason:     breakdown.reason,
+			Confidence: breakdown.confidence,
+			Factors:    buildFactors(breakdown.weighted),
+		}
+		if !filter.matchesResult(rec) {
+			continue
+		}
+		recommendations = append(recommendations, rec)
 	}
 
-	// Ordenar por score descendente
-	recommendations = rs.sortRecommendations(recommendations)
+	return recommendations, model, nil
+}
 
-	// Filtrar recomendaciones con score suficientemente bueno.
-	filtered := make([]models.StockRecommendation, 0, len(recommendations))
-	for _, rec := range recommendations {
-		if rec.Score >= minimumAcceptedScore {
-			filtered = append(filtered, rec)
+// ModelMetadata describe el modelo vigente: pesos de cada feature y umbrales de corte
+func (rs *RecommendationService) ModelMetadata() models.RecommendationModel {
+	// Los pesos se leen de scoreWithWeights para que nunca se desincronicen del scoring real.
+	weighted, _ := rs.scoreWithWeights(featureVector{}, models.Stock{})
+	weights := make([]models.FeatureWeight, 0, len(weighted))
+	for _, item := range weighted {
+		weights = append(weights, models.FeatureWeight{Label: item.label, Weight: item.weight})
+	}
+
+	return models.RecommendationModel{
+		Name:    recommendationModelName,
+		Version: recommendationModelVersion,
+		Weights: weights,
+		Thresholds: models.ModelThresholds{
+			MinimumAcceptedScore: minimumAcceptedScore,
+			MaximumBearishScore:  maximumBearishScore,
+			Confidence:           confidenceRules,
+		},
+		BrokerageWeighting: rs.weigher != nil,
+	}
+}
+
+// ExplainRecommendation devuelve el desglose completo del score de un ticker
+func (rs *RecommendationService) ExplainRecommendation(ticker string) (*models.RecommendationExplanation, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+
+	stocks, _, err := rs.loadWindow(0)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]models.Stock, 0)
+	for _, stock := range stocks {
+		if strings.EqualFold(stock.Ticker, ticker) {
+			history = append(history, stock)
 		}
 	}
 
-	if len(filtered) > 0 {
-		recommendations = filtered
+	// Si el ticker no tiene actividad en la ventana se usa su historial completo.
+	if len(history) == 0 {
+		history, err = rs.repo.FindByTicker(ticker)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// Limitar resultados
-	if limit > 0 && len(recommendations) > limit {
-		recommendations = recommendations[:limit]
+	if len(history) == 0 {
+		return nil, repositories.ErrNotFound
 	}
 
-	log.Printf("✅ Generated %d recommendations", len(recommendations))
-	return recommendations, nil
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Time.After(history[j].Time)
+	})
+	latestStock := history[0]
+	breakdown := rs.calculateScore(latestStock, history)
+
+	return &models.RecommendationExplanation{
+		Ticker:              latestStock.Ticker,
+		Stock:               latestStock,
+		Score:               breakdown.finalScore,
+		RawScore:            breakdown.rawScore,
+		Confidence:          breakdown.confidence,
+		ConfidenceRule:      breakdown.confidenceRule,
+		Reason:              breakdown.reason,
+		DataQuality:         breakdown.features.DataQuality,
+		QualityFactor:       breakdown.qualityFactor,
+		BrokerageMultiplier: breakdown.features.BrokerageWeight,
+		Factors:             buildFactors(breakdown.weighted),
+		History:             rs.consensusItems(history),
+		HistoryCount:        len(history),
+	}, nil
+}
+
+// loadWindow carga los registros de los últimos lookbackDays días y devuelve la ventana
+// usada. Sin ventana explícita busca primero en los últimos 30 días y la amplía a 90 si
+// no hay datos.
+func (rs *RecommendationService) loadWindow(lookbackDays int) ([]models.Stock, int, error) {
+	if lookbackDays > 0 {
+		stocks, err := rs.repo.FindSince(rs.now().AddDate(0, 0, -lookbackDays))
+		return stocks, lookbackDays, err
+	}
+
+	stocks, err := rs.repo.FindSince(rs.now().AddDate(0, 0, -30))
+	if err != nil {
+		return nil, 30, err
+	}
+
+	if len(stocks) == 0 {
+		stocks, err = rs.repo.FindSince(rs.now().AddDate(0, 0, -90))
+		if err != nil {
+			return nil, 90, err
+		}
+		return stocks, 90, nil
+	}
+
+	return stocks, 30, nil
 }
 
 // calculateScore calcula el score de una acción basado en múltiples criterios
-func (rs *RecommendationService) calculateScore(stock models.Stock, history []models.Stock) (float64, string, models.ConfidenceLevel) {
+// y conserva cada paso intermedio del modelo
+func (rs *RecommendationService) calculateScore(stock models.Stock, history []models.Stock) scoreBreakdown {
 	features := rs.buildFeatureVector(stock, history)
 	weighted, rawScore := rs.scoreWithWeights(features, stock)
 	finalScore := rs.calibrateScore(rawScore, features.DataQuality)
-	confidence := rs.determineConfidence(finalScore, len(history), features.DataQuality)
+	confidence, rule := rs.determineConfidence(finalScore, len(history), features.DataQuality)
 	finalReason := rs.buildReason(weighted)
 	if finalReason == "" {
 		finalReason = "No significant changes detected"
 	}
 
-	return finalScore, finalReason, confidence
+	return scoreBreakdown{
+		features:       features,
+		weighted:       weighted,
+		rawScore:       rawScore,
+		qualityFactor:  qualityFactor(features.DataQuality),
+		finalScore:     finalScore,
+		confidence:     confidence,
+		confidenceRule: rule,
+		reason:         finalReason,
+	}
 }
 
 func (rs *RecommendationService) buildFeatureVector(stock models.Stock, history []models.Stock) featureVector {
 	fluctuation, targetQuality := rs.evaluatePriceFluctuation(stock)
 	comboScore := rs.evaluateActionRatingCombination(stock, fluctuation)
-	ratingQuality := rs.evaluateRatingQuality(stock.RatingTo)
+	_, ratingTo := stockRatingScores(stock)
+	ratingQuality := rs.evaluateRatingQuality(ratingTo)
 	ratingChange := rs.evaluateRatingChange(stock)
 	activityScore := rs.evaluateRecentActivity(stock)
 	consensusScore := rs.evaluateHistoryConsensus(history)
+	streetScore := consensusSignal(history, rs.now().AddDate(0, 0, -defaultConsensusWindowDays))
 
 	dataQuality := targetQuality
 	if strings.TrimSpace(stock.RatingTo) != "" {
@@ -236,27 +430,40 @@ func (rs *RecommendationService) buildFeatureVector(stock models.Stock, history
 		dataQuality += 10
 	}
 
+	// Las señales propias de la llamada se escalan según la credibilidad del broker.
+	brokerageWeight := rs.brokerageMultiplier(stock.Brokerage)
+
 	return featureVector{
-		PriceDirection:    fluctuation.Direction,
-		PriceMomentum:     fluctuation.Momentum,
-		ActionRatingCombo: comboScore,
+		PriceDirection:    clamp(fluctuation.Direction*brokerageWeight, -100, 100),
+		PriceMomentum:     clamp(fluctuation.Momentum*brokerageWeight, -100, 100),
+		ActionRatingCombo: clamp(comboScore*brokerageWeight, -100, 100),
 		RatingQuality:     ratingQuality,
-		RatingChange:      ratingChange,
+		RatingChange:      clamp(ratingChange*brokerageWeight, -100, 100),
 		RecentActivity:    activityScore,
 		HistoryConsensus:  consensusScore,
+		StreetConsensus:   streetScore,
 		DataQuality:       clamp(dataQuality, 0, 100),
+		BrokerageWeight:   brokerageWeight,
 	}
 }
 
+func (rs *RecommendationService) brokerageMultiplier(brokerage string) float64 {
+	if rs.weigher == nil {
+		return 1.0
+	}
+	return rs.weigher.Multiplier(brokerage)
+}
+
 func (rs *RecommendationService) scoreWithWeights(features featureVector, stock models.Stock) ([]weightedFeature, float64) {
 	weighted := []weightedFeature{
-		{label: "Price direction", weight: 0.36, value: features.PriceDirection, positiveText: "Target price moved upward", neutralText: "Target price is mostly unchanged", negativeText: "Target price moved downward"},
-		{label: "Price momentum", weight: 0.22, value: features.PriceMomentum, positiveText: "Magnitude of target change is bullish", neutralText: "Target change magnitude is small", negativeText: "Magnitude of target change is bearish"},
+		{label: "Price direction", weight: 0.33, value: features.PriceDirection, positiveText: "Target price moved upward", neutralText: "Target price is mostly unchanged", negativeText: "Target price moved downward"},
+		{label: "Price momentum", weight: 0.19, value: features.PriceMomentum, positiveText: "Magnitude of target change is bullish", neutralText: "Target change magnitude is small", negativeText: "Magnitude of target change is bearish"},
 		{label: "Action-rating combo", weight: 0.20, value: features.ActionRatingCombo, positiveText: fmt.Sprintf("Action and rating are aligned (%s / %s→%s)", stock.Action, stock.RatingFrom, stock.RatingTo), neutralText: "Action and rating combination is mixed", negativeText: fmt.Sprintf("Action and rating are bearish (%s / %s→%s)", stock.Action, stock.RatingFrom, stock.RatingTo)},
 		{label: "Rating quality", weight: 0.08, value: features.RatingQuality, positiveText: fmt.Sprintf("Current rating is favorable (%s)", stock.RatingTo), neutralText: "Current rating is neutral", negativeText: fmt.Sprintf("Current rating is weak (%s)", stock.RatingTo)},
 		{label: "Rating change", weight: 0.06, value: features.RatingChange, positiveText: "Rating improved", neutralText: "Rating is unchanged", negativeText: "Rating deteriorated"},
 		{label: "Recency", weight: 0.05, value: features.RecentActivity, positiveText: "Very recent signal", neutralText: "Moderately recent signal", negativeText: "Signal is stale"},
 		{label: "Consensus", weight: 0.03, value: features.HistoryConsensus, positiveText: "Recent history confirms bullish bias", neutralText: "Recent history is mixed", negativeText: "Recent history confirms bearish bias"},
+		{label: "Street consensus", weight: 0.06, value: features.StreetConsensus, positiveText: "Covering firms lean bullish", neutralText: "Covering firms are split", negativeText: "Covering firms lean bearish"},
 	}
 
 	score := 0.0
@@ -271,24 +478,40 @@ func (rs *RecommendationService) scoreWithWeights(features featureVector, stock
 func (rs *RecommendationService) buildReason(weighted []weightedFeature) string {
 	parts := make([]string, 0, len(weighted))
 	for _, item := range weighted {
-		explanation := item.label
-		if item.value >= 15 {
-			explanation = item.positiveText
-		} else if item.value <= -15 {
-			explanation = item.negativeText
-		} else if item.neutralText != "" {
-			explanation = item.neutralText
-		}
-
-		parts = append(parts, fmt.Sprintf("%s (+%.1f points)", explanation, item.contribution))
+		parts = append(parts, fmt.Sprintf("%s (+%.1f points)", item.explanation(), item.contribution))
 	}
 
 	return strings.Join(parts, ". ")
 }
 
+// explanation elige el texto que corresponde al valor de la feature
+func (f weightedFeature) explanation() string {
+	if f.value >= 15 {
+		return f.positiveText
+	} else if f.value <= -15 {
+		return f.negativeText
+	} else if f.neutralText != "" {
+		return f.neutralText
+	}
+	return f.label
+}
+
+func buildFactors(weighted []weightedFeature) []models.RecommendationFactor {
+	factors := make([]models.RecommendationFactor, 0, len(weighted))
+	for _, item := range weighted {
+		factors = append(factors, models.RecommendationFactor{
+			Label:        item.label,
+			Value:        item.value,
+			Weight:       item.weight,
+			Contribution: item.contribution,
+			Explanation:  item.explanation(),
+		})
+	}
+	return factors
+}
+
 func (rs *RecommendationService) evaluatePriceFluctuation(stock models.Stock) (priceFluctuation, float64) {
-	fromPrice := rs.parsePrice(stock.TargetFrom)
-	toPrice := rs.parsePrice(stock.TargetTo)
+	fromPrice, toPrice := stockTargetPrices(stock)
 	actionSignal := rs.evaluateActionSignal(stock.Action)
 
 	if fromPrice == 0 || toPrice == 0 {
@@ -321,9 +544,8 @@ func (rs *RecommendationService) evaluatePriceFluctuation(stock models.Stock) (p
 
 func (rs *RecommendationService) evaluateActionRatingCombination(stock models.Stock, fluctuation priceFluctuation) float64 {
 	actionSignal := rs.evaluateActionSignal(stock.Action)
-	ratingToScore := rs.ratingToScore(stock.RatingTo)
-	ratingFromScore := rs.ratingToScore(stock.RatingFrom)
-	ratingSignal := rs.evaluateRatingQuality(stock.RatingTo)
+	ratingFromScore, ratingToScore := stockRatingScores(stock)
+	ratingSignal := rs.evaluateRatingQuality(ratingToScore)
 	ratingTransition := clamp((ratingToScore-ratingFromScore)*35, -100, 100)
 
 	combo := actionSignal*0.44 + ratingSignal*0.32 + ratingTransition*0.24
@@ -376,38 +598,45 @@ func (rs *RecommendationService) evaluateActionRatingCombination(stock models.St
 
 // evaluateActionSignal transforma la metadata de action en una señal de mercado [-100, 100]
 func (rs *RecommendationService) evaluateActionSignal(action string) float64 {
-	normalized := normalizeText(action)
-	if normalized == "" {
+	return actionSignal(action)
+}
+
+func actionSignal(action string) float64 {
+	rule, ok := matchActionSignal(normalizeText(action))
+	if !ok {
 		return 0
 	}
+	return rule.score
+}
 
-	bestScore := 0.0
+// matchActionSignal devuelve la regla de actionSignals con el patrón más largo contenido en la acción normalizada
+func matchActionSignal(action string) (actionSignalRule, bool) {
+	if action == "" {
+		return actionSignalRule{}, false
+	}
+
+	var best actionSignalRule
 	bestPatternLen := 0
 	for _, signal := range actionSignals {
-		if strings.Contains(normalized, signal.pattern) {
-			if len(signal.pattern) > bestPatternLen {
-				bestPatternLen = len(signal.pattern)
-				bestScore = signal.score
-			}
+		if strings.Contains(action, signal.pattern) && len(signal.pattern) > bestPatternLen {
+			bestPatternLen = len(signal.pattern)
+			best = signal
 		}
 	}
 
-	return bestScore
+	return best, bestPatternLen > 0
 }
 
 // evaluateRatingChange evalúa el cambio en el rating
 func (rs *RecommendationService) evaluateRatingChange(stock models.Stock) float64 {
-	fromRating := rs.ratingToScore(stock.RatingFrom)
-	toRating := rs.ratingToScore(stock.RatingTo)
+	fromRating, toRating := stockRatingScores(stock)
 
 	diff := toRating - fromRating
 
 	return clamp(diff*35, -100, 100)
 }
 
-func (rs *RecommendationService) evaluateRatingQuality(rating string) float64 {
-	score := rs.ratingToScore(rating)
-
+func (rs *RecommendationService) evaluateRatingQuality(score float64) float64 {
 	// Mapea de [0,5] a [-100,100]
 	return ((score - 2.5) / 2.5) * 100
 }
@@ -435,28 +664,55 @@ func (rs *RecommendationService) evaluateRecentActivity(stock models.Stock) floa
 
 // ratingToScore convierte un rating en un valor numérico
 func (rs *RecommendationService) ratingToScore(rating string) float64 {
-	rating = normalizeText(rating)
+	return ratingScore(rating)
+}
 
-	if rating == "" {
+func ratingScore(rating string) float64 {
+	signal, ok := matchRatingSignal(normalizeText(rating))
+	if !ok {
 		return defaultUnknownRating
 	}
+	return signal.score
+}
+
+// matchRatingSignal devuelve la regla de ratingSignals con el patrón más largo contenido en el rating normalizado
+func matchRatingSignal(rating string) (ratingSignal, bool) {
+	if rating == "" {
+		return ratingSignal{}, false
+	}
 
-	bestScore := defaultUnknownRating
+	var best ratingSignal
 	bestPatternLen := 0
 	for _, signal := range ratingSignals {
-		if strings.Contains(rating, signal.pattern) {
-			if len(signal.pattern) > bestPatternLen {
-				bestPatternLen = len(signal.pattern)
-				bestScore = signal.score
-			}
+		if strings.Contains(rating, signal.pattern) && len(signal.pattern) > bestPatternLen {
+			bestPatternLen = len(signal.pattern)
+			best = signal
 		}
 	}
 
-	return bestScore
+	return best, bestPatternLen > 0
+}
+
+// stockRatingScores usa los scores normalizados al ingerir y solo re-parsea el texto crudo
+// en registros que aún no pasaron por el normalizador
+func stockRatingScores(stock models.Stock) (from, to float64) {
+	return storedRatingScore(stock.RatingFromCanonical, stock.RatingFromScore, stock.RatingFrom),
+		storedRatingScore(stock.RatingToCanonical, stock.RatingToScore, stock.RatingTo)
+}
+
+func storedRatingScore(canonical models.CanonicalRating, score float64, raw string) float64 {
+	if _, ok := models.ParseCanonicalRating(string(canonical)); ok {
+		return score
+	}
+	return ratingScore(raw)
 }
 
 // parsePrice extrae el valor numérico de un precio (ej: "$150.00" -> 150.00)
 func (rs *RecommendationService) parsePrice(price string) float64 {
+	return parsePrice(price)
+}
+
+func parsePrice(price string) float64 {
 	clean := strings.TrimSpace(price)
 	if clean == "" {
 		return 0
@@ -469,33 +725,47 @@ func (rs *RecommendationService) parsePrice(price string) float64 {
 	clean = strings.TrimSpace(clean)
 
 	value, err := strconv.ParseFloat(clean, 64)
-	if err != nil {
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
 		return 0
 	}
 	return value
 }
 
-func (rs *RecommendationService) determineConfidence(score float64, historyCount int, dataQuality float64) models.ConfidenceLevel {
-	if score >= 74 && historyCount >= 4 && dataQuality >= 72 {
-		return models.ConfidenceHigh
-	} else if score >= 58 && historyCount >= 2 && dataQuality >= 48 {
-		return models.ConfidenceMedium
-	} else {
-		return models.ConfidenceLow
+func (rs *RecommendationService) determineConfidence(score float64, historyCount int, dataQuality float64) (models.ConfidenceLevel, string) {
+	for _, rule := range confidenceRules {
+		if score >= rule.MinScore && historyCount >= rule.MinHistory && dataQuality >= rule.MinDataQuality {
+			return rule.Level, fmt.Sprintf("%s: score >= %.0f, history >= %d, data quality >= %.0f", rule.Level, rule.MinScore, rule.MinHistory, rule.MinDataQuality)
+		}
 	}
+	return models.ConfidenceLow, "low: high and medium thresholds not met"
 }
 
 func (rs *RecommendationService) evaluateHistoryConsensus(history []models.Stock) float64 {
-	if len(history) == 0 {
+	items := rs.consensusItems(history)
+	if len(items) == 0 {
 		return 0
 	}
 
+	total := 0.0
+	divisor := 0.0
+	for _, item := range items {
+		weight := item.RecencyWeight * item.BrokerageWeight
+		total += item.Signal * weight
+		divisor += weight
+	}
+
+	avg := total / divisor
+	return clamp(avg, -100, 100)
+}
+
+// consensusItems devuelve los registros recientes que alimentan el consenso con su peso
+func (rs *RecommendationService) consensusItems(history []models.Stock) []models.ConsensusItem {
 	maxItems := maxHistoryItems
 	if len(history) < maxItems {
 		maxItems = len(history)
 	}
 
-	total := 0.0
+	items := make([]models.ConsensusItem, 0, maxItems)
 	for i := 0; i < maxItems; i++ {
 		item := history[i]
 		recencyWeight := 1.0 - (float64(i) * 0.1)
@@ -504,21 +774,17 @@ func (rs *RecommendationService) evaluateHistoryConsensus(history []models.Stock
 		}
 		fluctuation, _ := rs.evaluatePriceFluctuation(item)
 		combo := rs.evaluateActionRatingCombination(item, fluctuation)
-		combined := fluctuation.Direction*0.45 + fluctuation.Momentum*0.20 + combo*0.25 + rs.evaluateRatingQuality(item.RatingTo)*0.10
-		total += combined * recencyWeight
-	}
-
-	divisor := 0.0
-	for i := 0; i < maxItems; i++ {
-		weight := 1.0 - (float64(i) * 0.1)
-		if weight < 0.3 {
-			weight = 0.3
-		}
-		divisor += weight
+		_, itemRating := stockRatingScores(item)
+		combined := fluctuation.Direction*0.45 + fluctuation.Momentum*0.20 + combo*0.25 + rs.evaluateRatingQuality(itemRating)*0.10
+		items = append(items, models.ConsensusItem{
+			Stock:           item,
+			RecencyWeight:   recencyWeight,
+			BrokerageWeight: rs.brokerageMultiplier(item.Brokerage),
+			Signal:          combined,
+		})
 	}
 
-	avg := total / divisor
-	return clamp(avg, -100, 100)
+	return items
 }
 
 func (rs *RecommendationService) sortRecommendations(recs []models.StockRecommendation) []models.StockRecommendation {
@@ -540,11 +806,15 @@ func normalizeText(value string) string {
 }
 
 func (rs *RecommendationService) calibrateScore(rawScore float64, dataQuality float64) float64 {
-	qualityFactor := 0.78 + (clamp(dataQuality, 0, 100) / 100.0 * 0.22)
-	adjusted := rawScore * qualityFactor
+	adjusted := rawScore * qualityFactor(dataQuality)
 	return clamp((adjusted+100.0)/2.0, 0, 100)
 }
 
+// qualityFactor atenúa el score crudo cuando los datos del registro son incompletos
+func qualityFactor(dataQuality float64) float64 {
+	return 0.78 + (clamp(dataQuality, 0, 100) / 100.0 * 0.22)
+}
+
 func clamp(value, minValue, maxValue float64) float64 {
 	if value < minValue {
 		return minValue
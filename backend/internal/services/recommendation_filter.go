@@ -0,0 +1,85 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+// RecommendationFilter restringe el universo de registros y de resultados del ranking.
+// Los filtros de registros se aplican antes de agrupar y puntuar; los de score y
+// confianza antes de ordenar y limitar.
+type RecommendationFilter struct {
+	Limit             int
+	MinScore          float64
+	MinConfidence     models.ConfidenceLevel
+	Brokerages        []string
+	ExcludeBrokerages []string
+	Tickers           []string
+	Actions           []string
+	LookbackDays      int
+}
+
+// matchesStock indica si un registro pertenece al universo seleccionado
+func (f RecommendationFilter) matchesStock(stock models.Stock) bool {
+	if len(f.Tickers) > 0 && !containsFold(f.Tickers, stock.Ticker) {
+		return false
+	}
+
+	brokerage := brokerageKey(stock.Brokerage)
+	if len(f.Brokerages) > 0 && !containsFold(f.Brokerages, brokerage) {
+		return false
+	}
+	if len(f.ExcludeBrokerages) > 0 && containsFold(f.ExcludeBrokerages, brokerage) {
+		return false
+	}
+
+	// Una acción se compara completa, o contra el tipo de evento clasificado (upgrade, target_raise…)
+	if len(f.Actions) > 0 && !containsFold(f.Actions, string(stock.EventType)) && !f.matchesAction(stock.Action) {
+		return false
+	}
+
+	return true
+}
+
+func (f RecommendationFilter) matchesAction(action string) bool {
+	action = normalizeText(action)
+	if action == "" {
+		return false
+	}
+	for _, candidate := range f.Actions {
+		if normalizeText(candidate) == action {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesResult indica si una recomendación supera los umbrales pedidos
+func (f RecommendationFilter) matchesResult(rec models.StockRecommendation) bool {
+	return rec.Score >= f.MinScore && rec.Confidence >= f.MinConfidence
+}
+
+func (f RecommendationFilter) filterStocks(stocks []models.Stock) []models.Stock {
+	if len(f.Tickers) == 0 && len(f.Brokerages) == 0 && len(f.ExcludeBrokerages) == 0 && len(f.Actions) == 0 {
+		return stocks
+	}
+
+	filtered := make([]models.Stock, 0, len(stocks))
+	for _, stock := range stocks {
+		if f.matchesStock(stock) {
+			filtered = append(filtered, stock)
+		}
+	}
+	return filtered
+}
+
+func containsFold(values []string, target string) bool {
+	target = strings.TrimSpace(target)
+	for _, value := range values {
+		if strings.EqualFold(strings.TrimSpace(value), target) {
+			return true
+		}
+	}
+	return false
+}
@@ -6,26 +6,59 @@ import (
 	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
 )
 
 type recoRepo struct {
-	findSinceFn func(since time.Time) ([]models.Stock, error)
+	findSinceFn    func(since time.Time) ([]models.Stock, error)
+	findByTickerFn func(ticker string) ([]models.Stock, error)
 }
 
 func (r *recoRepo) GetByTickerAndTime(string, time.Time) (*models.Stock, error) { return nil, nil }
 func (r *recoRepo) Create(*models.Stock) error                                  { return nil }
 func (r *recoRepo) Save(*models.Stock) error                                    { return nil }
-func (r *recoRepo) CountAll() (int64, error)                                    { return 0, nil }
-func (r *recoRepo) List(int, int, string, bool) ([]models.Stock, error)         { return nil, nil }
-func (r *recoRepo) FindByID(uint64) (*models.Stock, error)                      { return nil, nil }
-func (r *recoRepo) FindByTicker(string) ([]models.Stock, error)                 { return nil, nil }
-func (r *recoRepo) Search(string, int) ([]models.Stock, error)                  { return nil, nil }
-func (r *recoRepo) Filter(string, string, int, int) ([]models.Stock, int64, error) {
+func (r *recoRepo) UpdateDerivedColumns(*models.Stock) error                    { return nil }
+func (r *recoRepo) Count(repositories.StockFilter) (int64, error)               { return 0, nil }
+func (r *recoRepo) List(repositories.StockFilter, int, int, string, bool) ([]models.Stock, error) {
+	return nil, nil
+}
+func (r *recoRepo) ListPage(repositories.StockFilter, repositories.StockPageRequest) ([]models.Stock, error) {
+	return nil, nil
+}
+func (r *recoRepo) FindByID(uint64) (*models.Stock, error)              { return nil, nil }
+func (r *recoRepo) SearchCandidates() ([]models.SearchCandidate, error) { return nil, nil }
+func (r *recoRepo) TickerActivity() ([]models.TickerSuggestion, error)  { return nil, nil }
+func (r *recoRepo) LatestByTickers([]string) ([]models.Stock, error)    { return nil, nil }
+func (r *recoRepo) Filter(repositories.StockFilter, int, int) ([]models.Stock, int64, error) {
 	return nil, 0, nil
 }
-func (r *recoRepo) DistinctActions() ([]string, error) { return nil, nil }
-func (r *recoRepo) DistinctRatings() ([]string, error) { return nil, nil }
+func (r *recoRepo) DistinctActions() ([]string, error)          { return nil, nil }
+func (r *recoRepo) DistinctRatings() ([]string, error)          { return nil, nil }
+func (r *recoRepo) DistinctCanonicalRatings() ([]string, error) { return nil, nil }
+func (r *recoRepo) DistinctBrokerages() ([]string, error)       { return nil, nil }
+func (r *recoRepo) BrokerageActivity() ([]models.BrokerageSummary, error) {
+	return nil, nil
+}
+func (r *recoRepo) SentimentSeries(repositories.StockFilter, models.TimelineBucket) ([]models.SentimentPoint, error) {
+	return nil, nil
+}
+func (r *recoRepo) RatingTransitions(repositories.StockFilter, models.RatingMode) ([]models.RatingTransition, error) {
+	return nil, nil
+}
+func (r *recoRepo) CurrentRatingDistribution(models.RatingMode) ([]models.RatingShare, error) {
+	return nil, nil
+}
+func (r *recoRepo) CountByEventType() ([]models.EventTypeCount, error) {
+	return nil, nil
+}
 func (r *recoRepo) Latest(int) ([]models.Stock, error) { return nil, nil }
+func (r *recoRepo) FindByTicker(ticker string) ([]models.Stock, error) {
+	if r.findByTickerFn != nil {
+		return r.findByTickerFn(ticker)
+	}
+	return nil, nil
+}
+func (r *recoRepo) FindByTickers([]string) ([]models.Stock, error) { return nil, nil }
 func (r *recoRepo) FindSince(since time.Time) ([]models.Stock, error) {
 	if r.findSinceFn != nil {
 		return r.findSinceFn(since)
@@ -57,6 +90,9 @@ func TestRecommendationHelpers(t *testing.T) {
 	if got := rs.parsePrice("$1,234.50 USD"); got != 1234.5 {
 		t.Fatalf("parsePrice got %v", got)
 	}
+	if got := rs.parsePrice("$NaN"); got != 0 {
+		t.Fatalf("parsePrice of NaN got %v, want 0", got)
+	}
 
 	if got := rs.ratingToScore("Strong Buy"); got < 4.9 {
 		t.Fatalf("ratingToScore strong buy too low: %v", got)
@@ -86,7 +122,7 @@ func TestGetRecommendations_FallbackWindowAndLimit(t *testing.T) {
 	rs := NewRecommendationService(repo)
 	rs.now = func() time.Time { return fixedNow }
 
-	recs, err := rs.GetRecommendations(1)
+	recs, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 1})
 	if err != nil {
 		t.Fatalf("GetRecommendations error: %v", err)
 	}
@@ -104,8 +140,209 @@ func TestGetRecommendations_RepoError(t *testing.T) {
 	rs := NewRecommendationService(&recoRepo{findSinceFn: func(_ time.Time) ([]models.Stock, error) {
 		return nil, errors.New("db down")
 	}})
-	_, err := rs.GetRecommendations(10)
+	_, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 10})
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
 }
+
+func TestGetBearishRecommendations_RanksLowestFirst(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	repo := &recoRepo{
+		findSinceFn: func(_ time.Time) ([]models.Stock, error) {
+			return []models.Stock{
+				{Ticker: "AAA", TargetFrom: "$100", TargetTo: "$130", Action: "Target raised", RatingFrom: "Hold", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -1)},
+				{Ticker: "BBB", TargetFrom: "$100", TargetTo: "$90", Action: "Target lowered", RatingFrom: "Hold", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -2)},
+				{Ticker: "CCC", TargetFrom: "$100", TargetTo: "$60", Action: "Downgraded", RatingFrom: "Buy", RatingTo: "Sell", Time: fixedNow.AddDate(0, 0, -1)},
+			}, nil
+		},
+	}
+
+	rs := NewRecommendationService(repo)
+	rs.now = func() time.Time { return fixedNow }
+
+	recs, _, err := rs.GetBearishRecommendations(RecommendationFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetBearishRecommendations error: %v", err)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("len(recs) = %d, want 2 (bullish ticker must be excluded)", len(recs))
+	}
+	if recs[0].Stock.Ticker != "CCC" || recs[0].Score > recs[1].Score {
+		t.Fatalf("expected CCC ranked first ascending, got %+v", recs)
+	}
+	for _, rec := range recs {
+		if rec.Score > maximumBearishScore || len(rec.Factors) == 0 {
+			t.Fatalf("unexpected bearish rec: %+v", rec)
+		}
+	}
+}
+
+func TestExplainRecommendation_BreakdownMatchesRanking(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	repo := &recoRepo{
+		findSinceFn: func(_ time.Time) ([]models.Stock, error) {
+			return []models.Stock{
+				{Ticker: "AAA", TargetFrom: "$100", TargetTo: "$130", Action: "Target raised", RatingFrom: "Hold", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -1)},
+				{Ticker: "AAA", TargetFrom: "$90", TargetTo: "$100", Action: "Upgraded", RatingFrom: "Sell", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -5)},
+				{Ticker: "BBB", TargetFrom: "$100", TargetTo: "$80", Action: "Downgraded", RatingFrom: "Buy", RatingTo: "Sell", Time: fixedNow.AddDate(0, 0, -2)},
+			}, nil
+		},
+	}
+
+	rs := NewRecommendationService(repo)
+	rs.now = func() time.Time { return fixedNow }
+
+	explanation, err := rs.ExplainRecommendation("aaa")
+	if err != nil {
+		t.Fatalf("ExplainRecommendation error: %v", err)
+	}
+	if explanation.Ticker != "AAA" || explanation.HistoryCount != 2 || len(explanation.History) != 2 {
+		t.Fatalf("unexpected explanation: %+v", explanation)
+	}
+	if len(explanation.Factors) != 8 {
+		t.Fatalf("len(factors) = %d, want 8", len(explanation.Factors))
+	}
+
+	sum := 0.0
+	for _, factor := range explanation.Factors {
+		sum += factor.Contribution
+	}
+	if diff := sum - explanation.RawScore; diff > 1e-9 || diff < -1e-9 {
+		t.Fatalf("factor contributions %v do not add up to raw score %v", sum, explanation.RawScore)
+	}
+	if explanation.ConfidenceRule == "" {
+		t.Fatalf("expected confidence rule to be reported")
+	}
+
+	recs, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetRecommendations error: %v", err)
+	}
+	if len(recs) == 0 || recs[0].Stock.Ticker != "AAA" || recs[0].Score != explanation.Score {
+		t.Fatalf("explanation score %v does not match ranking %+v", explanation.Score, recs)
+	}
+}
+
+func TestExplainRecommendation_FallsBackToFullHistory(t *testing.T) {
+	rs := NewRecommendationService(&recoRepo{
+		findByTickerFn: func(ticker string) ([]models.Stock, error) {
+			if ticker == "OLD" {
+				return []models.Stock{{Ticker: "OLD", RatingTo: "Hold", Time: time.Now().AddDate(-1, 0, 0)}}, nil
+			}
+			return nil, nil
+		},
+	})
+
+	if _, err := rs.ExplainRecommendation("OLD"); err != nil {
+		t.Fatalf("expected explanation from full history, got %v", err)
+	}
+	if _, err := rs.ExplainRecommendation("NONE"); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestGetRecommendations_FiltersBeforeRanking(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	var requestedSince []time.Time
+	repo := &recoRepo{
+		findSinceFn: func(since time.Time) ([]models.Stock, error) {
+			requestedSince = append(requestedSince, since)
+			return []models.Stock{
+				{Ticker: "AAA", Brokerage: "Goldman Sachs", TargetFrom: "$100", TargetTo: "$130", Action: "target raised by", EventType: models.ActionEventTargetRaise, RatingFrom: "Hold", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -1)},
+				{Ticker: "BBB", Brokerage: "Citi", TargetFrom: "$100", TargetTo: "$140", Action: "upgraded by", EventType: models.ActionEventUpgrade, RatingFrom: "Hold", RatingTo: "Strong-Buy", Time: fixedNow.AddDate(0, 0, -1)},
+				{Ticker: "CCC", Brokerage: "Goldman Sachs", TargetFrom: "$100", TargetTo: "$125", Action: "upgraded by", EventType: models.ActionEventUpgrade, RatingFrom: "Sell", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -2)},
+				{Ticker: "DDD", Brokerage: "Goldman Sachs", TargetFrom: "$100", TargetTo: "$101", Action: "reiterated by", EventType: models.ActionEventReiteration, RatingFrom: "Hold", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -2)},
+			}, nil
+		},
+	}
+
+	rs := NewRecommendationService(repo)
+	rs.now = func() time.Time { return fixedNow }
+
+	recs, _, err := rs.GetRecommendations(RecommendationFilter{
+		Limit:             10,
+		MinScore:          55,
+		ExcludeBrokerages: []string{"citi"},
+		Actions:           []string{"Upgrade", "Target Raised By"},
+		LookbackDays:      7,
+	})
+	if err != nil {
+		t.Fatalf("GetRecommendations error: %v", err)
+	}
+
+	if len(requestedSince) != 1 || !requestedSince[0].Equal(fixedNow.AddDate(0, 0, -7)) {
+		t.Fatalf("expected a single 7-day window query, got %v", requestedSince)
+	}
+	if len(recs) != 2 {
+		t.Fatalf("len(recs) = %d, want 2: %+v", len(recs), recs)
+	}
+	for _, rec := range recs {
+		if rec.Stock.Ticker != "AAA" && rec.Stock.Ticker != "CCC" {
+			t.Fatalf("unexpected ticker in filtered ranking: %s", rec.Stock.Ticker)
+		}
+	}
+
+	// Una acción parcial no coincide por subcadena
+	partial, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 10, Actions: []string{"raised"}, LookbackDays: 7})
+	if err != nil || len(partial) != 0 {
+		t.Fatalf("partial action must not match: len=%d err=%v", len(partial), err)
+	}
+
+	none, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 10, MinScore: 99.9})
+	if err != nil || len(none) != 0 {
+		t.Fatalf("explicit min_score must not fall back: len=%d err=%v", len(none), err)
+	}
+
+	high, _, err := rs.GetRecommendations(RecommendationFilter{Limit: 10, MinConfidence: models.ConfidenceHigh, Tickers: []string{"aaa"}})
+	if err != nil || len(high) != 0 {
+		t.Fatalf("single-record history cannot reach high confidence: len=%d err=%v", len(high), err)
+	}
+}
+
+func TestGetRecommendations_ReportsModelMetadata(t *testing.T) {
+	fixedNow := time.Date(2026, 2, 13, 12, 0, 0, 0, time.UTC)
+	call := 0
+	repo := &recoRepo{
+		findSinceFn: func(_ time.Time) ([]models.Stock, error) {
+			call++
+			if call == 1 {
+				return nil, nil
+			}
+			return []models.Stock{
+				{Ticker: "AAA", TargetFrom: "$100", TargetTo: "$130", Action: "Target raised", RatingTo: "Buy", Time: fixedNow.AddDate(0, 0, -40)},
+				{Ticker: "AAA", TargetFrom: "$90", TargetTo: "$100", Action: "Upgraded", RatingTo: "Hold", Time: fixedNow.AddDate(0, 0, -50)},
+				{Ticker: "BBB", TargetFrom: "$100", TargetTo: "$80", Action: "Downgraded", RatingTo: "Sell", Time: fixedNow.AddDate(0, 0, -60)},
+			}, nil
+		},
+	}
+
+	rs := NewRecommendationServiceWithReliability(repo, fixedWeigher{})
+	rs.now = func() time.Time { return fixedNow }
+
+	_, model, err := rs.GetRecommendations(RecommendationFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("GetRecommendations error: %v", err)
+	}
+
+	if model.LookbackDays != 90 || !model.WindowStart.Equal(fixedNow.AddDate(0, 0, -90)) {
+		t.Fatalf("expected widened 90-day window, got %d since %v", model.LookbackDays, model.WindowStart)
+	}
+	if model.RecordsEvaluated != 3 || model.TickersEvaluated != 2 {
+		t.Fatalf("unexpected evaluated counts: records=%d tickers=%d", model.RecordsEvaluated, model.TickersEvaluated)
+	}
+	if model.Name == "" || model.Version == "" || !model.BrokerageWeighting {
+		t.Fatalf("unexpected model identity: %+v", model)
+	}
+
+	total := 0.0
+	for _, weight := range model.Weights {
+		total += weight.Weight
+	}
+	if len(model.Weights) != 8 || total < 0.999 || total > 1.001 {
+		t.Fatalf("expected 8 weights summing to 1, got %d summing to %v", len(model.Weights), total)
+	}
+	if model.Thresholds.MinimumAcceptedScore != minimumAcceptedScore || len(model.Thresholds.Confidence) != 2 {
+		t.Fatalf("unexpected thresholds: %+v", model.Thresholds)
+	}
+}
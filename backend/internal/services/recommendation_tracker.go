@@ -0,0 +1,139 @@
+package services
+
+import (
+	"log"
+	"sync"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+// recommendationRanker calcula el ranking de recomendaciones (RecommendationService)
+type recommendationRanker interface {
+	GetRecommendations(filter RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error)
+}
+
+// RecommendationTracker recuerda el último top de recomendaciones para detectar
+// qué tickers entraron, salieron o cambiaron de confianza entre sincronizaciones
+type RecommendationTracker struct {
+	ranker recommendationRanker
+	top    int
+	events EventPublisher
+
+	mu      sync.Mutex
+	ranking []models.StockRecommendation
+	loaded  bool
+}
+
+// NewRecommendationTracker crea un tracker sobre las primeras top recomendaciones
+func NewRecommendationTracker(ranker recommendationRanker, top int) *RecommendationTracker {
+	return &RecommendationTracker{ranker: ranker, top: top}
+}
+
+// SetEventPublisher publica cada cambio detectado como recommendation.changed (por ejemplo en el EventBus)
+func (t *RecommendationTracker) SetEventPublisher(publisher EventPublisher) {
+	t.events = publisher
+}
+
+// Load toma el ranking actual como referencia sin reportar cambios
+func (t *RecommendationTracker) Load() error {
+	ranking, _, err := t.ranker.GetRecommendations(RecommendationFilter{Limit: t.top})
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ranking = ranking
+	t.loaded = true
+	return nil
+}
+
+// Changes recalcula el ranking, lo compara con el anterior y lo guarda como nueva referencia.
+// Si todavía no había referencia (Load falló o no se llamó) solo la guarda
+func (t *RecommendationTracker) Changes() ([]models.RecommendationChange, error) {
+	ranking, _, err := t.ranker.GetRecommendations(RecommendationFilter{Limit: t.top})
+	if err != nil {
+		return nil, err
+	}
+
+	t.mu.Lock()
+	previous, loaded := t.ranking, t.loaded
+	t.ranking = ranking
+	t.loaded = true
+	t.mu.Unlock()
+	if !loaded {
+		return nil, nil
+	}
+
+	changes := diffRecommendations(previous, ranking)
+	if t.events != nil {
+		for _, change := range changes {
+			t.events.Publish(models.Event{Type: models.EventRecommendationChanged, Ticker: change.Ticker, Data: change})
+		}
+	}
+	return changes, nil
+}
+
+// AfterSyncResult implementa SyncResultListener: si la sincronización creó o modificó registros
+// recalcula el ranking y publica sus cambios
+func (t *RecommendationTracker) AfterSyncResult(result SyncResult) {
+	if len(result.Created)+len(result.Updated) == 0 {
+		return
+	}
+	if _, err := t.Changes(); err != nil {
+		log.Printf("⚠️  Failed to compare recommendations: %v", err)
+	}
+}
+
+// diffRecommendations lista primero los tickers del ranking nuevo, en su orden, y después los que salieron
+func diffRecommendations(previous, current []models.StockRecommendation) []models.RecommendationChange {
+	previousRank := make(map[string]int, len(previous))
+	for i, rec := range previous {
+		previousRank[rec.Stock.Ticker] = i + 1
+	}
+	currentRank := make(map[string]int, len(current))
+	for i, rec := range current {
+		currentRank[rec.Stock.Ticker] = i + 1
+	}
+
+	changes := make([]models.RecommendationChange, 0)
+	for i, rec := range current {
+		confidence := rec.Confidence
+		rank, ok := previousRank[rec.Stock.Ticker]
+		if !ok {
+			changes = append(changes, models.RecommendationChange{
+				Ticker:     rec.Stock.Ticker,
+				Change:     models.RecommendationEntered,
+				Rank:       i + 1,
+				Score:      rec.Score,
+				Confidence: &confidence,
+			})
+			continue
+		}
+		if before := previous[rank-1].Confidence; before != confidence {
+			changes = append(changes, models.RecommendationChange{
+				Ticker:             rec.Stock.Ticker,
+				Change:             models.RecommendationConfidenceChanged,
+				Rank:               i + 1,
+				PreviousRank:       rank,
+				Score:              rec.Score,
+				Confidence:         &confidence,
+				PreviousConfidence: &before,
+			})
+		}
+	}
+	for i, rec := range previous {
+		if _, ok := currentRank[rec.Stock.Ticker]; ok {
+			continue
+		}
+		before := rec.Confidence
+		changes = append(changes, models.RecommendationChange{
+			Ticker:             rec.Stock.Ticker,
+			Change:             models.RecommendationLeft,
+			PreviousRank:       i + 1,
+			Score:              rec.Score,
+			PreviousConfidence: &before,
+		})
+	}
+	return changes
+}
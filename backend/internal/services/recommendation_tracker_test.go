@@ -0,0 +1,77 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+func TestRecommendationTracker_ReportsEnteredLeftAndConfidence(t *testing.T) {
+	ranker := &fakeRanker{rankings: [][]models.StockRecommendation{
+		{recommendation("AAPL", 9, models.ConfidenceHigh), recommendation("MSFT", 7, models.ConfidenceMedium), recommendation("TSLA", 5, models.ConfidenceLow)},
+		{recommendation("NVDA", 9.5, models.ConfidenceHigh), recommendation("AAPL", 9, models.ConfidenceHigh), recommendation("MSFT", 6, models.ConfidenceLow)},
+	}}
+	tracker := NewRecommendationTracker(ranker, 3)
+	bus := NewEventBus(10)
+	tracker.SetEventPublisher(bus)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+
+	// Sin referencia previa el primer cálculo solo se guarda
+	if changes, err := tracker.Changes(); err != nil || len(changes) != 0 {
+		t.Fatalf("first Changes = %+v, %v; want none", changes, err)
+	}
+
+	changes, err := tracker.Changes()
+	if err != nil {
+		t.Fatalf("Changes error: %v", err)
+	}
+	if len(changes) != 3 {
+		t.Fatalf("got %d changes, want 3: %+v", len(changes), changes)
+	}
+
+	entered, confidence, left := changes[0], changes[1], changes[2]
+	if entered.Ticker != "NVDA" || entered.Change != models.RecommendationEntered || entered.Rank != 1 || entered.PreviousRank != 0 {
+		t.Fatalf("unexpected entered change: %+v", entered)
+	}
+	if confidence.Ticker != "MSFT" || confidence.Change != models.RecommendationConfidenceChanged || confidence.Rank != 3 || confidence.PreviousRank != 2 ||
+		*confidence.Confidence != models.ConfidenceLow || *confidence.PreviousConfidence != models.ConfidenceMedium {
+		t.Fatalf("unexpected confidence change: %+v", confidence)
+	}
+	if left.Ticker != "TSLA" || left.Change != models.RecommendationLeft || left.Rank != 0 || left.PreviousRank != 3 || left.Confidence != nil {
+		t.Fatalf("unexpected left change: %+v", left)
+	}
+
+	for _, want := range []string{"NVDA", "MSFT", "TSLA"} {
+		event := <-sub.Events()
+		if event.Type != models.EventRecommendationChanged || event.Ticker != want {
+			t.Fatalf("expected recommendation.changed for %s, got %+v", want, event)
+		}
+	}
+}
+
+func TestRecommendationTracker_AfterSyncResultPublishesChanges(t *testing.T) {
+	ranker := &fakeRanker{rankings: [][]models.StockRecommendation{
+		{recommendation("AAPL", 9, models.ConfidenceHigh)},
+		{recommendation("NVDA", 9.5, models.ConfidenceHigh), recommendation("AAPL", 9, models.ConfidenceHigh)},
+	}}
+	tracker := NewRecommendationTracker(ranker, 3)
+	bus := NewEventBus(10)
+	tracker.SetEventPublisher(bus)
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+
+	// Una sincronización sin cambios no recalcula el ranking
+	tracker.AfterSyncResult(SyncResult{})
+	if ranker.calls != 1 {
+		t.Fatalf("ranker calls = %d, want 1", ranker.calls)
+	}
+
+	tracker.AfterSyncResult(SyncResult{Updated: []models.Stock{{ID: 1, Ticker: "NVDA"}}})
+	if event := <-sub.Events(); event.Type != models.EventRecommendationChanged || event.Ticker != "NVDA" {
+		t.Fatalf("expected recommendation.changed for NVDA, got %+v", event)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// ErrInvalidCursor se devuelve cuando un cursor no se puede decodificar
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type sortKeyKind int
+
+const (
+	sortKeyString sortKeyKind = iota
+	sortKeyTime
+	sortKeyNumber
+	sortKeyID
+)
+
+// stockSortKeys define cómo se serializa y se vuelve a tipar la clave de orden de cada campo
+var stockSortKeys = map[string]struct {
+	kind  sortKeyKind
+	value func(models.Stock) string
+}{
+	"id":                {kind: sortKeyID, value: func(s models.Stock) string { return strconv.FormatUint(s.ID, 10) }},
+	"ticker":            {kind: sortKeyString, value: func(s models.Stock) string { return s.Ticker }},
+	"target_from":       {kind: sortKeyString, value: func(s models.Stock) string { return s.TargetFrom }},
+	"target_to":         {kind: sortKeyString, value: func(s models.Stock) string { return s.TargetTo }},
+	"company":           {kind: sortKeyString, value: func(s models.Stock) string { return s.Company }},
+	"action":            {kind: sortKeyString, value: func(s models.Stock) string { return s.Action }},
+	"brokerage":         {kind: sortKeyString, value: func(s models.Stock) string { return s.Brokerage }},
+	"rating_from":       {kind: sortKeyString, value: func(s models.Stock) string { return s.RatingFrom }},
+	"rating_to":         {kind: sortKeyString, value: func(s models.Stock) string { return s.RatingTo }},
+	"target_currency":   {kind: sortKeyString, value: func(s models.Stock) string { return s.TargetCurrency }},
+	"time":              {kind: sortKeyTime, value: func(s models.Stock) string { return timeKey(s.Time) }},
+	"created_at":        {kind: sortKeyTime, value: func(s models.Stock) string { return timeKey(s.CreatedAt) }},
+	"updated_at":        {kind: sortKeyTime, value: func(s models.Stock) string { return timeKey(s.UpdatedAt) }},
+	"target_from_value": {kind: sortKeyNumber, value: func(s models.Stock) string { return numberKey(s.TargetFromValue) }},
+	"target_to_value":   {kind: sortKeyNumber, value: func(s models.Stock) string { return numberKey(s.TargetToValue) }},
+	"target_change_pct": {kind: sortKeyNumber, value: func(s models.Stock) string { return numberKey(s.TargetChangePct) }},
+}
+
+// StockPage es una página de un listado paginado por cursor
+type StockPage struct {
+	Data       []models.Stock
+	SortField  string
+	Order      string
+	NextCursor string
+	PrevCursor string
+}
+
+// stockCursor es el contenido del cursor opaco que viaja al cliente
+type stockCursor struct {
+	Field    string `json:"f"`
+	Order    string `json:"o"`
+	Key      string `json:"k"`
+	ID       uint64 `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// GetStocksPage lista stocks con paginación por cursor sobre (campo de orden, id).
+// Si se recibe un cursor, su campo y orden prevalecen sobre sortBy/order.
+func (s *StockService) GetStocksPage(filter repositories.StockFilter, limit int, sortBy, order, cursor string) (*StockPage, error) {
+	request := repositories.StockPageRequest{
+		SortField: sortBy,
+		Desc:      normalizeSortOrder(order),
+		Limit:     limit + 1,
+	}
+
+	if cursor != "" {
+		decoded, err := decodeStockCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		key, err := typedSortKey(decoded.Field, decoded.Key)
+		if err != nil {
+			return nil, err
+		}
+		request.SortField = decoded.Field
+		request.Desc = decoded.Order == "desc"
+		request.Backward = decoded.Backward
+		request.Cursor = &repositories.StockCursor{Key: key, ID: decoded.ID}
+	}
+	if _, ok := stockSortKeys[request.SortField]; !ok {
+		return nil, fmt.Errorf("%w: unsupported sort field %q", ErrInvalidCursor, request.SortField)
+	}
+
+	rows, err := s.repo.ListPage(filter, request)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(rows) > limit
+	if hasMore {
+		rows = rows[:limit]
+	}
+	if request.Backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	page := &StockPage{Data: rows, SortField: request.SortField, Order: "asc"}
+	if request.Desc {
+		page.Order = "desc"
+	}
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	first, last := rows[0], rows[len(rows)-1]
+	if request.Backward {
+		// Volvimos desde una página posterior, así que siempre hay una siguiente
+		page.NextCursor = encodeStockCursor(page.SortField, page.Order, last, false)
+		if hasMore {
+			page.PrevCursor = encodeStockCursor(page.SortField, page.Order, first, true)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = encodeStockCursor(page.SortField, page.Order, last, false)
+		}
+		if request.Cursor != nil {
+			page.PrevCursor = encodeStockCursor(page.SortField, page.Order, first, true)
+		}
+	}
+	return page, nil
+}
+
+func encodeStockCursor(field, order string, stock models.Stock, backward bool) string {
+	payload, _ := json.Marshal(stockCursor{
+		Field:    field,
+		Order:    order,
+		Key:      stockSortKeys[field].value(stock),
+		ID:       stock.ID,
+		Backward: backward,
+	})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodeStockCursor(raw string) (stockCursor, error) {
+	var cursor stockCursor
+	payload, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, fmt.Errorf("%w: malformed encoding", ErrInvalidCursor)
+	}
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return cursor, fmt.Errorf("%w: malformed payload", ErrInvalidCursor)
+	}
+	if cursor.Order != "asc" && cursor.Order != "desc" {
+		return cursor, fmt.Errorf("%w: unknown order", ErrInvalidCursor)
+	}
+	return cursor, nil
+}
+
+// typedSortKey convierte la clave serializada al tipo de la columna para la comparación en SQL
+func typedSortKey(field, key string) (any, error) {
+	spec, ok := stockSortKeys[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported sort field %q", ErrInvalidCursor, field)
+	}
+
+	switch spec.kind {
+	case sortKeyTime:
+		value, err := time.Parse(time.RFC3339Nano, key)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad time key", ErrInvalidCursor)
+		}
+		return value, nil
+	case sortKeyNumber:
+		value, err := strconv.ParseFloat(key, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad numeric key", ErrInvalidCursor)
+		}
+		return value, nil
+	case sortKeyID:
+		value, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: bad id key", ErrInvalidCursor)
+		}
+		return value, nil
+	default:
+		return key, nil
+	}
+}
+
+func timeKey(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func numberKey(value *float64) string {
+	if value == nil {
+		return strconv.FormatFloat(repositories.NullSortKey, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// keysetRepo simula ListPage en memoria ordenando por (time, id)
+func keysetRepo(stocks []models.Stock) *fakeRepo {
+	return &fakeRepo{
+		listPageFn: func(_ repositories.StockFilter, page repositories.StockPageRequest) ([]models.Stock, error) {
+			desc := page.Desc != page.Backward
+			rows := append([]models.Stock(nil), stocks...)
+			sort.Slice(rows, func(i, j int) bool {
+				if !rows[i].Time.Equal(rows[j].Time) {
+					return rows[i].Time.Before(rows[j].Time) != desc
+				}
+				return (rows[i].ID < rows[j].ID) != desc
+			})
+
+			out := make([]models.Stock, 0, page.Limit)
+			for _, row := range rows {
+				if page.Cursor != nil {
+					key := page.Cursor.Key.(time.Time)
+					after := row.Time.After(key) || (row.Time.Equal(key) && row.ID > page.Cursor.ID)
+					if after == desc {
+						continue
+					}
+					if row.Time.Equal(key) && row.ID == page.Cursor.ID {
+						continue
+					}
+				}
+				out = append(out, row)
+				if len(out) == page.Limit {
+					break
+				}
+			}
+			return out, nil
+		},
+	}
+}
+
+func pageIDs(page *StockPage) []uint64 {
+	ids := make([]uint64, 0, len(page.Data))
+	for _, stock := range page.Data {
+		ids = append(ids, stock.ID)
+	}
+	return ids
+}
+
+func TestGetStocksPage_ForwardAndBackward(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	stocks := []models.Stock{
+		{ID: 1, Time: base},
+		{ID: 2, Time: base.Add(time.Hour)},
+		{ID: 3, Time: base.Add(time.Hour)},
+		{ID: 4, Time: base.Add(2 * time.Hour)},
+		{ID: 5, Time: base.Add(3 * time.Hour)},
+	}
+	svc := NewStockService(nil, keysetRepo(stocks))
+
+	first, err := svc.GetStocksPage(repositories.StockFilter{}, 2, "time", "desc", "")
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if got := pageIDs(first); len(got) != 2 || got[0] != 5 || got[1] != 4 {
+		t.Fatalf("first page ids = %v", got)
+	}
+	if first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("first page cursors next=%q prev=%q", first.NextCursor, first.PrevCursor)
+	}
+
+	second, err := svc.GetStocksPage(repositories.StockFilter{}, 2, "ticker", "asc", first.NextCursor)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if got := pageIDs(second); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Fatalf("second page ids = %v", got)
+	}
+	if second.SortField != "time" || second.Order != "desc" {
+		t.Fatalf("cursor sort must win, got %s %s", second.SortField, second.Order)
+	}
+
+	last, err := svc.GetStocksPage(repositories.StockFilter{}, 2, "", "", second.NextCursor)
+	if err != nil {
+		t.Fatalf("last page: %v", err)
+	}
+	if got := pageIDs(last); len(got) != 1 || got[0] != 1 || last.NextCursor != "" {
+		t.Fatalf("last page ids = %v next=%q", got, last.NextCursor)
+	}
+
+	back, err := svc.GetStocksPage(repositories.StockFilter{}, 2, "", "", last.PrevCursor)
+	if err != nil {
+		t.Fatalf("backward page: %v", err)
+	}
+	if got := pageIDs(back); len(got) != 2 || got[0] != 3 || got[1] != 2 {
+		t.Fatalf("backward page ids = %v", got)
+	}
+	if back.NextCursor == "" || back.PrevCursor == "" {
+		t.Fatalf("backward page cursors next=%q prev=%q", back.NextCursor, back.PrevCursor)
+	}
+}
+
+func TestGetStocksPage_InvalidCursor(t *testing.T) {
+	svc := NewStockService(nil, keysetRepo(nil))
+
+	for _, cursor := range []string{"not-base64!", "bm90LWpzb24", encodeStockCursor("time", "sideways", models.Stock{}, false)} {
+		if _, err := svc.GetStocksPage(repositories.StockFilter{}, 10, "time", "desc", cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+
+	if _, err := svc.GetStocksPage(repositories.StockFilter{}, 10, "rating_from_score", "desc", ""); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected ErrInvalidCursor for unsupported sort field, got %v", err)
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+// Similitud mínima por trigramas para aceptar una coincidencia difusa
+const (
+	companyFuzzyThreshold   = 0.3
+	brokerageFuzzyThreshold = 0.4
+)
+
+type searchMatch struct {
+	score     float64
+	matchType string
+	highlight models.SearchHighlight
+}
+
+// SearchStocks busca por prefijo de ticker, por compañía (subcadena o similitud por trigramas,
+// tolerante a errores de tipeo) y por brokerage. Devuelve un resultado por ticker ordenado por
+// relevancia, con la cantidad de registros y el más reciente.
+func (s *StockService) SearchStocks(query string, limit int) ([]models.SearchHit, error) {
+	needle := strings.ToLower(strings.TrimSpace(query))
+	if needle == "" {
+		return []models.SearchHit{}, nil
+	}
+
+	candidates, err := s.searchCandidateSet()
+	if err != nil {
+		return nil, err
+	}
+
+	byTicker := make(map[string]*models.SearchHit)
+	for _, candidate := range candidates {
+		hit, ok := byTicker[candidate.Ticker]
+		if !ok {
+			hit = &models.SearchHit{Ticker: candidate.Ticker, Company: candidate.Company}
+			byTicker[candidate.Ticker] = hit
+		}
+		hit.Records += candidate.Count
+
+		match, ok := matchSearchCandidate(needle, candidate)
+		if ok && match.score > hit.Score {
+			hit.Score = math.Round(match.score*1000) / 1000
+			hit.MatchType = match.matchType
+			hit.Highlights = []models.SearchHighlight{match.highlight}
+		}
+	}
+
+	hits := make([]models.SearchHit, 0)
+	for _, hit := range byTicker {
+		if hit.MatchType != "" {
+			hits = append(hits, *hit)
+		}
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Records != hits[j].Records {
+			return hits[i].Records > hits[j].Records
+		}
+		return hits[i].Ticker < hits[j].Ticker
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	if len(hits) == 0 {
+		return hits, nil
+	}
+
+	tickers := make([]string, len(hits))
+	for i, hit := range hits {
+		tickers[i] = hit.Ticker
+	}
+	latest, err := s.repo.LatestByTickers(tickers)
+	if err != nil {
+		return nil, err
+	}
+	latestByTicker := make(map[string]models.Stock, len(latest))
+	for _, stock := range latest {
+		latestByTicker[stock.Ticker] = stock
+	}
+	for i := range hits {
+		if stock, ok := latestByTicker[hits[i].Ticker]; ok {
+			hits[i].Latest = stock
+			hits[i].LatestTime = stock.Time
+			hits[i].Company = stock.Company
+		}
+	}
+
+	return hits, nil
+}
+
+// RefreshSearchCandidates recarga desde la base de datos el conjunto de candidatos de la búsqueda
+func (s *StockService) RefreshSearchCandidates() error {
+	candidates, err := s.repo.SearchCandidates()
+	if err != nil {
+		return err
+	}
+
+	s.searchMu.Lock()
+	s.searchCandidates = candidates
+	s.searchLoaded = true
+	s.searchMu.Unlock()
+	return nil
+}
+
+// searchCandidateSet devuelve los candidatos en memoria y los carga la primera vez que se necesitan
+func (s *StockService) searchCandidateSet() ([]models.SearchCandidate, error) {
+	s.searchMu.RLock()
+	candidates, loaded := s.searchCandidates, s.searchLoaded
+	s.searchMu.RUnlock()
+	if loaded {
+		return candidates, nil
+	}
+
+	if err := s.RefreshSearchCandidates(); err != nil {
+		return nil, err
+	}
+	s.searchMu.RLock()
+	defer s.searchMu.RUnlock()
+	return s.searchCandidates, nil
+}
+
+// matchSearchCandidate devuelve la mejor coincidencia del texto buscado en un candidato.
+// Los puntajes por tipo no se solapan, salvo los difusos que escalan con la similitud.
+func matchSearchCandidate(needle string, candidate models.SearchCandidate) (searchMatch, bool) {
+	ticker := strings.ToLower(candidate.Ticker)
+	tickerRunes := len([]rune(ticker))
+	needleRunes := len([]rune(needle))
+
+	if ticker == needle {
+		return searchMatch{
+			score:     1,
+			matchType: models.SearchMatchTickerExact,
+			highlight: models.SearchHighlight{Field: "ticker", Value: candidate.Ticker, Start: 0, End: tickerRunes},
+		}, true
+	}
+	if strings.HasPrefix(ticker, needle) {
+		return searchMatch{
+			score:     0.9 + 0.05*float64(needleRunes)/float64(tickerRunes),
+			matchType: models.SearchMatchTickerPrefix,
+			highlight: models.SearchHighlight{Field: "ticker", Value: candidate.Ticker, Start: 0, End: needleRunes},
+		}, true
+	}
+
+	if start, ok := runeIndexFold(candidate.Company, needle); ok {
+		score := 0.7
+		if start == 0 {
+			score = 0.8
+		} else if isWordStart(candidate.Company, start) {
+			score = 0.75
+		}
+		return searchMatch{
+			score:     score,
+			matchType: models.SearchMatchCompany,
+			highlight: models.SearchHighlight{Field: "company", Value: candidate.Company, Start: start, End: start + needleRunes},
+		}, true
+	}
+	if similarity, start, end := bestTrigramSpan(needle, candidate.Company); similarity >= companyFuzzyThreshold {
+		return searchMatch{
+			score:     0.6 * similarity,
+			matchType: models.SearchMatchCompanyFuzzy,
+			highlight: models.SearchHighlight{Field: "company", Value: candidate.Company, Start: start, End: end},
+		}, true
+	}
+
+	if start, ok := runeIndexFold(candidate.Brokerage, needle); ok {
+		return searchMatch{
+			score:     0.5,
+			matchType: models.SearchMatchBrokerage,
+			highlight: models.SearchHighlight{Field: "brokerage", Value: candidate.Brokerage, Start: start, End: start + needleRunes},
+		}, true
+	}
+	if similarity, start, end := bestTrigramSpan(needle, candidate.Brokerage); similarity >= brokerageFuzzyThreshold {
+		return searchMatch{
+			score:     0.4 * similarity,
+			matchType: models.SearchMatchBrokerageFuzzy,
+			highlight: models.SearchHighlight{Field: "brokerage", Value: candidate.Brokerage, Start: start, End: end},
+		}, true
+	}
+
+	return searchMatch{}, false
+}
+
+// runeIndexFold busca needle (ya en minúsculas) en value sin distinguir mayúsculas y devuelve la posición en runas
+func runeIndexFold(value, needle string) (int, bool) {
+	haystack := []rune(value)
+	target := []rune(needle)
+	for i := range haystack {
+		haystack[i] = unicode.ToLower(haystack[i])
+	}
+	for i := 0; i+len(target) <= len(haystack); i++ {
+		if string(haystack[i:i+len(target)]) == needle {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func isWordStart(value string, index int) bool {
+	runes := []rune(value)
+	return index == 0 || !isWordRune(runes[index-1])
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type wordSpan struct {
+	text       string
+	start, end int
+}
+
+// wordSpans separa value en palabras alfanuméricas con su posición en runas
+func wordSpans(value string) []wordSpan {
+	runes := []rune(value)
+	spans := make([]wordSpan, 0)
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(runes) && isWordRune(runes[i]) {
+			i++
+		}
+		spans = append(spans, wordSpan{text: string(runes[start:i]), start: start, end: i})
+	}
+	return spans
+}
+
+// bestTrigramSpan compara needle con el texto completo y con cada palabra, y devuelve la mejor
+// similitud junto al tramo que la produjo
+func bestTrigramSpan(needle, value string) (float64, int, int) {
+	spans := wordSpans(value)
+	if len(spans) == 0 {
+		return 0, 0, 0
+	}
+
+	needleGrams := trigrams(needle)
+	best := trigramSimilarity(needleGrams, trigrams(value))
+	start, end := spans[0].start, spans[len(spans)-1].end
+	for _, span := range spans {
+		if similarity := trigramSimilarity(needleGrams, trigrams(span.text)); similarity > best {
+			best, start, end = similarity, span.start, span.end
+		}
+	}
+	return best, start, end
+}
+
+// trigrams genera los trigramas de cada palabra al estilo pg_trgm (dos espacios al inicio y uno al final)
+func trigrams(value string) map[string]struct{} {
+	grams := make(map[string]struct{})
+	for _, span := range wordSpans(strings.ToLower(value)) {
+		padded := []rune("  " + span.text + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return grams
+}
+
+func trigramSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,165 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func searchRepo() *fakeRepo {
+	return &fakeRepo{
+		searchCandidatesFn: func() ([]models.SearchCandidate, error) {
+			return []models.SearchCandidate{
+				{Ticker: "GOOGL", Company: "Alphabet Inc.", Brokerage: "Goldman Sachs", Count: 3},
+				{Ticker: "GOOGL", Company: "Alphabet Inc.", Brokerage: "Morgan Stanley", Count: 2},
+				{Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Morgan Stanley", Count: 4},
+				{Ticker: "AA", Company: "Alcoa Corporation", Brokerage: "Citigroup", Count: 1},
+				{Ticker: "APLE", Company: "Apple Hospitality REIT", Brokerage: "Raymond James", Count: 1},
+				{Ticker: "MSFT", Company: "Microsoft Corporation", Brokerage: "Goldman Sachs", Count: 2},
+			}, nil
+		},
+		latestByTickersFn: func(tickers []string) ([]models.Stock, error) {
+			stocks := make([]models.Stock, 0, len(tickers))
+			for i, ticker := range tickers {
+				stocks = append(stocks, models.Stock{ID: uint64(i + 1), Ticker: ticker, Company: ticker + " latest", Time: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)})
+			}
+			return stocks, nil
+		},
+	}
+}
+
+func hitTickers(hits []models.SearchHit) []string {
+	tickers := make([]string, len(hits))
+	for i, hit := range hits {
+		tickers[i] = hit.Ticker
+	}
+	return tickers
+}
+
+func TestSearchStocks_FuzzyCompanyGroupedByTicker(t *testing.T) {
+	svc := NewStockService(nil, searchRepo())
+
+	hits, err := svc.SearchStocks("alphabt", 10)
+	if err != nil {
+		t.Fatalf("SearchStocks error: %v", err)
+	}
+	if len(hits) != 1 || hits[0].Ticker != "GOOGL" {
+		t.Fatalf("expected GOOGL only, got %v", hitTickers(hits))
+	}
+
+	hit := hits[0]
+	if hit.MatchType != models.SearchMatchCompanyFuzzy || hit.Records != 5 {
+		t.Fatalf("unexpected hit: %+v", hit)
+	}
+	if hit.Latest.Ticker != "GOOGL" || hit.LatestTime.IsZero() || hit.Company != "GOOGL latest" {
+		t.Fatalf("latest record not attached: %+v", hit)
+	}
+	if len(hit.Highlights) != 1 || hit.Highlights[0].Field != "company" || hit.Highlights[0].Start != 0 || hit.Highlights[0].End != 8 {
+		t.Fatalf("unexpected highlight: %+v", hit.Highlights)
+	}
+}
+
+func TestSearchStocks_TickerPrefixRankedFirst(t *testing.T) {
+	svc := NewStockService(nil, searchRepo())
+
+	hits, err := svc.SearchStocks("AP", 10)
+	if err != nil {
+		t.Fatalf("SearchStocks error: %v", err)
+	}
+	got := hitTickers(hits)
+	if len(got) < 2 || got[0] != "APLE" || hits[0].MatchType != models.SearchMatchTickerPrefix {
+		t.Fatalf("expected APLE first by ticker prefix, got %v", got)
+	}
+	for _, hit := range hits[1:] {
+		if hit.Score >= hits[0].Score {
+			t.Fatalf("results must be sorted by score: %+v", hits)
+		}
+	}
+
+	exact, err := svc.SearchStocks("aa", 10)
+	if err != nil || len(exact) == 0 || exact[0].Ticker != "AA" || exact[0].MatchType != models.SearchMatchTickerExact {
+		t.Fatalf("expected exact ticker AA first, got %v err=%v", hitTickers(exact), err)
+	}
+	if exact[1].Ticker != "AAPL" {
+		t.Fatalf("expected AAPL prefix right after exact match, got %v", hitTickers(exact))
+	}
+}
+
+func TestSearchStocks_CompanyAndBrokerage(t *testing.T) {
+	svc := NewStockService(nil, searchRepo())
+
+	hits, err := svc.SearchStocks("corporation", 10)
+	if err != nil || len(hits) != 2 {
+		t.Fatalf("expected two company matches, got %v err=%v", hitTickers(hits), err)
+	}
+	if hits[0].Ticker != "MSFT" || hits[0].Highlights[0].Start != 10 || hits[0].Highlights[0].End != 21 {
+		t.Fatalf("unexpected company ranking/highlight: %+v", hits[0])
+	}
+
+	brokerage, err := svc.SearchStocks("goldman", 1)
+	if err != nil || len(brokerage) != 1 {
+		t.Fatalf("expected limit to apply, got %v err=%v", hitTickers(brokerage), err)
+	}
+	if brokerage[0].MatchType != models.SearchMatchBrokerage || brokerage[0].Highlights[0].Value != "Goldman Sachs" {
+		t.Fatalf("unexpected brokerage hit: %+v", brokerage[0])
+	}
+
+	fuzzyBroker, err := svc.SearchStocks("raymnd james", 10)
+	if err != nil || len(fuzzyBroker) != 1 || fuzzyBroker[0].Ticker != "APLE" || fuzzyBroker[0].MatchType != models.SearchMatchBrokerageFuzzy {
+		t.Fatalf("expected fuzzy brokerage match on APLE, got %+v err=%v", fuzzyBroker, err)
+	}
+
+	none, err := svc.SearchStocks("zzzz", 10)
+	if err != nil || len(none) != 0 {
+		t.Fatalf("expected no hits, got %v err=%v", hitTickers(none), err)
+	}
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	if got := trigramSimilarity(trigrams("alphabet"), trigrams("alphabet")); got != 1 {
+		t.Fatalf("identical words should score 1, got %v", got)
+	}
+	typo := trigramSimilarity(trigrams("alphabt"), trigrams("alphabet"))
+	unrelated := trigramSimilarity(trigrams("alphabt"), trigrams("microsoft"))
+	if typo < companyFuzzyThreshold || unrelated >= companyFuzzyThreshold {
+		t.Fatalf("typo=%v unrelated=%v", typo, unrelated)
+	}
+}
+
+func TestSearchStocks_CandidatesCachedUntilSyncChangesData(t *testing.T) {
+	repo := searchRepo()
+	loads := 0
+	candidates := repo.searchCandidatesFn
+	repo.searchCandidatesFn = func() ([]models.SearchCandidate, error) {
+		loads++
+		return candidates()
+	}
+	repo.getByTickerAndTimeFn = func(string, time.Time) (*models.Stock, error) {
+		return nil, repositories.ErrNotFound
+	}
+	fetcher := &fakeFetcher{}
+	svc := NewStockService(fetcher, repo)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.SearchStocks("goog", 10); err != nil {
+			t.Fatalf("SearchStocks error: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected candidates loaded once, got %d", loads)
+	}
+
+	// Una sincronización sin cambios no reconstruye el conjunto; una con altas sí
+	if _, _, err := svc.SyncStocksFromAPI(); err != nil || loads != 1 {
+		t.Fatalf("empty sync should keep candidates, loads=%d err=%v", loads, err)
+	}
+	fetcher.stocks = []models.Stock{{Ticker: "NVDA", Time: time.Now(), Action: "upgraded by", Brokerage: "UBS"}}
+	if _, _, err := svc.SyncStocksFromAPI(); err != nil || loads != 2 {
+		t.Fatalf("sync with changes should rebuild candidates, loads=%d err=%v", loads, err)
+	}
+	if _, err := svc.SearchStocks("goog", 10); err != nil || loads != 2 {
+		t.Fatalf("search after sync should use rebuilt candidates, loads=%d err=%v", loads, err)
+	}
+}
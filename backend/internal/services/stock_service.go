@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Hitomiblood/StockStream/internal/models"
@@ -13,20 +14,88 @@ import (
 type StockService struct {
 	repo      repositories.StockRepository
 	apiClient StockFetcher
+	enrichers []StockEnricher
+	listeners []SyncListener
+	reporters []SyncResultListener
+	events    EventPublisher
+
+	// Candidatos de búsqueda agregados por (ticker, compañía, brokerage); se reconstruyen tras cada sync con cambios
+	searchMu         sync.RWMutex
+	searchCandidates []models.SearchCandidate
+	searchLoaded     bool
 }
 
 type StockFetcher interface {
 	FetchAllStocks() ([]models.Stock, error)
 }
 
-// NewStockService crea una nueva instancia del servicio de stocks
-func NewStockService(apiClient StockFetcher, repo repositories.StockRepository) *StockService {
+// StockEnricher completa campos derivados de un stock antes de persistirlo
+type StockEnricher interface {
+	Enrich(stock *models.Stock)
+}
+
+// SyncListener recibe los stocks creados o modificados al terminar cada sincronización
+type SyncListener interface {
+	AfterSync(changed []models.Stock)
+}
+
+// SyncResult resume una sincronización separando los stocks creados de los modificados
+type SyncResult struct {
+	Created    []models.Stock
+	Updated    []models.Stock
+	StartedAt  time.Time
+	FinishedAt time.Time
+}
+
+// SyncResultListener recibe el resumen de cada sincronización, después de los SyncListener
+type SyncResultListener interface {
+	AfterSyncResult(result SyncResult)
+}
+
+// EventPublisher recibe los eventos que emite la sincronización mientras procesa los registros
+type EventPublisher interface {
+	Publish(event models.Event)
+}
+
+// syncProgressInterval es cada cuántos registros procesados se publica sync.progress
+const syncProgressInterval = 100
+
+// Summary resume la sincronización con sus totales y su duración
+func (r SyncResult) Summary() models.SyncSummary {
+	return models.SyncSummary{
+		Created:    len(r.Created),
+		Updated:    len(r.Updated),
+		StartedAt:  r.StartedAt,
+		FinishedAt: r.FinishedAt,
+		DurationMs: r.FinishedAt.Sub(r.StartedAt).Milliseconds(),
+	}
+}
+
+// NewStockService crea una nueva instancia del servicio de stocks.
+// Los enrichers se aplican, en orden, a cada stock sincronizado antes de guardarlo.
+func NewStockService(apiClient StockFetcher, repo repositories.StockRepository, enrichers ...StockEnricher) *StockService {
 	return &StockService{
 		repo:      repo,
 		apiClient: apiClient,
+		enrichers: enrichers,
 	}
 }
 
+// AddSyncListener registra un listener que se invoca al final de cada sincronización
+func (s *StockService) AddSyncListener(listener SyncListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// AddSyncResultListener registra un listener que recibe el resumen de cada sincronización
+func (s *StockService) AddSyncResultListener(listener SyncResultListener) {
+	s.reporters = append(s.reporters, listener)
+}
+
+// SetEventPublisher fija el destino de los eventos de sincronización (por ejemplo un EventBus)
+func (s *StockService) SetEventPublisher(publisher EventPublisher) {
+	s.events = publisher
+}
+
 // SyncStocksFromAPI sincroniza los datos desde la API externa a la base de datos
 func (s *StockService) SyncStocksFromAPI() (int, int, error) {
 	startTime := time.Now()
@@ -35,14 +104,26 @@ func (s *StockService) SyncStocksFromAPI() (int, int, error) {
 	// Obtener todos los stocks de la API
 	stocks, err := s.apiClient.FetchAllStocks()
 	if err != nil {
+		s.publish(models.EventSyncFailed, nil, models.SyncFailure{Error: err.Error()})
 		return 0, 0, fmt.Errorf("failed to fetch stocks: %w", err)
 	}
+	s.publish(models.EventSyncStarted, nil, models.SyncProgress{Total: len(stocks)})
 
 	totalNew := 0
 	totalUpdated := 0
+	changed := make([]models.Stock, 0)
+	result := SyncResult{StartedAt: startTime}
 
 	// Procesar cada stock
-	for _, stock := range stocks {
+	for i, stock := range stocks {
+		if i > 0 && i%syncProgressInterval == 0 {
+			s.publish(models.EventSyncProgress, nil, models.SyncProgress{Processed: i, Total: len(stocks), Created: totalNew, Updated: totalUpdated})
+		}
+
+		for _, enricher := range s.enrichers {
+			enricher.Enrich(&stock)
+		}
+
 		// Verificar si ya existe (por ticker y time)
 		existing, err := s.repo.GetByTickerAndTime(stock.Ticker, stock.Time)
 		if err != nil && err != repositories.ErrNotFound {
@@ -58,15 +139,27 @@ func (s *StockService) SyncStocksFromAPI() (int, int, error) {
 			}
 			log.Printf("🆕 Created new stock: ID=%d, Ticker=%s", stock.ID, stock.Ticker)
 			totalNew++
+			changed = append(changed, stock)
+			result.Created = append(result.Created, stock)
+			s.publish(models.EventStockCreated, &stock, stock)
 		} else if existing != nil {
-			// Ya existe, actualizar si hay cambios
+			stock.ID = existing.ID // Mantener el ID
 			if s.hasChanges(existing, &stock) {
-				stock.ID = existing.ID // Mantener el ID
+				// Ya existe y cambió en la fuente: actualizar
 				if err := s.repo.Save(&stock); err != nil {
 					log.Printf("⚠️  Error updating stock %s: %v", stock.Ticker, err)
 					continue
 				}
 				totalUpdated++
+				changed = append(changed, stock)
+				result.Updated = append(result.Updated, stock)
+				s.publish(models.EventStockUpdated, &stock, stock)
+			} else if hasDerivedChanges(existing, &stock) {
+				// Solo difieren las columnas calculadas al ingerir (registro anterior a una migración o
+				// regla de rating nueva): se recalculan sin contarlo como actualización ni notificarlo
+				if err := s.repo.UpdateDerivedColumns(&stock); err != nil {
+					log.Printf("⚠️  Error refreshing derived columns of stock %s: %v", stock.Ticker, err)
+				}
 			}
 		}
 	}
@@ -74,10 +167,37 @@ func (s *StockService) SyncStocksFromAPI() (int, int, error) {
 	duration := time.Since(startTime)
 	log.Printf("✅ Sync completed: %d new, %d updated in %v", totalNew, totalUpdated, duration)
 
+	if len(changed) > 0 {
+		if err := s.RefreshSearchCandidates(); err != nil {
+			log.Printf("⚠️  Failed to refresh search candidates: %v", err)
+		}
+	}
+	for _, listener := range s.listeners {
+		listener.AfterSync(changed)
+	}
+	result.FinishedAt = startTime.Add(duration)
+	s.publish(models.EventSyncCompleted, nil, result.Summary())
+	for _, reporter := range s.reporters {
+		reporter.AfterSyncResult(result)
+	}
+
 	return totalNew, totalUpdated, nil
 }
 
-// hasChanges verifica si hay diferencias entre dos stocks
+// publish envía un evento al publisher configurado; stock identifica el registro en los eventos de stocks
+func (s *StockService) publish(eventType models.EventType, stock *models.Stock, data any) {
+	if s.events == nil {
+		return
+	}
+	event := models.Event{Type: eventType, Data: data}
+	if stock != nil {
+		event.Ticker = stock.Ticker
+		event.Brokerage = stock.Brokerage
+	}
+	s.events.Publish(event)
+}
+
+// hasChanges verifica si cambió algún campo que viene de la API externa
 func (s *StockService) hasChanges(old, new *models.Stock) bool {
 	return old.TargetFrom != new.TargetFrom ||
 		old.TargetTo != new.TargetTo ||
@@ -87,17 +207,37 @@ func (s *StockService) hasChanges(old, new *models.Stock) bool {
 		old.Brokerage != new.Brokerage
 }
 
-// GetAllStocks obtiene todos los stocks con paginación
-func (s *StockService) GetAllStocks(limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
+// hasDerivedChanges verifica si difieren las columnas que calculan los enrichers
+func hasDerivedChanges(old, new *models.Stock) bool {
+	return old.RatingFromCanonical != new.RatingFromCanonical ||
+		old.RatingToCanonical != new.RatingToCanonical ||
+		old.RatingFromScore != new.RatingFromScore ||
+		old.RatingToScore != new.RatingToScore ||
+		old.EventType != new.EventType ||
+		old.TargetCurrency != new.TargetCurrency ||
+		!equalFloatPtr(old.TargetFromValue, new.TargetFromValue) ||
+		!equalFloatPtr(old.TargetToValue, new.TargetToValue) ||
+		!equalFloatPtr(old.TargetChangePct, new.TargetChangePct)
+}
+
+func equalFloatPtr(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// GetAllStocks obtiene los stocks que cumplen el filtro con paginación
+func (s *StockService) GetAllStocks(filter repositories.StockFilter, limit, offset int, sortBy, order string) ([]models.Stock, int64, error) {
 	// Contar total
-	total, err := s.repo.CountAll()
+	total, err := s.repo.Count(filter)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	desc := normalizeSortOrder(order)
 
-	stocks, err := s.repo.List(limit, offset, sortBy, desc)
+	stocks, err := s.repo.List(filter, limit, offset, sortBy, desc)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -120,14 +260,9 @@ func (s *StockService) GetStocksByTicker(ticker string) ([]models.Stock, error)
 	return s.repo.FindByTicker(ticker)
 }
 
-// SearchStocks busca stocks por ticker o nombre de compañía
-func (s *StockService) SearchStocks(query string, limit int) ([]models.Stock, error) {
-	return s.repo.Search(query, limit)
-}
-
-// FilterStocks filtra stocks por acción y/o rating
-func (s *StockService) FilterStocks(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
-	return s.repo.Filter(action, rating, limit, offset)
+// FilterStocks filtra stocks por acción, tipo de evento, rating crudo y/o rating canónico
+func (s *StockService) FilterStocks(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+	return s.repo.Filter(filter, limit, offset)
 }
 
 // GetUniqueActions obtiene todas las acciones únicas disponibles
@@ -140,6 +275,21 @@ func (s *StockService) GetUniqueRatings() ([]string, error) {
 	return s.repo.DistinctRatings()
 }
 
+// GetUniqueCanonicalRatings obtiene los ratings normalizados presentes en la base de datos
+func (s *StockService) GetUniqueCanonicalRatings() ([]string, error) {
+	return s.repo.DistinctCanonicalRatings()
+}
+
+// GetUniqueBrokerages obtiene todas las casas de bolsa con llamadas registradas
+func (s *StockService) GetUniqueBrokerages() ([]string, error) {
+	return s.repo.DistinctBrokerages()
+}
+
+// GetEventTypeCounts obtiene cuántos registros hay de cada tipo de evento
+func (s *StockService) GetEventTypeCounts() ([]models.EventTypeCount, error) {
+	return s.repo.CountByEventType()
+}
+
 // GetLatestStocks obtiene los últimos N stocks añadidos
 func (s *StockService) GetLatestStocks(limit int) ([]models.Stock, error) {
 	return s.repo.Latest(limit)
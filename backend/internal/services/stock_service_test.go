@@ -25,14 +25,26 @@ type fakeRepo struct {
 	getByTickerAndTimeFn func(ticker string, at time.Time) (*models.Stock, error)
 	createFn             func(stock *models.Stock) error
 	saveFn               func(stock *models.Stock) error
-	countAllFn           func() (int64, error)
-	listFn               func(limit, offset int, sortField string, desc bool) ([]models.Stock, error)
+	updateDerivedFn      func(stock *models.Stock) error
+	countFn              func(filter repositories.StockFilter) (int64, error)
+	listPageFn           func(filter repositories.StockFilter, page repositories.StockPageRequest) ([]models.Stock, error)
+	listFn               func(filter repositories.StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error)
 	findByIDFn           func(id uint64) (*models.Stock, error)
 	findByTickerFn       func(ticker string) ([]models.Stock, error)
-	searchFn             func(query string, limit int) ([]models.Stock, error)
-	filterFn             func(action, rating string, limit, offset int) ([]models.Stock, int64, error)
+	findByTickersFn      func(tickers []string) ([]models.Stock, error)
+	searchCandidatesFn   func() ([]models.SearchCandidate, error)
+	latestByTickersFn    func(tickers []string) ([]models.Stock, error)
+	tickerActivityFn     func() ([]models.TickerSuggestion, error)
+	filterFn             func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error)
 	distinctActionsFn    func() ([]string, error)
 	distinctRatingsFn    func() ([]string, error)
+	distinctCanonicalFn  func() ([]string, error)
+	distinctBrokeragesFn func() ([]string, error)
+	brokerageActivityFn  func() ([]models.BrokerageSummary, error)
+	sentimentSeriesFn    func(filter repositories.StockFilter, bucket models.TimelineBucket) ([]models.SentimentPoint, error)
+	ratingTransitionsFn  func(filter repositories.StockFilter, mode models.RatingMode) ([]models.RatingTransition, error)
+	ratingDistributionFn func(mode models.RatingMode) ([]models.RatingShare, error)
+	countByEventTypeFn   func() ([]models.EventTypeCount, error)
 	latestFn             func(limit int) ([]models.Stock, error)
 	findSinceFn          func(since time.Time) ([]models.Stock, error)
 }
@@ -49,21 +61,33 @@ func (f *fakeRepo) Create(stock *models.Stock) error {
 	}
 	return nil
 }
+func (f *fakeRepo) UpdateDerivedColumns(stock *models.Stock) error {
+	if f.updateDerivedFn != nil {
+		return f.updateDerivedFn(stock)
+	}
+	return nil
+}
 func (f *fakeRepo) Save(stock *models.Stock) error {
 	if f.saveFn != nil {
 		return f.saveFn(stock)
 	}
 	return nil
 }
-func (f *fakeRepo) CountAll() (int64, error) {
-	if f.countAllFn != nil {
-		return f.countAllFn()
+func (f *fakeRepo) Count(filter repositories.StockFilter) (int64, error) {
+	if f.countFn != nil {
+		return f.countFn(filter)
 	}
 	return 0, nil
 }
-func (f *fakeRepo) List(limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
+func (f *fakeRepo) List(filter repositories.StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
 	if f.listFn != nil {
-		return f.listFn(limit, offset, sortField, desc)
+		return f.listFn(filter, limit, offset, sortField, desc)
+	}
+	return nil, nil
+}
+func (f *fakeRepo) ListPage(filter repositories.StockFilter, page repositories.StockPageRequest) ([]models.Stock, error) {
+	if f.listPageFn != nil {
+		return f.listPageFn(filter, page)
 	}
 	return nil, nil
 }
@@ -79,15 +103,63 @@ func (f *fakeRepo) FindByTicker(ticker string) ([]models.Stock, error) {
 	}
 	return nil, nil
 }
-func (f *fakeRepo) Search(query string, limit int) ([]models.Stock, error) {
-	if f.searchFn != nil {
-		return f.searchFn(query, limit)
+func (f *fakeRepo) FindByTickers(tickers []string) ([]models.Stock, error) {
+	if f.findByTickersFn != nil {
+		return f.findByTickersFn(tickers)
+	}
+	return nil, nil
+}
+func (f *fakeRepo) SearchCandidates() ([]models.SearchCandidate, error) {
+	if f.searchCandidatesFn != nil {
+		return f.searchCandidatesFn()
+	}
+	return nil, nil
+}
+func (f *fakeRepo) DistinctBrokerages() ([]string, error) {
+	if f.distinctBrokeragesFn != nil {
+		return f.distinctBrokeragesFn()
+	}
+	return nil, nil
+}
+func (f *fakeRepo) BrokerageActivity() ([]models.BrokerageSummary, error) {
+	if f.brokerageActivityFn != nil {
+		return f.brokerageActivityFn()
+	}
+	return nil, nil
+}
+func (f *fakeRepo) SentimentSeries(filter repositories.StockFilter, bucket models.TimelineBucket) ([]models.SentimentPoint, error) {
+	if f.sentimentSeriesFn != nil {
+		return f.sentimentSeriesFn(filter, bucket)
+	}
+	return nil, nil
+}
+func (f *fakeRepo) RatingTransitions(filter repositories.StockFilter, mode models.RatingMode) ([]models.RatingTransition, error) {
+	if f.ratingTransitionsFn != nil {
+		return f.ratingTransitionsFn(filter, mode)
+	}
+	return nil, nil
+}
+func (f *fakeRepo) CurrentRatingDistribution(mode models.RatingMode) ([]models.RatingShare, error) {
+	if f.ratingDistributionFn != nil {
+		return f.ratingDistributionFn(mode)
+	}
+	return nil, nil
+}
+func (f *fakeRepo) TickerActivity() ([]models.TickerSuggestion, error) {
+	if f.tickerActivityFn != nil {
+		return f.tickerActivityFn()
+	}
+	return nil, nil
+}
+func (f *fakeRepo) LatestByTickers(tickers []string) ([]models.Stock, error) {
+	if f.latestByTickersFn != nil {
+		return f.latestByTickersFn(tickers)
 	}
 	return nil, nil
 }
-func (f *fakeRepo) Filter(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
+func (f *fakeRepo) Filter(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
 	if f.filterFn != nil {
-		return f.filterFn(action, rating, limit, offset)
+		return f.filterFn(filter, limit, offset)
 	}
 	return nil, 0, nil
 }
@@ -103,6 +175,18 @@ func (f *fakeRepo) DistinctRatings() ([]string, error) {
 	}
 	return nil, nil
 }
+func (f *fakeRepo) DistinctCanonicalRatings() ([]string, error) {
+	if f.distinctCanonicalFn != nil {
+		return f.distinctCanonicalFn()
+	}
+	return nil, nil
+}
+func (f *fakeRepo) CountByEventType() ([]models.EventTypeCount, error) {
+	if f.countByEventTypeFn != nil {
+		return f.countByEventTypeFn()
+	}
+	return nil, nil
+}
 func (f *fakeRepo) Latest(limit int) ([]models.Stock, error) {
 	if f.latestFn != nil {
 		return f.latestFn(limit)
@@ -167,6 +251,9 @@ func TestSyncStocksFromAPI_CreateAndUpdate(t *testing.T) {
 	}
 
 	svc := NewStockService(&fakeFetcher{stocks: incoming}, repo)
+	listener := &recordingListener{}
+	svc.AddSyncListener(listener)
+	svc.AddSyncResultListener(listener)
 	newCount, updatedCount, err := svc.SyncStocksFromAPI()
 	if err != nil {
 		t.Fatalf("SyncStocksFromAPI error: %v", err)
@@ -178,20 +265,155 @@ func TestSyncStocksFromAPI_CreateAndUpdate(t *testing.T) {
 	if creates != 1 || saves != 1 {
 		t.Fatalf("repo ops got create=%d save=%d, want 1/1", creates, saves)
 	}
+	if listener.calls != 1 || len(listener.changed) != 2 || listener.changed[1].ID != 55 {
+		t.Fatalf("listener got calls=%d changed=%+v", listener.calls, listener.changed)
+	}
+	result := listener.result
+	if len(result.Created) != 1 || result.Created[0].Ticker != "AAPL" || len(result.Updated) != 1 || result.Updated[0].ID != 55 {
+		t.Fatalf("sync result got created=%+v updated=%+v", result.Created, result.Updated)
+	}
+	if result.FinishedAt.Before(result.StartedAt) {
+		t.Fatalf("sync result finished before it started: %+v", result)
+	}
+}
+
+type recordingListener struct {
+	calls   int
+	changed []models.Stock
+	result  SyncResult
+}
+
+func (l *recordingListener) AfterSync(changed []models.Stock) {
+	l.calls++
+	l.changed = changed
+}
+
+func (l *recordingListener) AfterSyncResult(result SyncResult) {
+	l.result = result
+}
+
+func TestSyncStocksFromAPI_RefreshesDerivedColumnsWithoutCountingUpdates(t *testing.T) {
+	now := time.Now()
+	incoming := []models.Stock{
+		{Ticker: "AAPL", Time: now, Action: "Upgrade", RatingFrom: "Hold", RatingTo: "Strong-Buy", Brokerage: "X"},
+	}
+
+	var refreshed *models.Stock
+	saves := 0
+	repo := &fakeRepo{
+		getByTickerAndTimeFn: func(string, time.Time) (*models.Stock, error) {
+			// Registro previo a la migración: mismos datos crudos, sin columnas normalizadas
+			return &models.Stock{ID: 9, Ticker: "AAPL", Time: now, Action: "Upgrade", RatingFrom: "Hold", RatingTo: "Strong-Buy", Brokerage: "X"}, nil
+		},
+		saveFn: func(*models.Stock) error {
+			saves++
+			return nil
+		},
+		updateDerivedFn: func(stock *models.Stock) error {
+			refreshed = stock
+			return nil
+		},
+	}
+
+	svc := NewStockService(&fakeFetcher{stocks: incoming}, repo, NewRatingNormalizer(&fakeRatingRepo{}))
+	listener := &recordingListener{}
+	svc.AddSyncListener(listener)
+	bus := NewEventBus(10)
+	svc.SetEventPublisher(bus)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+
+	_, updated, err := svc.SyncStocksFromAPI()
+	if err != nil {
+		t.Fatalf("SyncStocksFromAPI error: %v", err)
+	}
+	if updated != 0 || saves != 0 || len(listener.changed) != 0 {
+		t.Fatalf("derived-only difference counted as update: updated=%d saves=%d changed=%+v", updated, saves, listener.changed)
+	}
+	if refreshed == nil || refreshed.ID != 9 || refreshed.RatingToCanonical != models.RatingStrongBuy || refreshed.RatingFromCanonical != models.RatingHold {
+		t.Fatalf("normalized columns not refreshed: %+v", refreshed)
+	}
+	for _, want := range []models.EventType{models.EventSyncStarted, models.EventSyncCompleted} {
+		if event := <-sub.Events(); event.Type != want {
+			t.Fatalf("expected %s, got %s", want, event.Type)
+		}
+	}
 }
 
 func TestSyncStocksFromAPI_FetchError(t *testing.T) {
 	svc := NewStockService(&fakeFetcher{err: errors.New("boom")}, &fakeRepo{})
+	bus := NewEventBus(10)
+	svc.SetEventPublisher(bus)
+	sub, _, _ := bus.Subscribe(0)
+	defer sub.Close()
+
 	_, _, err := svc.SyncStocksFromAPI()
 	if err == nil {
 		t.Fatalf("expected error, got nil")
 	}
+	if event := <-sub.Events(); event.Type != models.EventSyncFailed || event.Data.(models.SyncFailure).Error != "boom" {
+		t.Fatalf("expected sync.failed event, got %+v", event)
+	}
+}
+
+func TestSyncStocksFromAPI_PublishesProgressEvents(t *testing.T) {
+	now := time.Now()
+	incoming := make([]models.Stock, 0, syncProgressInterval+1)
+	for i := 0; i <= syncProgressInterval; i++ {
+		incoming = append(incoming, models.Stock{Ticker: "AAPL", Brokerage: "UBS", Time: now.Add(time.Duration(i) * time.Minute)})
+	}
+	incoming[syncProgressInterval].Ticker = "MSFT"
+
+	repo := &fakeRepo{
+		getByTickerAndTimeFn: func(ticker string, at time.Time) (*models.Stock, error) {
+			if ticker == "MSFT" {
+				return &models.Stock{ID: 9, Ticker: "MSFT", Time: at, Brokerage: "Old"}, nil
+			}
+			return nil, repositories.ErrNotFound
+		},
+		createFn: func(*models.Stock) error { return nil },
+		saveFn:   func(*models.Stock) error { return nil },
+	}
+	svc := NewStockService(&fakeFetcher{stocks: incoming}, repo)
+	bus := NewEventBus(500)
+	svc.SetEventPublisher(bus)
+
+	if _, _, err := svc.SyncStocksFromAPI(); err != nil {
+		t.Fatalf("SyncStocksFromAPI error: %v", err)
+	}
+
+	// Cualquier id anterior al primero devuelve todo el buffer
+	sub, events, _ := bus.Subscribe(1)
+	defer sub.Close()
+	counts := map[models.EventType]int{}
+	for _, event := range events {
+		counts[event.Type]++
+	}
+	if events[0].Type != models.EventSyncStarted || events[0].Data.(models.SyncProgress).Total != syncProgressInterval+1 {
+		t.Fatalf("first event should be sync.started, got %+v", events[0])
+	}
+	if counts[models.EventStockCreated] != syncProgressInterval || counts[models.EventStockUpdated] != 1 || counts[models.EventSyncProgress] != 1 {
+		t.Fatalf("unexpected event counts: %v", counts)
+	}
+
+	progress := events[syncProgressInterval+1]
+	if progress.Type != models.EventSyncProgress || progress.Data.(models.SyncProgress).Processed != syncProgressInterval {
+		t.Fatalf("expected sync.progress after %d records, got %+v", syncProgressInterval, progress)
+	}
+	updated := events[len(events)-2]
+	if updated.Type != models.EventStockUpdated || updated.Ticker != "MSFT" || updated.Brokerage != "UBS" {
+		t.Fatalf("unexpected stock.updated event: %+v", updated)
+	}
+	completed := events[len(events)-1]
+	if summary := completed.Data.(models.SyncSummary); completed.Type != models.EventSyncCompleted || summary.Created != syncProgressInterval || summary.Updated != 1 {
+		t.Fatalf("unexpected sync.completed event: %+v", completed)
+	}
 }
 
 func TestGetAllStocks_UsesSortNormalization(t *testing.T) {
 	repo := &fakeRepo{
-		countAllFn: func() (int64, error) { return 2, nil },
-		listFn: func(limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
+		countFn: func(repositories.StockFilter) (int64, error) { return 2, nil },
+		listFn: func(_ repositories.StockFilter, limit, offset int, sortField string, desc bool) ([]models.Stock, error) {
 			if limit != 10 || offset != 5 || sortField != "time" || !desc {
 				t.Fatalf("unexpected args limit=%d offset=%d sort=%s desc=%v", limit, offset, sortField, desc)
 			}
@@ -200,7 +422,7 @@ func TestGetAllStocks_UsesSortNormalization(t *testing.T) {
 	}
 
 	svc := NewStockService(&fakeFetcher{}, repo)
-	stocks, total, err := svc.GetAllStocks(10, 5, "time", "unexpected")
+	stocks, total, err := svc.GetAllStocks(repositories.StockFilter{}, 10, 5, "time", "unexpected")
 	if err != nil {
 		t.Fatalf("GetAllStocks error: %v", err)
 	}
@@ -213,9 +435,11 @@ func TestStockService_WrapperMethods(t *testing.T) {
 	repo := &fakeRepo{
 		findByIDFn:     func(id uint64) (*models.Stock, error) { return &models.Stock{ID: id, Ticker: "AAPL"}, nil },
 		findByTickerFn: func(ticker string) ([]models.Stock, error) { return []models.Stock{{Ticker: ticker}}, nil },
-		searchFn:       func(query string, limit int) ([]models.Stock, error) { return []models.Stock{{Ticker: query}}, nil },
-		filterFn: func(action, rating string, limit, offset int) ([]models.Stock, int64, error) {
-			return []models.Stock{{Action: action, RatingTo: rating}}, 1, nil
+		searchCandidatesFn: func() ([]models.SearchCandidate, error) {
+			return []models.SearchCandidate{{Ticker: "AAPL", Company: "Apple Inc.", Count: 1}}, nil
+		},
+		filterFn: func(filter repositories.StockFilter, limit, offset int) ([]models.Stock, int64, error) {
+			return []models.Stock{{Action: filter.Action, RatingTo: filter.Rating}}, 1, nil
 		},
 		distinctActionsFn: func() ([]string, error) { return []string{"Upgrade"}, nil },
 		distinctRatingsFn: func() ([]string, error) { return []string{"Buy"}, nil },
@@ -233,7 +457,7 @@ func TestStockService_WrapperMethods(t *testing.T) {
 	if stocks, err := svc.SearchStocks("AAP", 10); err != nil || len(stocks) != 1 {
 		t.Fatalf("SearchStocks failed: len=%d err=%v", len(stocks), err)
 	}
-	if stocks, total, err := svc.FilterStocks("Upgrade", "Buy", 10, 0); err != nil || total != 1 || len(stocks) != 1 {
+	if stocks, total, err := svc.FilterStocks(repositories.StockFilter{Action: "Upgrade", Rating: "Buy"}, 10, 0); err != nil || total != 1 || len(stocks) != 1 {
 		t.Fatalf("FilterStocks failed: total=%d len=%d err=%v", total, len(stocks), err)
 	}
 	if actions, err := svc.GetUniqueActions(); err != nil || len(actions) != 1 {
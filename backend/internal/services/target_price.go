@@ -0,0 +1,160 @@
+package services
+
+import (
+	"log"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+const targetBackfillBatchSize = 500
+
+// currencySymbols se evalúa en orden: los prefijos compuestos van antes que "$"
+var currencySymbols = []struct {
+	token string
+	code  string
+}{
+	{token: "US$", code: "USD"},
+	{token: "CA$", code: "CAD"},
+	{token: "AU$", code: "AUD"},
+	{token: "HK$", code: "HKD"},
+	{token: "C$", code: "CAD"},
+	{token: "A$", code: "AUD"},
+	{token: "R$", code: "BRL"},
+	{token: "€", code: "EUR"},
+	{token: "£", code: "GBP"},
+	{token: "¥", code: "JPY"},
+	{token: "$", code: "USD"},
+}
+
+var currencyCodes = []string{"USD", "EUR", "GBP", "CAD", "AUD", "JPY", "CHF", "HKD", "BRL", "SEK", "NOK", "DKK"}
+
+// TargetPriceParser convierte los precios objetivo en texto en columnas numéricas con su moneda
+type TargetPriceParser struct{}
+
+// NewTargetPriceParser crea una nueva instancia del parser de precios objetivo
+func NewTargetPriceParser() *TargetPriceParser {
+	return &TargetPriceParser{}
+}
+
+// Enrich implementa StockEnricher rellenando valores, moneda y variación porcentual
+func (p *TargetPriceParser) Enrich(stock *models.Stock) {
+	stock.TargetFromValue, stock.TargetToValue, stock.TargetCurrency, stock.TargetChangePct = parseTargetPair(stock.TargetFrom, stock.TargetTo)
+}
+
+// Backfill recorre por lotes los registros sin valores numéricos y los completa.
+// Devuelve cuántos registros se actualizaron.
+func (p *TargetPriceParser) Backfill(repo repositories.StockTargetRepository) (int, error) {
+	updated := 0
+	var afterID uint64
+	for {
+		batch, err := repo.FindTargetBackfillBatch(afterID, targetBackfillBatchSize)
+		if err != nil {
+			return updated, err
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		for i := range batch {
+			stock := batch[i]
+			afterID = stock.ID
+			p.Enrich(&stock)
+			if stock.TargetFromValue == nil && stock.TargetToValue == nil {
+				continue
+			}
+			if err := repo.UpdateTargetValues(&stock); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+		log.Printf("🔄 Target backfill: %d records updated (last id %d)", updated, afterID)
+	}
+	return updated, nil
+}
+
+// parseTargetPair interpreta ambos precios de un registro. La variación solo se calcula
+// cuando los dos precios existen y están en la misma moneda.
+func parseTargetPair(rawFrom, rawTo string) (from, to *float64, currency string, changePct *float64) {
+	fromValue, fromCurrency, fromOK := parseTargetPrice(rawFrom)
+	toValue, toCurrency, toOK := parseTargetPrice(rawTo)
+
+	if fromOK {
+		from = &fromValue
+		currency = fromCurrency
+	}
+	if toOK {
+		to = &toValue
+		currency = toCurrency
+	}
+
+	if fromOK && toOK && fromCurrency == toCurrency && fromValue > 0 {
+		pct := math.Round((toValue-fromValue)/fromValue*100*100) / 100
+		changePct = &pct
+	}
+	return from, to, currency, changePct
+}
+
+// parseTargetPrice extrae valor y código de moneda (ej: "$1,234.50" -> 1234.5, "USD").
+// Un número sin símbolo ni código devuelve moneda vacía en lugar de asumir USD.
+func parseTargetPrice(raw string) (float64, string, bool) {
+	clean := strings.ToUpper(strings.TrimSpace(raw))
+	if clean == "" {
+		return 0, "", false
+	}
+
+	currency := ""
+	for _, code := range currencyCodes {
+		if strings.Contains(clean, code) {
+			currency = code
+			clean = strings.Replace(clean, code, "", 1)
+			break
+		}
+	}
+	for _, symbol := range currencySymbols {
+		if strings.Contains(clean, symbol.token) {
+			if currency == "" {
+				currency = symbol.code
+			}
+			clean = strings.Replace(clean, symbol.token, "", 1)
+			break
+		}
+	}
+
+	clean = strings.ReplaceAll(clean, ",", "")
+	clean = strings.TrimSpace(clean)
+
+	// ParseFloat acepta "NaN" e "Inf": no son precios y romperían el JSON y los cálculos derivados
+	value, err := strconv.ParseFloat(clean, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+		return 0, "", false
+	}
+	return value, currency, true
+}
+
+// stockTargetCurrency devuelve la moneda del precio objetivo: la guardada al ingerir o, en
+// registros que aún no pasaron por el parser, la del texto
+func stockTargetCurrency(stock models.Stock) string {
+	if stock.TargetCurrency != "" {
+		return stock.TargetCurrency
+	}
+	_, currency, _ := parseTargetPrice(stock.TargetTo)
+	return currency
+}
+
+// stockTargetPrices usa los valores numéricos guardados al ingerir y solo re-parsea el texto
+// en registros que aún no pasaron por el parser
+func stockTargetPrices(stock models.Stock) (from, to float64) {
+	from = parsePrice(stock.TargetFrom)
+	if stock.TargetFromValue != nil {
+		from = *stock.TargetFromValue
+	}
+	to = parsePrice(stock.TargetTo)
+	if stock.TargetToValue != nil {
+		to = *stock.TargetToValue
+	}
+	return from, to
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+func TestParseTargetPrice(t *testing.T) {
+	tests := []struct {
+		raw      string
+		value    float64
+		currency string
+		ok       bool
+	}{
+		{raw: "$150.00", value: 150, currency: "USD", ok: true},
+		{raw: "$1,234.50", value: 1234.5, currency: "USD", ok: true},
+		{raw: "1,234.50 USD", value: 1234.5, currency: "USD", ok: true},
+		{raw: "€95", value: 95, currency: "EUR", ok: true},
+		{raw: "£12.40", value: 12.4, currency: "GBP", ok: true},
+		{raw: "C$45.00", value: 45, currency: "CAD", ok: true},
+		{raw: "CA$45.00", value: 45, currency: "CAD", ok: true},
+		{raw: "42", value: 42, currency: "", ok: true},
+		{raw: "N/A", ok: false},
+		{raw: "NaN", ok: false},
+		{raw: "$INF", ok: false},
+		{raw: "-Inf", ok: false},
+		{raw: "Infinity USD", ok: false},
+		{raw: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		value, currency, ok := parseTargetPrice(tt.raw)
+		if ok != tt.ok || value != tt.value || currency != tt.currency {
+			t.Fatalf("parseTargetPrice(%q) = %v,%q,%v want %v,%q,%v", tt.raw, value, currency, ok, tt.value, tt.currency, tt.ok)
+		}
+	}
+}
+
+func TestTargetPriceParser_Enrich(t *testing.T) {
+	stock := models.Stock{TargetFrom: "$100.00", TargetTo: "$112.50"}
+	NewTargetPriceParser().Enrich(&stock)
+
+	if stock.TargetFromValue == nil || *stock.TargetFromValue != 100 || stock.TargetToValue == nil || *stock.TargetToValue != 112.5 {
+		t.Fatalf("unexpected values: %+v", stock)
+	}
+	if stock.TargetCurrency != "USD" || stock.TargetChangePct == nil || *stock.TargetChangePct != 12.5 {
+		t.Fatalf("unexpected currency/change: %q %v", stock.TargetCurrency, stock.TargetChangePct)
+	}
+
+	mixed := models.Stock{TargetFrom: "€100", TargetTo: "$110"}
+	NewTargetPriceParser().Enrich(&mixed)
+	if mixed.TargetChangePct != nil {
+		t.Fatalf("expected no change percent across currencies, got %v", *mixed.TargetChangePct)
+	}
+}
+
+type fakeTargetRepo struct {
+	rows    []models.Stock
+	updates map[uint64]models.Stock
+}
+
+func (f *fakeTargetRepo) FindTargetBackfillBatch(afterID uint64, limit int) ([]models.Stock, error) {
+	var out []models.Stock
+	for _, row := range f.rows {
+		if row.ID > afterID && len(out) < limit {
+			out = append(out, row)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTargetRepo) UpdateTargetValues(stock *models.Stock) error {
+	f.updates[stock.ID] = *stock
+	return nil
+}
+
+func TestTargetPriceParser_BackfillSkipsUnparseable(t *testing.T) {
+	repo := &fakeTargetRepo{
+		rows: []models.Stock{
+			{ID: 1, TargetFrom: "$10", TargetTo: "$12"},
+			{ID: 2, TargetFrom: "n/a", TargetTo: "tbd"},
+			{ID: 3, TargetTo: "£8"},
+		},
+		updates: map[uint64]models.Stock{},
+	}
+
+	updated, err := NewTargetPriceParser().Backfill(repo)
+	if err != nil {
+		t.Fatalf("Backfill error: %v", err)
+	}
+	if updated != 2 || len(repo.updates) != 2 {
+		t.Fatalf("updated = %d, want 2", updated)
+	}
+	if repo.updates[3].TargetCurrency != "GBP" {
+		t.Fatalf("unexpected backfilled row: %+v", repo.updates[3])
+	}
+}
+
+func TestStockTargetPrices_PrefersNumericColumns(t *testing.T) {
+	stored := 200.0
+	from, to := stockTargetPrices(models.Stock{TargetFrom: "$100", TargetTo: "garbage", TargetToValue: &stored})
+	if from != 100 || to != 200 {
+		t.Fatalf("stockTargetPrices = %v,%v want 100,200", from, to)
+	}
+}
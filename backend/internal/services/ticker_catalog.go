@@ -0,0 +1,154 @@
+package services
+
+import (
+	"log"
+	"strings"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// TickerCatalog mantiene las tablas tickers/companies a partir del historial de stocks
+type TickerCatalog struct {
+	stocks repositories.StockRepository
+	repo   repositories.TickerRepository
+}
+
+// NewTickerCatalog crea una nueva instancia del catálogo de tickers
+func NewTickerCatalog(stocks repositories.StockRepository, repo repositories.TickerRepository) *TickerCatalog {
+	return &TickerCatalog{stocks: stocks, repo: repo}
+}
+
+// RefreshTickers recalcula la entidad de cada ticker desde su historial completo
+func (c *TickerCatalog) RefreshTickers(tickers []string) (int, error) {
+	refreshed := 0
+	for _, ticker := range tickers {
+		history, err := c.stocks.FindByTicker(ticker)
+		if err != nil {
+			return refreshed, err
+		}
+		if len(history) == 0 {
+			continue
+		}
+
+		entity, company := buildTickerEntity(ticker, history)
+		if err := c.repo.SaveTicker(&entity, company); err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// Rebuild recalcula todos los tickers presentes en stocks (para poblar la tabla tras la migración)
+func (c *TickerCatalog) Rebuild() (int, error) {
+	activity, err := c.stocks.TickerActivity()
+	if err != nil {
+		return 0, err
+	}
+
+	tickers := make([]string, len(activity))
+	for i, item := range activity {
+		tickers[i] = item.Ticker
+	}
+	return c.RefreshTickers(tickers)
+}
+
+// AfterSync implementa SyncListener actualizando solo los tickers que cambiaron
+func (c *TickerCatalog) AfterSync(changed []models.Stock) {
+	seen := make(map[string]struct{})
+	tickers := make([]string, 0)
+	for _, stock := range changed {
+		if _, ok := seen[stock.Ticker]; ok {
+			continue
+		}
+		seen[stock.Ticker] = struct{}{}
+		tickers = append(tickers, stock.Ticker)
+	}
+	if len(tickers) == 0 {
+		return
+	}
+
+	refreshed, err := c.RefreshTickers(tickers)
+	if err != nil {
+		log.Printf("⚠️  Failed to refresh ticker catalog after %d tickers: %v", refreshed, err)
+		return
+	}
+	log.Printf("✅ Ticker catalog refreshed: %d tickers", refreshed)
+}
+
+// ListTickers lista los tickers con paginación y orden
+func (c *TickerCatalog) ListTickers(limit, offset int, sortBy, order string) ([]models.Ticker, int64, error) {
+	return c.repo.ListTickers(limit, offset, sortBy, normalizeSortOrder(order))
+}
+
+// GetTicker obtiene la entidad de un ticker
+func (c *TickerCatalog) GetTicker(ticker string) (*models.Ticker, error) {
+	return c.repo.FindTicker(strings.ToUpper(strings.TrimSpace(ticker)))
+}
+
+// buildTickerEntity resume el historial de un ticker; devuelve nil como compañía si ningún registro trae nombre
+func buildTickerEntity(ticker string, history []models.Stock) (models.Ticker, *models.Company) {
+	entity := models.Ticker{Ticker: ticker, RecordCount: int64(len(history))}
+
+	latest := history[0]
+	for _, stock := range history {
+		if entity.FirstSeen.IsZero() || stock.Time.Before(entity.FirstSeen) {
+			entity.FirstSeen = stock.Time
+		}
+		if stock.Time.After(latest.Time) || (stock.Time.Equal(latest.Time) && stock.ID > latest.ID) {
+			latest = stock
+		}
+	}
+	entity.LastSeen = latest.Time
+	entity.LatestStockID = latest.ID
+	entity.LatestRating = latest.RatingTo
+	entity.LatestRatingCanonical = latest.RatingToCanonical
+	entity.LatestBrokerage = latest.Brokerage
+
+	name := CanonicalCompanyName(history)
+	if name == "" {
+		return entity, nil
+	}
+	return entity, &models.Company{Name: name, NormalizedName: normalizeText(name)}
+}
+
+// CanonicalCompanyName elige el nombre de compañía más frecuente del historial;
+// los empates se resuelven por el registro más reciente.
+func CanonicalCompanyName(history []models.Stock) string {
+	type variant struct {
+		name   string
+		count  int
+		latest models.Stock
+	}
+	variants := make(map[string]*variant)
+	var best *variant
+
+	for _, stock := range history {
+		name := strings.TrimSpace(stock.Company)
+		if name == "" {
+			continue
+		}
+		v, ok := variants[name]
+		if !ok {
+			v = &variant{name: name, latest: stock}
+			variants[name] = v
+		}
+		v.count++
+		if stock.Time.After(v.latest.Time) {
+			v.latest = stock
+		}
+	}
+
+	for _, v := range variants {
+		if best == nil || v.count > best.count ||
+			(v.count == best.count && v.latest.Time.After(best.latest.Time)) ||
+			(v.count == best.count && v.latest.Time.Equal(best.latest.Time) && v.name < best.name) {
+			best = v
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.name
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+type fakeTickerRepo struct {
+	saved     map[string]models.Ticker
+	companies map[string]string
+	saveErr   error
+}
+
+func (f *fakeTickerRepo) SaveTicker(ticker *models.Ticker, company *models.Company) error {
+	if f.saveErr != nil {
+		return f.saveErr
+	}
+	if f.saved == nil {
+		f.saved = map[string]models.Ticker{}
+		f.companies = map[string]string{}
+	}
+	f.saved[ticker.Ticker] = *ticker
+	if company != nil {
+		f.companies[ticker.Ticker] = company.Name
+	}
+	return nil
+}
+
+func (f *fakeTickerRepo) ListTickers(int, int, string, bool) ([]models.Ticker, int64, error) {
+	return nil, 0, nil
+}
+
+func (f *fakeTickerRepo) FindTicker(string) (*models.Ticker, error) {
+	return nil, nil
+}
+
+func TestCanonicalCompanyName(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	history := []models.Stock{
+		{Company: "Apple Inc", Time: base},
+		{Company: "Apple Inc.", Time: base.Add(time.Hour)},
+		{Company: "Apple Inc", Time: base.Add(2 * time.Hour)},
+		{Company: "  ", Time: base.Add(3 * time.Hour)},
+	}
+	if got := CanonicalCompanyName(history); got != "Apple Inc" {
+		t.Fatalf("most frequent name expected, got %q", got)
+	}
+
+	tie := []models.Stock{
+		{Company: "Alphabet Inc.", Time: base},
+		{Company: "Alphabet Inc Class A", Time: base.Add(time.Hour)},
+	}
+	if got := CanonicalCompanyName(tie); got != "Alphabet Inc Class A" {
+		t.Fatalf("ties should go to the most recent record, got %q", got)
+	}
+
+	if got := CanonicalCompanyName([]models.Stock{{Ticker: "X"}}); got != "" {
+		t.Fatalf("expected empty name, got %q", got)
+	}
+}
+
+func TestTickerCatalog_AfterSyncRefreshesChangedTickers(t *testing.T) {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	lookups := 0
+	stocks := &fakeRepo{
+		findByTickerFn: func(ticker string) ([]models.Stock, error) {
+			lookups++
+			if ticker != "AAPL" {
+				return nil, nil
+			}
+			return []models.Stock{
+				{ID: 3, Ticker: "AAPL", Company: "Apple Inc.", Time: base.Add(48 * time.Hour), RatingTo: "Buy", RatingToCanonical: models.RatingBuy, Brokerage: "Morgan Stanley"},
+				{ID: 2, Ticker: "AAPL", Company: "Apple Inc", Time: base.Add(24 * time.Hour), RatingTo: "Hold"},
+				{ID: 1, Ticker: "AAPL", Company: "Apple Inc.", Time: base, RatingTo: "Hold"},
+			}, nil
+		},
+	}
+	repo := &fakeTickerRepo{}
+	catalog := NewTickerCatalog(stocks, repo)
+
+	catalog.AfterSync([]models.Stock{{Ticker: "AAPL"}, {Ticker: "AAPL"}, {Ticker: "GONE"}})
+	if lookups != 2 {
+		t.Fatalf("each changed ticker should be loaded once, got %d lookups", lookups)
+	}
+
+	entity, ok := repo.saved["AAPL"]
+	if !ok || len(repo.saved) != 1 {
+		t.Fatalf("expected only AAPL to be saved, got %+v", repo.saved)
+	}
+	if entity.RecordCount != 3 || !entity.FirstSeen.Equal(base) || !entity.LastSeen.Equal(base.Add(48*time.Hour)) {
+		t.Fatalf("unexpected counters: %+v", entity)
+	}
+	if entity.LatestStockID != 3 || entity.LatestRating != "Buy" || entity.LatestRatingCanonical != models.RatingBuy || entity.LatestBrokerage != "Morgan Stanley" {
+		t.Fatalf("unexpected latest rating: %+v", entity)
+	}
+	if repo.companies["AAPL"] != "Apple Inc." {
+		t.Fatalf("unexpected canonical company: %q", repo.companies["AAPL"])
+	}
+}
+
+func TestTickerCatalog_RebuildStopsOnError(t *testing.T) {
+	stocks := &fakeRepo{
+		tickerActivityFn: func() ([]models.TickerSuggestion, error) {
+			return []models.TickerSuggestion{{Ticker: "AAPL"}, {Ticker: "MSFT"}}, nil
+		},
+		findByTickerFn: func(ticker string) ([]models.Stock, error) {
+			return []models.Stock{{ID: 1, Ticker: ticker, Company: ticker + " Inc."}}, nil
+		},
+	}
+	catalog := NewTickerCatalog(stocks, &fakeTickerRepo{saveErr: errors.New("db down")})
+
+	refreshed, err := catalog.Rebuild()
+	if err == nil || refreshed != 0 {
+		t.Fatalf("expected save error, got refreshed=%d err=%v", refreshed, err)
+	}
+
+	ok := &fakeTickerRepo{}
+	refreshed, err = NewTickerCatalog(stocks, ok).Rebuild()
+	if err != nil || refreshed != 2 || ok.companies["MSFT"] != "MSFT Inc." {
+		t.Fatalf("Rebuild refreshed=%d err=%v companies=%v", refreshed, err, ok.companies)
+	}
+}
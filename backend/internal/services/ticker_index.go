@@ -0,0 +1,164 @@
+package services
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// Prioridad de coincidencia del autocompletado (menor es mejor)
+const (
+	suggestTickerExact = iota
+	suggestTickerPrefix
+	suggestCompanyPrefix
+	suggestCompanyWord
+)
+
+var suggestMatchNames = map[int]string{
+	suggestTickerExact:   "ticker_exact",
+	suggestTickerPrefix:  "ticker_prefix",
+	suggestCompanyPrefix: "company_prefix",
+	suggestCompanyWord:   "company_word",
+}
+
+type tickerIndexEntry struct {
+	item         models.TickerSuggestion
+	ticker       string
+	company      string
+	companyWords []string
+}
+
+// TickerIndex mantiene en memoria los pares ticker/compañía para el autocompletado.
+// Se reconstruye completo al cargar y después de cada sincronización.
+type TickerIndex struct {
+	repo repositories.StockRepository
+
+	mu        sync.RWMutex
+	entries   []tickerIndexEntry // ordenadas por ticker en minúsculas
+	refreshed time.Time
+}
+
+// NewTickerIndex crea un índice vacío; llamar a Refresh para cargarlo
+func NewTickerIndex(repo repositories.StockRepository) *TickerIndex {
+	return &TickerIndex{repo: repo}
+}
+
+// Refresh reconstruye el índice desde la base de datos
+func (idx *TickerIndex) Refresh() error {
+	items, err := idx.repo.TickerActivity()
+	if err != nil {
+		return err
+	}
+
+	entries := make([]tickerIndexEntry, 0, len(items))
+	for _, item := range items {
+		company := strings.ToLower(item.Company)
+		entries = append(entries, tickerIndexEntry{
+			item:         item,
+			ticker:       strings.ToLower(item.Ticker),
+			company:      company,
+			companyWords: strings.FieldsFunc(company, func(r rune) bool { return !isWordRune(r) }),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ticker < entries[j].ticker })
+
+	idx.mu.Lock()
+	idx.entries = entries
+	idx.refreshed = time.Now()
+	idx.mu.Unlock()
+	return nil
+}
+
+// AfterSync implementa SyncListener: reconstruye el índice si la sincronización cambió datos
+func (idx *TickerIndex) AfterSync(changed []models.Stock) {
+	if len(changed) == 0 {
+		return
+	}
+	if err := idx.Refresh(); err != nil {
+		log.Printf("⚠️  Failed to refresh ticker index: %v", err)
+	}
+}
+
+// RefreshedAt devuelve el momento de la última reconstrucción
+func (idx *TickerIndex) RefreshedAt() time.Time {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.refreshed
+}
+
+// Suggest devuelve hasta limit tickers cuyo símbolo o compañía empieza por query.
+// Ordena por tipo de coincidencia (ticker exacto, prefijo de ticker, prefijo de compañía,
+// prefijo de una palabra de la compañía) y luego por actividad reciente.
+func (idx *TickerIndex) Suggest(query string, limit int) []models.TickerSuggestion {
+	needle := strings.ToLower(strings.TrimSpace(query))
+	if needle == "" || limit < 1 {
+		return []models.TickerSuggestion{}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	type ranked struct {
+		entry *tickerIndexEntry
+		tier  int
+	}
+	matches := make([]ranked, 0)
+	seen := make(map[int]struct{})
+
+	// Los prefijos de ticker se resuelven con búsqueda binaria sobre el orden del índice
+	start := sort.Search(len(idx.entries), func(i int) bool { return idx.entries[i].ticker >= needle })
+	for i := start; i < len(idx.entries) && strings.HasPrefix(idx.entries[i].ticker, needle); i++ {
+		tier := suggestTickerPrefix
+		if idx.entries[i].ticker == needle {
+			tier = suggestTickerExact
+		}
+		matches = append(matches, ranked{entry: &idx.entries[i], tier: tier})
+		seen[i] = struct{}{}
+	}
+
+	for i := range idx.entries {
+		if _, ok := seen[i]; ok {
+			continue
+		}
+		entry := &idx.entries[i]
+		if strings.HasPrefix(entry.company, needle) {
+			matches = append(matches, ranked{entry: entry, tier: suggestCompanyPrefix})
+			continue
+		}
+		for _, word := range entry.companyWords {
+			if strings.HasPrefix(word, needle) {
+				matches = append(matches, ranked{entry: entry, tier: suggestCompanyWord})
+				break
+			}
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.tier != b.tier {
+			return a.tier < b.tier
+		}
+		if !a.entry.item.LastSeen.Equal(b.entry.item.LastSeen) {
+			return a.entry.item.LastSeen.After(b.entry.item.LastSeen)
+		}
+		if a.entry.item.Records != b.entry.item.Records {
+			return a.entry.item.Records > b.entry.item.Records
+		}
+		return a.entry.ticker < b.entry.ticker
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	suggestions := make([]models.TickerSuggestion, len(matches))
+	for i, match := range matches {
+		suggestions[i] = match.entry.item
+		suggestions[i].Match = suggestMatchNames[match.tier]
+	}
+	return suggestions
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+)
+
+func tickerIndexRepo(calls *int) *fakeRepo {
+	base := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return &fakeRepo{
+		tickerActivityFn: func() ([]models.TickerSuggestion, error) {
+			*calls++
+			return []models.TickerSuggestion{
+				{Ticker: "AAPL", Company: "Apple Inc.", Records: 12, LastSeen: base},
+				{Ticker: "AA", Company: "Alcoa Corporation", Records: 3, LastSeen: base.Add(-48 * time.Hour)},
+				{Ticker: "AAL", Company: "American Airlines Group", Records: 5, LastSeen: base.Add(24 * time.Hour)},
+				{Ticker: "APLE", Company: "Apple Hospitality REIT", Records: 2, LastSeen: base.Add(-24 * time.Hour)},
+				{Ticker: "GOOGL", Company: "Alphabet Inc.", Records: 8, LastSeen: base},
+				{Ticker: "MSFT", Company: "Microsoft Corporation", Records: 9, LastSeen: base},
+			}, nil
+		},
+	}
+}
+
+func suggestionTickers(items []models.TickerSuggestion) []string {
+	tickers := make([]string, len(items))
+	for i, item := range items {
+		tickers[i] = item.Ticker
+	}
+	return tickers
+}
+
+func TestTickerIndex_SuggestRanking(t *testing.T) {
+	calls := 0
+	idx := NewTickerIndex(tickerIndexRepo(&calls))
+	if err := idx.Refresh(); err != nil {
+		t.Fatalf("Refresh error: %v", err)
+	}
+
+	got := suggestionTickers(idx.Suggest("aa", 10))
+	want := []string{"AA", "AAL", "AAPL"}
+	if len(got) != len(want) {
+		t.Fatalf("Suggest(aa) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("Suggest(aa) = %v, want %v", got, want)
+		}
+	}
+
+	apple := idx.Suggest("Appl", 10)
+	if len(apple) != 2 || apple[0].Ticker != "AAPL" || apple[0].Match != "company_prefix" || apple[1].Ticker != "APLE" {
+		t.Fatalf("Suggest(Appl) = %+v", apple)
+	}
+
+	words := idx.Suggest("corp", 10)
+	if len(words) != 2 || words[0].Match != "company_word" {
+		t.Fatalf("Suggest(corp) = %+v", words)
+	}
+
+	if limited := idx.Suggest("a", 2); len(limited) != 2 || limited[0].Ticker != "AAL" {
+		t.Fatalf("Suggest(a, 2) = %v", suggestionTickers(limited))
+	}
+	if empty := idx.Suggest("  ", 10); len(empty) != 0 {
+		t.Fatalf("blank query should return nothing, got %v", suggestionTickers(empty))
+	}
+}
+
+func TestTickerIndex_RefreshesAfterSync(t *testing.T) {
+	calls := 0
+	idx := NewTickerIndex(tickerIndexRepo(&calls))
+	if got := idx.Suggest("msft", 5); len(got) != 0 {
+		t.Fatalf("index should start empty, got %v", suggestionTickers(got))
+	}
+
+	idx.AfterSync(nil)
+	if calls != 0 {
+		t.Fatalf("a sync without changes must not rebuild the index")
+	}
+
+	idx.AfterSync([]models.Stock{{Ticker: "MSFT"}})
+	if calls != 1 || idx.RefreshedAt().IsZero() {
+		t.Fatalf("expected one rebuild, got %d", calls)
+	}
+	if got := idx.Suggest("msft", 5); len(got) != 1 || got[0].Match != "ticker_exact" {
+		t.Fatalf("Suggest(msft) = %+v", got)
+	}
+}
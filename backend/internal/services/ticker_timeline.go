@@ -0,0 +1,181 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// GetTickerTimeline arma las series de un ticker para graficar: pasos de precio objetivo por
+// brokerage, cambios de rating y conteo de eventos por intervalo (day, week o month, en UTC).
+// from y to son opcionales e inclusivos. Devuelve ErrNotFound si el ticker no tiene historial.
+func (s *StockService) GetTickerTimeline(ticker string, from, to *time.Time, bucket models.TimelineBucket) (*models.TickerTimeline, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	history, err := s.repo.FindByTicker(ticker)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, repositories.ErrNotFound
+	}
+
+	timeline := buildTickerTimeline(history, from, to, bucket)
+	timeline.Ticker = ticker
+	timeline.Company = CanonicalCompanyName(history)
+	return &timeline, nil
+}
+
+func buildTickerTimeline(history []models.Stock, from, to *time.Time, bucket models.TimelineBucket) models.TickerTimeline {
+	timeline := models.TickerTimeline{
+		From:          from,
+		To:            to,
+		Bucket:        bucket,
+		Targets:       []models.BrokerageTargetSeries{},
+		RatingChanges: []models.RatingChange{},
+		Events:        []models.TimelineEvents{},
+	}
+
+	inRange := make([]models.Stock, 0, len(history))
+	for _, stock := range history {
+		if (from != nil && stock.Time.Before(*from)) || (to != nil && stock.Time.After(*to)) {
+			continue
+		}
+		inRange = append(inRange, stock)
+	}
+	sort.SliceStable(inRange, func(i, j int) bool {
+		if !inRange[i].Time.Equal(inRange[j].Time) {
+			return inRange[i].Time.Before(inRange[j].Time)
+		}
+		return inRange[i].ID < inRange[j].ID
+	})
+	timeline.Records = len(inRange)
+	if len(inRange) == 0 {
+		return timeline
+	}
+
+	seriesByBrokerage := make(map[string]*models.BrokerageTargetSeries)
+	for _, stock := range inRange {
+		if change, ok := ratingChange(stock); ok {
+			timeline.RatingChanges = append(timeline.RatingChanges, change)
+		}
+
+		fromTarget, toTarget := stockTargetPrices(stock)
+		key := brokerageKey(stock.Brokerage)
+		if toTarget <= 0 || key == "" {
+			continue
+		}
+		series, ok := seriesByBrokerage[key]
+		if !ok {
+			series = &models.BrokerageTargetSeries{Steps: []models.TargetStep{}}
+			seriesByBrokerage[key] = series
+		}
+		// El nombre mostrado es el del registro más reciente de la firma
+		series.Brokerage = strings.TrimSpace(stock.Brokerage)
+
+		step := models.TargetStep{
+			Time:      stock.Time,
+			Target:    toTarget,
+			Currency:  stock.TargetCurrency,
+			EventType: stock.EventType,
+			StockID:   stock.ID,
+		}
+		if fromTarget > 0 {
+			previous := fromTarget
+			step.PreviousTarget = &previous
+		}
+		series.Steps = append(series.Steps, step)
+	}
+
+	for _, series := range seriesByBrokerage {
+		timeline.Targets = append(timeline.Targets, *series)
+	}
+	sort.Slice(timeline.Targets, func(i, j int) bool {
+		return strings.ToLower(timeline.Targets[i].Brokerage) < strings.ToLower(timeline.Targets[j].Brokerage)
+	})
+
+	timeline.Events = bucketTimelineEvents(inRange, bucket)
+	return timeline
+}
+
+// ratingChange devuelve el cambio de rating de un registro: el rating destino difiere del de origen,
+// o el evento es un upgrade, downgrade o inicio de cobertura
+func ratingChange(stock models.Stock) (models.RatingChange, bool) {
+	ratingTo := strings.TrimSpace(stock.RatingTo)
+	if ratingTo == "" {
+		return models.RatingChange{}, false
+	}
+	changed := !strings.EqualFold(strings.TrimSpace(stock.RatingFrom), ratingTo)
+	switch stock.EventType {
+	case models.ActionEventUpgrade, models.ActionEventDowngrade, models.ActionEventInitiation:
+		changed = true
+	}
+	if !changed {
+		return models.RatingChange{}, false
+	}
+
+	return models.RatingChange{
+		Time:                stock.Time,
+		Brokerage:           strings.TrimSpace(stock.Brokerage),
+		RatingFrom:          stock.RatingFrom,
+		RatingTo:            stock.RatingTo,
+		RatingFromCanonical: stock.RatingFromCanonical,
+		RatingToCanonical:   stock.RatingToCanonical,
+		RatingToScore:       stock.RatingToScore,
+		EventType:           stock.EventType,
+		StockID:             stock.ID,
+	}, true
+}
+
+// bucketTimelineEvents cuenta los eventos por intervalo, incluyendo los intervalos vacíos entre
+// el primer y el último registro para que la serie sea continua. stocks debe venir en orden ascendente.
+func bucketTimelineEvents(stocks []models.Stock, bucket models.TimelineBucket) []models.TimelineEvents {
+	first := timelineBucketStart(stocks[0].Time, bucket)
+	last := timelineBucketStart(stocks[len(stocks)-1].Time, bucket)
+
+	events := make([]models.TimelineEvents, 0)
+	index := make(map[time.Time]int)
+	for start := first; !start.After(last); start = nextTimelineBucket(start, bucket) {
+		index[start] = len(events)
+		events = append(events, models.TimelineEvents{Start: start, ByEventType: map[models.ActionEvent]int{}})
+	}
+
+	for _, stock := range stocks {
+		item := &events[index[timelineBucketStart(stock.Time, bucket)]]
+		item.Total++
+		event := stock.EventType
+		if event == "" {
+			event = models.ActionEventOther
+		}
+		item.ByEventType[event]++
+	}
+	return events
+}
+
+// timelineBucketStart trunca t en UTC al inicio del día, de la semana (lunes) o del mes
+func timelineBucketStart(t time.Time, bucket models.TimelineBucket) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch bucket {
+	case models.TimelineBucketWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case models.TimelineBucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextTimelineBucket(start time.Time, bucket models.TimelineBucket) time.Time {
+	switch bucket {
+	case models.TimelineBucketWeek:
+		return start.AddDate(0, 0, 7)
+	case models.TimelineBucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
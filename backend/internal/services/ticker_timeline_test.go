@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+func timelineHistory() []models.Stock {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 15, 0, 0, 0, time.UTC) }
+	price := func(v float64) *float64 { return &v }
+	// Orden descendente, como lo devuelve FindByTicker
+	return []models.Stock{
+		{ID: 5, Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Time: day(20), RatingFrom: "Buy", RatingTo: "Buy", EventType: models.ActionEventTargetRaise, TargetFromValue: price(210), TargetToValue: price(230), TargetCurrency: "USD"},
+		{ID: 4, Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "barclays", Time: day(14), RatingFrom: "Equal Weight", RatingTo: "Overweight", EventType: models.ActionEventUpgrade, RatingToCanonical: models.RatingBuy},
+		{ID: 3, Ticker: "AAPL", Company: "Apple", Brokerage: "Barclays", Time: day(5), RatingTo: "Equal Weight", EventType: models.ActionEventInitiation, TargetToValue: price(190)},
+		{ID: 2, Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Time: day(5), RatingFrom: "Buy", RatingTo: "Buy", EventType: models.ActionEventReiteration, TargetFromValue: price(200), TargetToValue: price(210)},
+		{ID: 1, Ticker: "AAPL", Company: "Apple Inc.", Brokerage: "Goldman Sachs", Time: time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC), RatingTo: "Buy", EventType: models.ActionEventInitiation, TargetToValue: price(200)},
+	}
+}
+
+func TestGetTickerTimeline_Series(t *testing.T) {
+	var requested string
+	svc := NewStockService(nil, &fakeRepo{
+		findByTickerFn: func(ticker string) ([]models.Stock, error) {
+			requested = ticker
+			return timelineHistory(), nil
+		},
+	})
+
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	timeline, err := svc.GetTickerTimeline(" aapl ", &from, nil, models.TimelineBucketWeek)
+	if err != nil {
+		t.Fatalf("GetTickerTimeline error: %v", err)
+	}
+	if requested != "AAPL" || timeline.Ticker != "AAPL" || timeline.Company != "Apple Inc." {
+		t.Fatalf("ticker/company = %q/%q (requested %q)", timeline.Ticker, timeline.Company, requested)
+	}
+	if timeline.Records != 4 {
+		t.Fatalf("records = %d, want 4 (December call is out of range)", timeline.Records)
+	}
+
+	if len(timeline.Targets) != 2 || timeline.Targets[0].Brokerage != "Barclays" || timeline.Targets[1].Brokerage != "Goldman Sachs" {
+		t.Fatalf("unexpected target series: %+v", timeline.Targets)
+	}
+	goldman := timeline.Targets[1].Steps
+	if len(goldman) != 2 || goldman[0].Target != 210 || *goldman[0].PreviousTarget != 200 || goldman[1].Target != 230 {
+		t.Fatalf("unexpected Goldman steps: %+v", goldman)
+	}
+	if timeline.Targets[0].Steps[0].PreviousTarget != nil {
+		t.Fatalf("initiation step should have no previous target")
+	}
+
+	if len(timeline.RatingChanges) != 2 || timeline.RatingChanges[0].StockID != 3 || timeline.RatingChanges[1].RatingTo != "Overweight" {
+		t.Fatalf("unexpected rating changes: %+v", timeline.RatingChanges)
+	}
+
+	// Semanas que empiezan el lunes 5, 12 y 19 de enero, sin huecos
+	if len(timeline.Events) != 3 {
+		t.Fatalf("events = %+v, want 3 weekly buckets", timeline.Events)
+	}
+	if !timeline.Events[0].Start.Equal(time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)) || timeline.Events[0].Total != 2 {
+		t.Fatalf("first bucket = %+v", timeline.Events[0])
+	}
+	if timeline.Events[1].ByEventType[models.ActionEventUpgrade] != 1 || timeline.Events[2].ByEventType[models.ActionEventTargetRaise] != 1 {
+		t.Fatalf("unexpected bucket counts: %+v", timeline.Events)
+	}
+}
+
+func TestGetTickerTimeline_BucketsAndEmptyRange(t *testing.T) {
+	history := timelineHistory()
+
+	daily := buildTickerTimeline(history, nil, nil, models.TimelineBucketDay)
+	if len(daily.Events) != 51 {
+		t.Fatalf("daily buckets = %d, want 51 (Dec 1 to Jan 20)", len(daily.Events))
+	}
+
+	monthly := buildTickerTimeline(history, nil, nil, models.TimelineBucketMonth)
+	if len(monthly.Events) != 2 || monthly.Events[0].Total != 1 || monthly.Events[1].Total != 4 {
+		t.Fatalf("unexpected monthly buckets: %+v", monthly.Events)
+	}
+
+	from := time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)
+	empty := buildTickerTimeline(history, &from, nil, models.TimelineBucketDay)
+	if empty.Records != 0 || empty.Targets == nil || empty.RatingChanges == nil || empty.Events == nil {
+		t.Fatalf("empty range should return empty, non-nil series: %+v", empty)
+	}
+}
+
+func TestGetTickerTimeline_NotFoundAndError(t *testing.T) {
+	svc := NewStockService(nil, &fakeRepo{})
+	if _, err := svc.GetTickerTimeline("NONE", nil, nil, models.TimelineBucketDay); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	failing := NewStockService(nil, &fakeRepo{
+		findByTickerFn: func(string) ([]models.Stock, error) { return nil, errors.New("db") },
+	})
+	if _, err := failing.GetTickerTimeline("AAPL", nil, nil, models.TimelineBucketDay); err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,336 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// Límites de una watchlist
+const (
+	maxWatchlistNameLength  = 100
+	maxWatchlistNotesLength = 2000
+	maxWatchlistTickers     = 200
+	maxTickerLength         = 10
+)
+
+var (
+	// ErrInvalidWatchlist se devuelve cuando el nombre, las notas o los tickers no son válidos
+	ErrInvalidWatchlist = errors.New("invalid watchlist")
+	// ErrWatchlistNameTaken se devuelve cuando el dueño ya tiene una watchlist con ese nombre
+	ErrWatchlistNameTaken = errors.New("watchlist name already in use")
+)
+
+// WatchlistUpdate describe un cambio parcial; los campos nil se conservan
+type WatchlistUpdate struct {
+	Name    *string
+	Notes   *string
+	Tickers *[]string
+}
+
+// WatchlistService administra las watchlists de cada dueño y consulta datos restringidos a sus tickers
+type WatchlistService struct {
+	repo            repositories.WatchlistRepository
+	stocks          repositories.StockRepository
+	recommendations *RecommendationService
+	consensus       *ConsensusService
+	now             func() time.Time
+}
+
+// NewWatchlistService crea una nueva instancia del servicio de watchlists
+func NewWatchlistService(repo repositories.WatchlistRepository, stocks repositories.StockRepository, recommendations *RecommendationService, consensus *ConsensusService) *WatchlistService {
+	return &WatchlistService{
+		repo:            repo,
+		stocks:          stocks,
+		recommendations: recommendations,
+		consensus:       consensus,
+		now:             time.Now,
+	}
+}
+
+// ListWatchlists devuelve las watchlists del dueño ordenadas por nombre
+func (s *WatchlistService) ListWatchlists(owner string) ([]models.Watchlist, error) {
+	watchlists, err := s.repo.ListWatchlists(owner)
+	if err != nil {
+		return nil, err
+	}
+	if watchlists == nil {
+		watchlists = []models.Watchlist{}
+	}
+	return watchlists, nil
+}
+
+// GetWatchlist devuelve una watchlist del dueño o ErrNotFound
+func (s *WatchlistService) GetWatchlist(owner string, id uint64) (*models.Watchlist, error) {
+	return s.repo.FindWatchlist(owner, id)
+}
+
+// CreateWatchlist valida y guarda una nueva watchlist
+func (s *WatchlistService) CreateWatchlist(owner, name, notes string, tickers []string) (*models.Watchlist, error) {
+	name, err := validateWatchlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateWatchlistNotes(notes); err != nil {
+		return nil, err
+	}
+	symbols, err := normalizeWatchlistTickers(tickers)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureNameAvailable(owner, name, 0); err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	watchlist := &models.Watchlist{
+		Owner:     owner,
+		Name:      name,
+		Notes:     notes,
+		Tickers:   watchlistTickers(nil, symbols, now),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.repo.CreateWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// UpdateWatchlist aplica un cambio parcial; si llegan tickers reemplazan la lista completa
+func (s *WatchlistService) UpdateWatchlist(owner string, id uint64, update WatchlistUpdate) (*models.Watchlist, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.Name != nil {
+		name, err := validateWatchlistName(*update.Name)
+		if err != nil {
+			return nil, err
+		}
+		if name != watchlist.Name {
+			if err := s.ensureNameAvailable(owner, name, id); err != nil {
+				return nil, err
+			}
+		}
+		watchlist.Name = name
+	}
+	if update.Notes != nil {
+		if err := validateWatchlistNotes(*update.Notes); err != nil {
+			return nil, err
+		}
+		watchlist.Notes = *update.Notes
+	}
+	now := s.now()
+	if update.Tickers != nil {
+		symbols, err := normalizeWatchlistTickers(*update.Tickers)
+		if err != nil {
+			return nil, err
+		}
+		watchlist.Tickers = watchlistTickers(watchlist.Tickers, symbols, now)
+	}
+
+	watchlist.UpdatedAt = now
+	if err := s.repo.UpdateWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// AddTickers agrega tickers al final de la watchlist; los que ya estaban se ignoran
+func (s *WatchlistService) AddTickers(owner string, id uint64, tickers []string) (*models.Watchlist, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	added, err := normalizeWatchlistTickers(tickers)
+	if err != nil {
+		return nil, err
+	}
+	if len(added) == 0 {
+		return nil, fmt.Errorf("%w: at least one ticker is required", ErrInvalidWatchlist)
+	}
+
+	symbols, err := normalizeWatchlistTickers(append(watchlist.Symbols(), added...))
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	watchlist.Tickers = watchlistTickers(watchlist.Tickers, symbols, now)
+	watchlist.UpdatedAt = now
+	if err := s.repo.UpdateWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// RemoveTicker quita un ticker de la watchlist; devuelve ErrNotFound si no estaba
+func (s *WatchlistService) RemoveTicker(owner string, id uint64, ticker string) (*models.Watchlist, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	kept := make([]models.WatchlistTicker, 0, len(watchlist.Tickers))
+	for _, item := range watchlist.Tickers {
+		if item.Ticker != ticker {
+			kept = append(kept, item)
+		}
+	}
+	if len(kept) == len(watchlist.Tickers) {
+		return nil, repositories.ErrNotFound
+	}
+
+	watchlist.Tickers = kept
+	watchlist.UpdatedAt = s.now()
+	if err := s.repo.UpdateWatchlist(watchlist); err != nil {
+		return nil, err
+	}
+	return watchlist, nil
+}
+
+// DeleteWatchlist elimina la watchlist y sus tickers
+func (s *WatchlistService) DeleteWatchlist(owner string, id uint64) error {
+	return s.repo.DeleteWatchlist(owner, id)
+}
+
+// GetLatestStocks devuelve el último registro de cada ticker de la watchlist, del más reciente al más antiguo
+func (s *WatchlistService) GetLatestStocks(owner string, id uint64) (*models.Watchlist, []models.Stock, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stocks, err := s.stocks.LatestByTickers(watchlist.Symbols())
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(stocks, func(i, j int) bool {
+		if !stocks[i].Time.Equal(stocks[j].Time) {
+			return stocks[i].Time.After(stocks[j].Time)
+		}
+		return stocks[i].Ticker < stocks[j].Ticker
+	})
+	return watchlist, stocks, nil
+}
+
+// GetRecommendations rankea solo los tickers de la watchlist; el filtro de tickers recibido se reemplaza
+func (s *WatchlistService) GetRecommendations(owner string, id uint64, filter RecommendationFilter) (*models.Watchlist, []models.StockRecommendation, models.RecommendationModel, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, nil, models.RecommendationModel{}, err
+	}
+
+	symbols := watchlist.Symbols()
+	if len(symbols) == 0 {
+		return watchlist, []models.StockRecommendation{}, s.recommendations.ModelMetadata(), nil
+	}
+	filter.Tickers = symbols
+	recommendations, model, err := s.recommendations.GetRecommendations(filter)
+	if err != nil {
+		return nil, nil, models.RecommendationModel{}, err
+	}
+	return watchlist, recommendations, model, nil
+}
+
+// GetConsensus calcula el consenso de cada ticker de la watchlist; los tickers sin registros se omiten
+func (s *WatchlistService) GetConsensus(owner string, id uint64, windowDays int) (*models.Watchlist, []models.TickerConsensus, error) {
+	watchlist, err := s.repo.FindWatchlist(owner, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := s.consensus.GetConsensusForTickers(watchlist.Symbols(), windowDays)
+	if err != nil {
+		return nil, nil, err
+	}
+	return watchlist, items, nil
+}
+
+func (s *WatchlistService) ensureNameAvailable(owner, name string, id uint64) error {
+	existing, err := s.repo.FindWatchlistByName(owner, name)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != id {
+		return ErrWatchlistNameTaken
+	}
+	return nil
+}
+
+func validateWatchlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("%w: name is required", ErrInvalidWatchlist)
+	}
+	if utf8.RuneCountInString(name) > maxWatchlistNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidWatchlist, maxWatchlistNameLength)
+	}
+	return name, nil
+}
+
+func validateWatchlistNotes(notes string) error {
+	if utf8.RuneCountInString(notes) > maxWatchlistNotesLength {
+		return fmt.Errorf("%w: notes must be at most %d characters", ErrInvalidWatchlist, maxWatchlistNotesLength)
+	}
+	return nil
+}
+
+// normalizeWatchlistTickers aplica normalizeTickerList con los límites de una watchlist
+func normalizeWatchlistTickers(tickers []string) ([]string, error) {
+	symbols, err := normalizeTickerList(tickers)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWatchlist, err)
+	}
+	if len(symbols) > maxWatchlistTickers {
+		return nil, fmt.Errorf("%w: at most %d tickers are allowed", ErrInvalidWatchlist, maxWatchlistTickers)
+	}
+	return symbols, nil
+}
+
+// normalizeTickerList pasa a mayúsculas, descarta vacíos y duplicados y conserva el orden recibido
+func normalizeTickerList(tickers []string) ([]string, error) {
+	seen := make(map[string]bool, len(tickers))
+	symbols := make([]string, 0, len(tickers))
+	for _, raw := range tickers {
+		ticker := strings.ToUpper(strings.TrimSpace(raw))
+		if ticker == "" || seen[ticker] {
+			continue
+		}
+		if len(ticker) > maxTickerLength || strings.ContainsAny(ticker, " \t,") {
+			return nil, fmt.Errorf("invalid ticker %q", raw)
+		}
+		seen[ticker] = true
+		symbols = append(symbols, ticker)
+	}
+	return symbols, nil
+}
+
+// watchlistTickers arma las filas de tickers conservando la fecha en que se agregó cada uno
+func watchlistTickers(current []models.WatchlistTicker, symbols []string, now time.Time) []models.WatchlistTicker {
+	addedAt := make(map[string]time.Time, len(current))
+	for _, item := range current {
+		addedAt[item.Ticker] = item.AddedAt
+	}
+
+	items := make([]models.WatchlistTicker, 0, len(symbols))
+	for _, symbol := range symbols {
+		added, ok := addedAt[symbol]
+		if !ok {
+			added = now
+		}
+		items = append(items, models.WatchlistTicker{Ticker: symbol, AddedAt: added})
+	}
+	return items
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+type fakeWatchlistRepo struct {
+	items  map[uint64]models.Watchlist
+	nextID uint64
+}
+
+func (f *fakeWatchlistRepo) CreateWatchlist(watchlist *models.Watchlist) error {
+	if f.items == nil {
+		f.items = map[uint64]models.Watchlist{}
+	}
+	f.nextID++
+	watchlist.ID = f.nextID
+	f.items[watchlist.ID] = *watchlist
+	return nil
+}
+
+func (f *fakeWatchlistRepo) ListWatchlists(owner string) ([]models.Watchlist, error) {
+	var watchlists []models.Watchlist
+	for _, watchlist := range f.items {
+		if watchlist.Owner == owner {
+			watchlists = append(watchlists, watchlist)
+		}
+	}
+	return watchlists, nil
+}
+
+func (f *fakeWatchlistRepo) FindWatchlist(owner string, id uint64) (*models.Watchlist, error) {
+	watchlist, ok := f.items[id]
+	if !ok || watchlist.Owner != owner {
+		return nil, repositories.ErrNotFound
+	}
+	watchlist.Tickers = append([]models.WatchlistTicker(nil), watchlist.Tickers...)
+	return &watchlist, nil
+}
+
+func (f *fakeWatchlistRepo) FindWatchlistByName(owner, name string) (*models.Watchlist, error) {
+	for _, watchlist := range f.items {
+		if watchlist.Owner == owner && watchlist.Name == name {
+			return &watchlist, nil
+		}
+	}
+	return nil, repositories.ErrNotFound
+}
+
+func (f *fakeWatchlistRepo) UpdateWatchlist(watchlist *models.Watchlist) error {
+	if _, err := f.FindWatchlist(watchlist.Owner, watchlist.ID); err != nil {
+		return err
+	}
+	f.items[watchlist.ID] = *watchlist
+	return nil
+}
+
+func (f *fakeWatchlistRepo) DeleteWatchlist(owner string, id uint64) error {
+	if _, err := f.FindWatchlist(owner, id); err != nil {
+		return err
+	}
+	delete(f.items, id)
+	return nil
+}
+
+func newTestWatchlistService(stocks repositories.StockRepository, now time.Time) *WatchlistService {
+	recommendations := NewRecommendationService(stocks)
+	recommendations.now = func() time.Time { return now }
+	consensus := NewConsensusService(stocks)
+	consensus.now = func() time.Time { return now }
+
+	service := NewWatchlistService(&fakeWatchlistRepo{}, stocks, recommendations, consensus)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestCreateWatchlist_NormalizesAndValidates(t *testing.T) {
+	service := newTestWatchlistService(&fakeRepo{}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	watchlist, err := service.CreateWatchlist("user:alice", "  Tech  ", "long term", []string{"aapl", " MSFT", "AAPL", ""})
+	if err != nil {
+		t.Fatalf("CreateWatchlist error: %v", err)
+	}
+	if watchlist.Name != "Tech" {
+		t.Fatalf("name = %q, want trimmed", watchlist.Name)
+	}
+	if symbols := watchlist.Symbols(); len(symbols) != 2 || symbols[0] != "AAPL" || symbols[1] != "MSFT" {
+		t.Fatalf("symbols = %v, want [AAPL MSFT]", symbols)
+	}
+
+	if _, err := service.CreateWatchlist("user:alice", "Tech", "", nil); !errors.Is(err, ErrWatchlistNameTaken) {
+		t.Fatalf("expected ErrWatchlistNameTaken, got %v", err)
+	}
+	if _, err := service.CreateWatchlist("user:bob", "Tech", "", nil); err != nil {
+		t.Fatalf("names are unique per owner only, got %v", err)
+	}
+	if _, err := service.CreateWatchlist("user:alice", " ", "", nil); !errors.Is(err, ErrInvalidWatchlist) {
+		t.Fatalf("expected ErrInvalidWatchlist for blank name, got %v", err)
+	}
+	if _, err := service.CreateWatchlist("user:alice", "Bad", "", []string{"NOT A TICKER"}); !errors.Is(err, ErrInvalidWatchlist) {
+		t.Fatalf("expected ErrInvalidWatchlist for bad ticker, got %v", err)
+	}
+}
+
+func TestUpdateWatchlist_KeepsAddedAtAndOwnership(t *testing.T) {
+	created := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	service := newTestWatchlistService(&fakeRepo{}, created)
+	watchlist, _ := service.CreateWatchlist("user:alice", "Tech", "", []string{"AAPL"})
+	_, _ = service.CreateWatchlist("user:alice", "Energy", "", nil)
+
+	later := created.AddDate(0, 0, 5)
+	service.now = func() time.Time { return later }
+	tickers := []string{"NVDA", "AAPL"}
+	notes := "semis"
+	updated, err := service.UpdateWatchlist("user:alice", watchlist.ID, WatchlistUpdate{Notes: &notes, Tickers: &tickers})
+	if err != nil {
+		t.Fatalf("UpdateWatchlist error: %v", err)
+	}
+	if updated.Name != "Tech" || updated.Notes != "semis" {
+		t.Fatalf("omitted fields must be kept: %+v", updated)
+	}
+	if updated.Tickers[0].Ticker != "NVDA" || !updated.Tickers[0].AddedAt.Equal(later) || !updated.Tickers[1].AddedAt.Equal(created) {
+		t.Fatalf("tickers = %+v", updated.Tickers)
+	}
+
+	taken := "Energy"
+	if _, err := service.UpdateWatchlist("user:alice", watchlist.ID, WatchlistUpdate{Name: &taken}); !errors.Is(err, ErrWatchlistNameTaken) {
+		t.Fatalf("expected ErrWatchlistNameTaken, got %v", err)
+	}
+	if _, err := service.UpdateWatchlist("user:bob", watchlist.ID, WatchlistUpdate{Notes: &notes}); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("another owner must not see the watchlist, got %v", err)
+	}
+}
+
+func TestAddAndRemoveTickers(t *testing.T) {
+	service := newTestWatchlistService(&fakeRepo{}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))
+	watchlist, _ := service.CreateWatchlist("user:alice", "Tech", "", []string{"AAPL"})
+
+	updated, err := service.AddTickers("user:alice", watchlist.ID, []string{"msft", "AAPL"})
+	if err != nil {
+		t.Fatalf("AddTickers error: %v", err)
+	}
+	if symbols := updated.Symbols(); len(symbols) != 2 || symbols[1] != "MSFT" {
+		t.Fatalf("symbols = %v", symbols)
+	}
+
+	updated, err = service.RemoveTicker("user:alice", watchlist.ID, "aapl")
+	if err != nil || len(updated.Tickers) != 1 || updated.Tickers[0].Ticker != "MSFT" {
+		t.Fatalf("RemoveTicker watchlist=%+v err=%v", updated, err)
+	}
+	if _, err := service.RemoveTicker("user:alice", watchlist.ID, "TSLA"); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a ticker not in the list, got %v", err)
+	}
+}
+
+func TestWatchlistRecommendations_RestrictedToTickers(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	stocks := &fakeRepo{findSinceFn: func(time.Time) ([]models.Stock, error) {
+		return []models.Stock{
+			{Ticker: "AAA", TargetFrom: "$100", TargetTo: "$130", Action: "Target raised", RatingFrom: "Hold", RatingTo: "Buy", Time: now.AddDate(0, 0, -1)},
+			{Ticker: "BBB", TargetFrom: "$100", TargetTo: "$150", Action: "Upgraded", RatingFrom: "Hold", RatingTo: "Strong-Buy", Time: now.AddDate(0, 0, -1)},
+		}, nil
+	}}
+	service := newTestWatchlistService(stocks, now)
+	watchlist, _ := service.CreateWatchlist("user:alice", "Mine", "", []string{"AAA"})
+
+	_, recs, _, err := service.GetRecommendations("user:alice", watchlist.ID, RecommendationFilter{Limit: 10, Tickers: []string{"BBB"}})
+	if err != nil {
+		t.Fatalf("GetRecommendations error: %v", err)
+	}
+	if len(recs) != 1 || recs[0].Stock.Ticker != "AAA" {
+		t.Fatalf("recommendations = %+v, want only AAA", recs)
+	}
+
+	empty, _ := service.CreateWatchlist("user:alice", "Empty", "", nil)
+	_, recs, _, err = service.GetRecommendations("user:alice", empty.ID, RecommendationFilter{Limit: 10})
+	if err != nil || len(recs) != 0 {
+		t.Fatalf("empty watchlist must not rank the whole universe: recs=%d err=%v", len(recs), err)
+	}
+}
+
+func TestWatchlistConsensus_SkipsTickersWithoutData(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	target := 120.0
+	queries := 0
+	stocks := &fakeRepo{
+		findByTickerFn: func(string) ([]models.Stock, error) {
+			t.Fatalf("watchlist consensus must not query ticker by ticker")
+			return nil, nil
+		},
+		findByTickersFn: func(tickers []string) ([]models.Stock, error) {
+			queries++
+			if len(tickers) != 3 {
+				t.Fatalf("expected every watchlist ticker in one query, got %v", tickers)
+			}
+			return []models.Stock{
+				{Ticker: "MSFT", Brokerage: "UBS", RatingTo: "Hold", Time: now.AddDate(0, 0, -1)},
+				{Ticker: "AAPL", Brokerage: "UBS", RatingTo: "Buy", TargetToValue: &target, Time: now.AddDate(0, 0, -2)},
+				{Ticker: "AAPL", Brokerage: "Citi", RatingTo: "Sell", Time: now.AddDate(0, 0, -200)},
+			}, nil
+		},
+	}
+	service := newTestWatchlistService(stocks, now)
+	watchlist, _ := service.CreateWatchlist("user:alice", "Mine", "", []string{"AAPL", "ZZZZ", "MSFT"})
+
+	_, items, err := service.GetConsensus("user:alice", watchlist.ID, 90)
+	if err != nil {
+		t.Fatalf("GetConsensus error: %v", err)
+	}
+	if queries != 1 {
+		t.Fatalf("history queries = %d, want 1", queries)
+	}
+	if len(items) != 2 || items[0].Ticker != "AAPL" || items[1].Ticker != "MSFT" {
+		t.Fatalf("consensus = %+v, want AAPL and MSFT", items)
+	}
+	if items[0].CoveringFirms != 1 || items[0].MedianTarget != target || items[0].WindowDays != 90 {
+		t.Fatalf("AAPL consensus should only use calls inside the window: %+v", items[0])
+	}
+}
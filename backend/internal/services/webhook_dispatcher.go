@@ -0,0 +1,565 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+// Cabeceras enviadas en cada entrega. La firma es "sha256=" + HMAC-SHA256(secret, timestamp + "." + body) en hex
+const (
+	WebhookEventHeader     = "X-StockStream-Event"
+	WebhookDeliveryHeader  = "X-StockStream-Delivery"
+	WebhookTimestampHeader = "X-StockStream-Timestamp"
+	WebhookSignatureHeader = "X-StockStream-Signature"
+)
+
+const (
+	maxWebhookURLLength    = 2048
+	minWebhookSecretLength = 16
+	maxWebhookSecretLength = 256
+	webhookDeliveryBatch   = 50
+	webhookMaxAttempts     = 8
+	webhookRetryBase       = 30 * time.Second
+	webhookMaxRetryDelay   = time.Hour
+	webhookRequestTimeout  = 10 * time.Second
+	webhookDialTimeout     = 5 * time.Second
+	webhookResponseDrain   = 4 << 10
+)
+
+// ErrInvalidWebhook se devuelve cuando la URL, el secreto o los eventos de un webhook no son válidos
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+// errWebhookTargetNotAllowed se devuelve al conectar con una dirección interna (loopback, privada, link-local...)
+var errWebhookTargetNotAllowed = errors.New("webhook target address is not allowed")
+
+// blockedWebhookPrefixes completa los rangos no públicos que netip no clasifica por sí solo
+var blockedWebhookPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// WebhookUpdate describe un cambio parcial de un webhook; los campos nil se conservan
+type WebhookUpdate struct {
+	URL        *string
+	Secret     *string
+	EventTypes *[]models.WebhookEventType
+	Enabled    *bool
+}
+
+// WebhookDispatcher administra los webhooks, encola un envío por evento y suscriptor
+// y entrega la cola con reintentos de espera exponencial
+type WebhookDispatcher struct {
+	repo        repositories.WebhookRepository
+	client      *http.Client
+	now         func() time.Time
+	retryBase   time.Duration
+	maxAttempts int
+	wake        chan struct{}
+}
+
+// NewWebhookDispatcher crea el dispatcher. recommendation.changed solo se emite si se llama a ConsumeEvents
+func NewWebhookDispatcher(repo repositories.WebhookRepository) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:        repo,
+		client:      newWebhookClient(false),
+		now:         time.Now,
+		retryBase:   webhookRetryBase,
+		maxAttempts: webhookMaxAttempts,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// SetAllowPrivateTargets permite entregar a direcciones internas (WEBHOOK_ALLOW_PRIVATE_TARGETS).
+// Solo para desarrollo y pruebas: cualquiera puede registrar un webhook
+func (d *WebhookDispatcher) SetAllowPrivateTargets(allow bool) {
+	d.client = newWebhookClient(allow)
+}
+
+// ListWebhooks devuelve los webhooks del dueño sin sus secretos
+func (d *WebhookDispatcher) ListWebhooks(owner string) ([]models.Webhook, error) {
+	webhooks, err := d.repo.ListWebhooks(owner)
+	if err != nil {
+		return nil, err
+	}
+	if webhooks == nil {
+		webhooks = []models.Webhook{}
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	return webhooks, nil
+}
+
+// GetWebhook devuelve un webhook del dueño sin su secreto, o ErrNotFound
+func (d *WebhookDispatcher) GetWebhook(owner string, id uint64) (*models.Webhook, error) {
+	webhook, err := d.repo.FindWebhook(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// CreateWebhook valida y guarda un webhook. Si no se indica secreto se genera uno;
+// el webhook devuelto es la única respuesta que lo incluye
+func (d *WebhookDispatcher) CreateWebhook(owner, rawURL, secret string, eventTypes []models.WebhookEventType, enabled bool) (*models.Webhook, error) {
+	rawURL, err := validateWebhookURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if secret, err = normalizeWebhookSecret(secret); err != nil {
+		return nil, err
+	}
+	if eventTypes, err = normalizeWebhookEventTypes(eventTypes); err != nil {
+		return nil, err
+	}
+
+	now := d.now()
+	webhook := &models.Webhook{
+		Owner:      owner,
+		URL:        rawURL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Enabled:    enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := d.repo.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return webhook, nil
+}
+
+// UpdateWebhook aplica un cambio parcial. El secreto solo se devuelve si se cambió;
+// un secreto vacío genera uno nuevo
+func (d *WebhookDispatcher) UpdateWebhook(owner string, id uint64, update WebhookUpdate) (*models.Webhook, error) {
+	webhook, err := d.repo.FindWebhook(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.URL != nil {
+		if webhook.URL, err = validateWebhookURL(*update.URL); err != nil {
+			return nil, err
+		}
+	}
+	if update.Secret != nil {
+		if webhook.Secret, err = normalizeWebhookSecret(*update.Secret); err != nil {
+			return nil, err
+		}
+	}
+	if update.EventTypes != nil {
+		if webhook.EventTypes, err = normalizeWebhookEventTypes(*update.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Enabled != nil {
+		webhook.Enabled = *update.Enabled
+	}
+
+	webhook.UpdatedAt = d.now()
+	if err := d.repo.UpdateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	if update.Secret == nil {
+		webhook.Secret = ""
+	}
+	return webhook, nil
+}
+
+// DeleteWebhook elimina el webhook y su historial de entregas
+func (d *WebhookDispatcher) DeleteWebhook(owner string, id uint64) error {
+	return d.repo.DeleteWebhook(owner, id)
+}
+
+// ListDeliveries devuelve el historial de entregas de un webhook del dueño, de la más reciente a la más antigua
+func (d *WebhookDispatcher) ListDeliveries(owner string, id uint64, status models.WebhookDeliveryStatus, limit, offset int) ([]models.WebhookDelivery, int64, error) {
+	if _, err := d.repo.FindWebhook(owner, id); err != nil {
+		return nil, 0, err
+	}
+
+	deliveries, total, err := d.repo.ListDeliveries(id, status, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if deliveries == nil {
+		deliveries = []models.WebhookDelivery{}
+	}
+	return deliveries, total, nil
+}
+
+// Publish encola un evento para todos los webhooks activos suscritos a su tipo y devuelve cuántas entregas creó
+func (d *WebhookDispatcher) Publish(eventType models.WebhookEventType, data any) (int, error) {
+	return d.enqueue([]models.WebhookEvent{d.newEvent(eventType, data)})
+}
+
+// AfterSyncResult implementa SyncResultListener: encola stock.created, stock.updated y sync.completed
+func (d *WebhookDispatcher) AfterSyncResult(result SyncResult) {
+	events := make([]models.WebhookEvent, 0, len(result.Created)+len(result.Updated)+1)
+	for _, stock := range result.Created {
+		events = append(events, d.newEvent(models.WebhookEventStockCreated, stock))
+	}
+	for _, stock := range result.Updated {
+		events = append(events, d.newEvent(models.WebhookEventStockUpdated, stock))
+	}
+	events = append(events, d.newEvent(models.WebhookEventSyncCompleted, result.Summary()))
+
+	enqueued, err := d.enqueue(events)
+	if err != nil {
+		log.Printf("⚠️  Failed to enqueue webhook deliveries: %v", err)
+		return
+	}
+	if enqueued > 0 {
+		log.Printf("✅ Enqueued %d webhook deliveries", enqueued)
+	}
+}
+
+// ConsumeEvents se suscribe al bus y, en segundo plano, encola recommendation.changed por cada
+// cambio de ranking publicado (RecommendationTracker) hasta que stop se cierre
+func (d *WebhookDispatcher) ConsumeEvents(bus *EventBus, stop <-chan struct{}) {
+	sub, _, _ := bus.Subscribe(0)
+	go d.consumeEvents(bus, sub, stop)
+}
+
+func (d *WebhookDispatcher) consumeEvents(bus *EventBus, sub *EventSubscription, stop <-chan struct{}) {
+	var lastID uint64
+	for {
+		select {
+		case <-stop:
+			sub.Close()
+			return
+		case event, ok := <-sub.Events():
+			if ok {
+				lastID = event.ID
+				d.handleEvent(event)
+				continue
+			}
+
+			// El bus desconecta a los suscriptores lentos (por ejemplo durante una sincronización grande):
+			// se vuelve a suscribir y recupera del buffer lo publicado desde el último evento visto
+			var replay []models.Event
+			var complete bool
+			sub, replay, complete = bus.Subscribe(lastID)
+			if !complete {
+				log.Printf("⚠️  Webhook event consumer fell behind; events after id %d were dropped", lastID)
+			}
+			for _, event := range replay {
+				lastID = event.ID
+				d.handleEvent(event)
+			}
+		}
+	}
+}
+
+func (d *WebhookDispatcher) handleEvent(event models.Event) {
+	if event.Type != models.EventRecommendationChanged {
+		return
+	}
+	if _, err := d.enqueue([]models.WebhookEvent{d.newEvent(models.WebhookEventRecommendationChanged, event.Data)}); err != nil {
+		log.Printf("⚠️  Failed to enqueue recommendation webhook deliveries: %v", err)
+	}
+}
+
+// StartDeliveryWorker entrega la cola cada interval, o en cuanto se encolan eventos, hasta que stop se cierre
+func (d *WebhookDispatcher) StartDeliveryWorker(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		interval = webhookRetryBase
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		d.deliverPending()
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// DeliverDue intenta un lote de entregas vencidas y devuelve cuántas procesó
+func (d *WebhookDispatcher) DeliverDue() (int, error) {
+	due, err := d.repo.DueDeliveries(d.now(), webhookDeliveryBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	var saveErr error
+	for i := range due {
+		d.attempt(&due[i])
+		if err := d.repo.UpdateDelivery(&due[i]); err != nil && saveErr == nil {
+			saveErr = err
+		}
+	}
+	return len(due), saveErr
+}
+
+// SignWebhookPayload calcula la firma que acompaña a una entrega, para verificarla del lado del receptor
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (d *WebhookDispatcher) deliverPending() {
+	for {
+		processed, err := d.DeliverDue()
+		if err != nil {
+			log.Printf("⚠️  Webhook delivery failed: %v", err)
+			return
+		}
+		if processed < webhookDeliveryBatch {
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) newEvent(eventType models.WebhookEventType, data any) models.WebhookEvent {
+	return models.WebhookEvent{
+		ID:        "evt_" + randomHex(16),
+		Type:      eventType,
+		CreatedAt: d.now(),
+		Data:      data,
+	}
+}
+
+// enqueue crea una entrega pendiente por cada par evento-webhook suscrito y despierta al worker
+func (d *WebhookDispatcher) enqueue(events []models.WebhookEvent) (int, error) {
+	webhooks, err := d.repo.ListEnabledWebhooks()
+	if err != nil {
+		return 0, err
+	}
+
+	now := d.now()
+	deliveries := make([]models.WebhookDelivery, 0)
+	for _, event := range events {
+		var payload []byte
+		for _, webhook := range webhooks {
+			if !webhook.Subscribes(event.Type) {
+				continue
+			}
+			if payload == nil {
+				if payload, err = json.Marshal(event); err != nil {
+					return 0, err
+				}
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       event.ID,
+				EventType:     event.Type,
+				Payload:       string(payload),
+				Status:        models.WebhookDeliveryPending,
+				NextAttemptAt: &now,
+				CreatedAt:     now,
+				UpdatedAt:     now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	if err := d.repo.EnqueueDeliveries(deliveries); err != nil {
+		return 0, err
+	}
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return len(deliveries), nil
+}
+
+// attempt envía la entrega una vez y deja registrado el resultado y, si falla, el próximo reintento
+func (d *WebhookDispatcher) attempt(delivery *models.WebhookDelivery) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.UpdatedAt = now
+
+	if delivery.Webhook == nil || !delivery.Webhook.Enabled {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook disabled"
+		return
+	}
+
+	status, err := d.send(delivery.Webhook, delivery, now)
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if errors.Is(err, errWebhookTargetNotAllowed) {
+		// No se guarda la dirección resuelta y no se reintenta: el destino no cambiará de categoría
+		delivery.LastError = errWebhookTargetNotAllowed.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	if delivery.Attempts >= d.maxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(d.retryDelay(delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// retryDelay duplica la espera en cada intento fallido: base, 2×base, 4×base... hasta una hora
+func (d *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.retryBase
+	for i := 1; i < attempts && delay < webhookMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, webhookMaxRetryDelay)
+}
+
+func (d *WebhookDispatcher) send(webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "StockStream-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, string(delivery.EventType))
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// El cuerpo de la respuesta no se guarda: el log de entregas es visible por la API
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookResponseDrain))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp.StatusCode, nil
+	}
+	return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+}
+
+// newWebhookClient crea el cliente de entregas. Sin allowPrivate la comprobación se hace al conectar,
+// sobre la IP ya resuelta, para que ni un DNS que cambia de respuesta ni una redirección lleguen a la red interna
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: webhookDialTimeout, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = webhookDialControl
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil // un proxy conectaría por nosotros y saltaría el control
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: webhookRequestTimeout, Transport: transport}
+}
+
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return errWebhookTargetNotAllowed
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil || !isPublicWebhookAddr(ip) {
+		return errWebhookTargetNotAllowed
+	}
+	return nil
+}
+
+// isPublicWebhookAddr rechaza loopback, redes privadas, link-local (incluida la metadata de la nube
+// en 169.254.169.254), multicast y los rangos reservados de blockedWebhookPrefixes
+func isPublicWebhookAddr(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range blockedWebhookPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+func validateWebhookURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", fmt.Errorf("%w: url is required", ErrInvalidWebhook)
+	}
+	if len(rawURL) > maxWebhookURLLength {
+		return "", fmt.Errorf("%w: url must be at most %d characters", ErrInvalidWebhook, maxWebhookURLLength)
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return "", fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+	return rawURL, nil
+}
+
+func normalizeWebhookSecret(secret string) (string, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return "whsec_" + randomHex(24), nil
+	}
+	if len(secret) < minWebhookSecretLength || len(secret) > maxWebhookSecretLength {
+		return "", fmt.Errorf("%w: secret must be between %d and %d characters", ErrInvalidWebhook, minWebhookSecretLength, maxWebhookSecretLength)
+	}
+	return secret, nil
+}
+
+// normalizeWebhookEventTypes valida los eventos y quita duplicados conservando el orden
+func normalizeWebhookEventTypes(eventTypes []models.WebhookEventType) ([]models.WebhookEventType, error) {
+	normalized := make([]models.WebhookEventType, 0, len(eventTypes))
+	seen := make(map[models.WebhookEventType]bool, len(eventTypes))
+	for _, raw := range eventTypes {
+		eventType, ok := models.ParseWebhookEventType(string(raw))
+		if !ok {
+			return nil, fmt.Errorf("%w: invalid event type %q", ErrInvalidWebhook, raw)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			normalized = append(normalized, eventType)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	return normalized, nil
+}
+
+func randomHex(n int) string {
+	buf := make([]byte, n)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,444 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Hitomiblood/StockStream/internal/models"
+	"github.com/Hitomiblood/StockStream/internal/repositories"
+)
+
+type fakeWebhookRepo struct {
+	webhooks   map[uint64]models.Webhook
+	deliveries []models.WebhookDelivery
+	nextID     uint64
+	enqueued   chan struct{} // si no es nil, recibe una señal por cada EnqueueDeliveries
+}
+
+func (f *fakeWebhookRepo) CreateWebhook(webhook *models.Webhook) error {
+	if f.webhooks == nil {
+		f.webhooks = map[uint64]models.Webhook{}
+	}
+	f.nextID++
+	webhook.ID = f.nextID
+	f.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (f *fakeWebhookRepo) ListWebhooks(owner string) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	for id := uint64(1); id <= f.nextID; id++ {
+		if webhook, ok := f.webhooks[id]; ok && webhook.Owner == owner {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (f *fakeWebhookRepo) ListEnabledWebhooks() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	for id := uint64(1); id <= f.nextID; id++ {
+		if webhook, ok := f.webhooks[id]; ok && webhook.Enabled {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks, nil
+}
+
+func (f *fakeWebhookRepo) FindWebhook(owner string, id uint64) (*models.Webhook, error) {
+	webhook, ok := f.webhooks[id]
+	if !ok || webhook.Owner != owner {
+		return nil, repositories.ErrNotFound
+	}
+	return &webhook, nil
+}
+
+func (f *fakeWebhookRepo) UpdateWebhook(webhook *models.Webhook) error {
+	if _, err := f.FindWebhook(webhook.Owner, webhook.ID); err != nil {
+		return err
+	}
+	f.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (f *fakeWebhookRepo) DeleteWebhook(owner string, id uint64) error {
+	if _, err := f.FindWebhook(owner, id); err != nil {
+		return err
+	}
+	delete(f.webhooks, id)
+	return nil
+}
+
+func (f *fakeWebhookRepo) EnqueueDeliveries(deliveries []models.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		f.nextID++
+		delivery.ID = f.nextID
+		f.deliveries = append(f.deliveries, delivery)
+	}
+	if f.enqueued != nil {
+		f.enqueued <- struct{}{}
+	}
+	return nil
+}
+
+func (f *fakeWebhookRepo) DueDeliveries(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var due []models.WebhookDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) || len(due) == limit {
+			continue
+		}
+		if webhook, ok := f.webhooks[delivery.WebhookID]; ok {
+			delivery.Webhook = &webhook
+		}
+		due = append(due, delivery)
+	}
+	return due, nil
+}
+
+func (f *fakeWebhookRepo) UpdateDelivery(delivery *models.WebhookDelivery) error {
+	for i := range f.deliveries {
+		if f.deliveries[i].ID == delivery.ID {
+			updated := *delivery
+			updated.Webhook = nil
+			f.deliveries[i] = updated
+			return nil
+		}
+	}
+	return repositories.ErrNotFound
+}
+
+func (f *fakeWebhookRepo) ListDeliveries(webhookID uint64, status models.WebhookDeliveryStatus, _, _ int) ([]models.WebhookDelivery, int64, error) {
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range f.deliveries {
+		if delivery.WebhookID == webhookID && (status == "" || delivery.Status == status) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, int64(len(deliveries)), nil
+}
+
+type fakeRanker struct {
+	rankings [][]models.StockRecommendation
+	calls    int
+}
+
+func (f *fakeRanker) GetRecommendations(RecommendationFilter) ([]models.StockRecommendation, models.RecommendationModel, error) {
+	ranking := f.rankings[min(f.calls, len(f.rankings)-1)]
+	f.calls++
+	return ranking, models.RecommendationModel{}, nil
+}
+
+func recommendation(ticker string, score float64, confidence models.ConfidenceLevel) models.StockRecommendation {
+	return models.StockRecommendation{Stock: models.Stock{Ticker: ticker}, Score: score, Confidence: confidence}
+}
+
+func TestWebhookDispatcher_SignsAndRetriesWithBackoff(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	var mu sync.Mutex
+	var requests []received
+	failures := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, received{header: r.Header.Clone(), body: body})
+		if failures > 0 {
+			failures--
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	dispatcher := NewWebhookDispatcher(repo)
+	dispatcher.SetAllowPrivateTargets(true) // httptest escucha en loopback
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+
+	webhook, err := dispatcher.CreateWebhook("user:alice", server.URL, "", []models.WebhookEventType{"STOCK.CREATED", "sync.completed"}, true)
+	if err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+	if len(webhook.Secret) < minWebhookSecretLength {
+		t.Fatalf("expected a generated secret, got %q", webhook.Secret)
+	}
+	if _, err := dispatcher.CreateWebhook("user:bob", server.URL, "", []models.WebhookEventType{models.WebhookEventStockUpdated}, true); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+
+	enqueued, err := dispatcher.Publish(models.WebhookEventStockCreated, models.Stock{ID: 7, Ticker: "AAPL"})
+	if err != nil || enqueued != 1 {
+		t.Fatalf("Publish enqueued=%d err=%v, want 1 delivery for the subscribed webhook", enqueued, err)
+	}
+
+	// Primer intento: 503, se reprograma a base; segundo: 503, se reprograma a 2×base; tercero: entregado
+	for attempt, wantDelay := range []time.Duration{webhookRetryBase, 2 * webhookRetryBase} {
+		if processed, err := dispatcher.DeliverDue(); processed != 1 || err != nil {
+			t.Fatalf("attempt %d: processed=%d err=%v", attempt+1, processed, err)
+		}
+		delivery := repo.deliveries[0]
+		if delivery.Status != models.WebhookDeliveryPending || delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.LastError != "HTTP 503" {
+			t.Fatalf("attempt %d: unexpected delivery %+v", attempt+1, delivery)
+		}
+		if got := delivery.NextAttemptAt.Sub(now); got != wantDelay {
+			t.Fatalf("attempt %d: retry in %v, want %v", attempt+1, got, wantDelay)
+		}
+		if processed, _ := dispatcher.DeliverDue(); processed != 0 {
+			t.Fatalf("attempt %d: delivery retried before its backoff elapsed", attempt+1)
+		}
+		now = *delivery.NextAttemptAt
+	}
+	if processed, err := dispatcher.DeliverDue(); processed != 1 || err != nil {
+		t.Fatalf("final attempt: processed=%d err=%v", processed, err)
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 3 || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Fatalf("unexpected delivered state: %+v", delivery)
+	}
+
+	last := requests[len(requests)-1]
+	if last.header.Get(WebhookEventHeader) != "stock.created" || last.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("unexpected headers: %v", last.header)
+	}
+	if want := SignWebhookPayload(webhook.Secret, last.header.Get(WebhookTimestampHeader), last.body); last.header.Get(WebhookSignatureHeader) != want {
+		t.Fatalf("signature = %q, want %q", last.header.Get(WebhookSignatureHeader), want)
+	}
+	var event struct {
+		ID   string       `json:"id"`
+		Type string       `json:"type"`
+		Data models.Stock `json:"data"`
+	}
+	if err := json.Unmarshal(last.body, &event); err != nil || event.ID != delivery.EventID || event.Data.Ticker != "AAPL" {
+		t.Fatalf("unexpected payload %s (err %v)", last.body, err)
+	}
+}
+
+func TestWebhookDispatcher_GivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	dispatcher := NewWebhookDispatcher(repo)
+	dispatcher.SetAllowPrivateTargets(true)
+	dispatcher.maxAttempts = 3
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+
+	if _, err := dispatcher.CreateWebhook("user:alice", server.URL, "a-very-long-shared-secret", []models.WebhookEventType{models.WebhookEventSyncCompleted}, true); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+	if _, err := dispatcher.Publish(models.WebhookEventSyncCompleted, models.SyncSummary{Created: 1}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		_, _ = dispatcher.DeliverDue()
+		now = now.Add(webhookMaxRetryDelay)
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.Attempts != 3 || delivery.NextAttemptAt != nil || delivery.LastError != "HTTP 500" {
+		t.Fatalf("expected a failed delivery after 3 attempts, got %+v", delivery)
+	}
+	if processed, _ := dispatcher.DeliverDue(); processed != 0 {
+		t.Fatalf("failed deliveries must not be retried")
+	}
+}
+
+func TestWebhookDispatcher_BlocksInternalTargets(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	repo := &fakeWebhookRepo{}
+	dispatcher := NewWebhookDispatcher(repo)
+	if _, err := dispatcher.CreateWebhook("user:mallory", server.URL, "", []models.WebhookEventType{models.WebhookEventSyncCompleted}, true); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+	if _, err := dispatcher.Publish(models.WebhookEventSyncCompleted, models.SyncSummary{}); err != nil {
+		t.Fatalf("Publish error: %v", err)
+	}
+	if _, err := dispatcher.DeliverDue(); err != nil {
+		t.Fatalf("DeliverDue error: %v", err)
+	}
+
+	delivery := repo.deliveries[0]
+	if hits != 0 || delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil || delivery.LastError != errWebhookTargetNotAllowed.Error() {
+		t.Fatalf("loopback target should fail without a request or retry: hits=%d delivery=%+v", hits, delivery)
+	}
+
+	for addr, want := range map[string]bool{
+		"93.184.216.34":   true,
+		"127.0.0.1":       false,
+		"10.1.2.3":        false,
+		"192.168.0.10":    false,
+		"169.254.169.254": false,
+		"100.100.100.200": false,
+		"::1":             false,
+		"fd00:ec2::254":   false,
+		"::ffff:10.0.0.1": false,
+	} {
+		if got := isPublicWebhookAddr(netip.MustParseAddr(addr)); got != want {
+			t.Fatalf("isPublicWebhookAddr(%s) = %v, want %v", addr, got, want)
+		}
+	}
+}
+
+func TestWebhookDispatcher_AfterSyncResultEnqueuesSubscribedEvents(t *testing.T) {
+	repo := &fakeWebhookRepo{}
+	dispatcher := NewWebhookDispatcher(repo)
+
+	if _, err := dispatcher.CreateWebhook("user:alice", "https://hooks.example.com/all", "", models.WebhookEventTypes, true); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+	if _, err := dispatcher.CreateWebhook("user:bob", "https://hooks.example.com/paused", "", models.WebhookEventTypes, false); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	dispatcher.AfterSyncResult(SyncResult{
+		Created:    []models.Stock{{ID: 1, Ticker: "NVDA"}, {ID: 2, Ticker: "AMD"}},
+		Updated:    []models.Stock{{ID: 3, Ticker: "MSFT"}},
+		StartedAt:  started,
+		FinishedAt: started.Add(1500 * time.Millisecond),
+	})
+
+	counts := map[models.WebhookEventType]int{}
+	for _, delivery := range repo.deliveries {
+		counts[delivery.EventType]++
+	}
+	want := map[models.WebhookEventType]int{
+		models.WebhookEventStockCreated:  2,
+		models.WebhookEventStockUpdated:  1,
+		models.WebhookEventSyncCompleted: 1,
+	}
+	if len(repo.deliveries) != 4 {
+		t.Fatalf("got %d deliveries (%v), want 4 for the enabled webhook only", len(repo.deliveries), counts)
+	}
+	for eventType, count := range want {
+		if counts[eventType] != count {
+			t.Fatalf("%s deliveries = %d, want %d", eventType, counts[eventType], count)
+		}
+	}
+
+	for _, delivery := range repo.deliveries {
+		if delivery.EventType != models.WebhookEventSyncCompleted {
+			continue
+		}
+		var event struct {
+			Data models.SyncSummary `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(delivery.Payload), &event); err != nil || event.Data.Created != 2 || event.Data.Updated != 1 || event.Data.DurationMs != 1500 {
+			t.Fatalf("unexpected sync.completed payload %s (err %v)", delivery.Payload, err)
+		}
+	}
+}
+
+func TestWebhookDispatcher_ConsumesRecommendationChangesFromBus(t *testing.T) {
+	repo := &fakeWebhookRepo{enqueued: make(chan struct{}, 10)}
+	ranker := &fakeRanker{rankings: [][]models.StockRecommendation{
+		{recommendation("AAPL", 9, models.ConfidenceHigh), recommendation("MSFT", 7, models.ConfidenceMedium)},
+		{recommendation("AAPL", 9.5, models.ConfidenceHigh), recommendation("NVDA", 8, models.ConfidenceHigh), recommendation("MSFT", 6, models.ConfidenceLow)},
+	}}
+	bus := NewEventBus(10)
+	tracker := NewRecommendationTracker(ranker, 10)
+	tracker.SetEventPublisher(bus)
+	if err := tracker.Load(); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	dispatcher := NewWebhookDispatcher(repo)
+	if _, err := dispatcher.CreateWebhook("user:alice", "https://hooks.example.com/ranking", "", []models.WebhookEventType{models.WebhookEventRecommendationChanged}, true); err != nil {
+		t.Fatalf("CreateWebhook error: %v", err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	dispatcher.ConsumeEvents(bus, stop)
+
+	tracker.AfterSyncResult(SyncResult{Created: []models.Stock{{ID: 1, Ticker: "NVDA"}}})
+
+	// Cada cambio publicado en el bus se encola por separado
+	for i := 0; i < 2; i++ {
+		select {
+		case <-repo.enqueued:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for recommendation deliveries (%d so far)", i)
+		}
+	}
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("got %d deliveries, want 2", len(repo.deliveries))
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.EventType != models.WebhookEventRecommendationChanged || !strings.Contains(delivery.Payload, `"change":`) {
+			t.Fatalf("unexpected delivery: %+v", delivery)
+		}
+	}
+}
+
+func TestWebhookDispatcher_ValidatesAndHidesSecrets(t *testing.T) {
+	dispatcher := NewWebhookDispatcher(&fakeWebhookRepo{})
+
+	tests := map[string]struct {
+		url        string
+		secret     string
+		eventTypes []models.WebhookEventType
+	}{
+		"missing url":   {url: "", eventTypes: []models.WebhookEventType{models.WebhookEventStockCreated}},
+		"relative url":  {url: "/hooks", eventTypes: []models.WebhookEventType{models.WebhookEventStockCreated}},
+		"ftp url":       {url: "ftp://example.com", eventTypes: []models.WebhookEventType{models.WebhookEventStockCreated}},
+		"short secret":  {url: "https://example.com", secret: "short", eventTypes: []models.WebhookEventType{models.WebhookEventStockCreated}},
+		"no events":     {url: "https://example.com"},
+		"unknown event": {url: "https://example.com", eventTypes: []models.WebhookEventType{"stock.deleted"}},
+	}
+	for name, tc := range tests {
+		if _, err := dispatcher.CreateWebhook("user:alice", tc.url, tc.secret, tc.eventTypes, true); !errors.Is(err, ErrInvalidWebhook) {
+			t.Fatalf("%s: expected ErrInvalidWebhook, got %v", name, err)
+		}
+	}
+
+	created, err := dispatcher.CreateWebhook("user:alice", "https://example.com/hook", "", []models.WebhookEventType{"stock.created", "stock.created"}, true)
+	if err != nil || len(created.EventTypes) != 1 {
+		t.Fatalf("CreateWebhook webhook=%+v err=%v", created, err)
+	}
+	secret := created.Secret
+
+	listed, _ := dispatcher.ListWebhooks("user:alice")
+	fetched, _ := dispatcher.GetWebhook("user:alice", created.ID)
+	if len(listed) != 1 || listed[0].Secret != "" || fetched.Secret != "" {
+		t.Fatalf("secrets must not be returned after creation: %+v %+v", listed, fetched)
+	}
+
+	disabled := false
+	updated, err := dispatcher.UpdateWebhook("user:alice", created.ID, WebhookUpdate{Enabled: &disabled})
+	if err != nil || updated.Enabled || updated.Secret != "" {
+		t.Fatalf("UpdateWebhook webhook=%+v err=%v", updated, err)
+	}
+	rotate := ""
+	rotated, err := dispatcher.UpdateWebhook("user:alice", created.ID, WebhookUpdate{Secret: &rotate})
+	if err != nil || rotated.Secret == "" || rotated.Secret == secret {
+		t.Fatalf("rotating the secret should return a new one: %+v err=%v", rotated, err)
+	}
+
+	if _, _, err := dispatcher.ListDeliveries("user:bob", created.ID, "", 50, 0); !errors.Is(err, repositories.ErrNotFound) {
+		t.Fatalf("another owner must not see the delivery log, got %v", err)
+	}
+}
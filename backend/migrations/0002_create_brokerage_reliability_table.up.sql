@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS brokerage_reliability (
+  brokerage STRING PRIMARY KEY,
+  score FLOAT8 NOT NULL DEFAULT 0,
+  multiplier FLOAT8 NOT NULL DEFAULT 1,
+  total_calls INT8 NOT NULL DEFAULT 0,
+  directional_calls INT8 NOT NULL DEFAULT 0,
+  confirmed_calls INT8 NOT NULL DEFAULT 0,
+  contradicted_calls INT8 NOT NULL DEFAULT 0,
+  reversals INT8 NOT NULL DEFAULT 0,
+  tickers_covered INT8 NOT NULL DEFAULT 0,
+  confirmation_rate FLOAT8 NOT NULL DEFAULT 0,
+  reversal_rate FLOAT8 NOT NULL DEFAULT 0,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_brokerage_reliability_score ON brokerage_reliability (score DESC);
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS rating_mappings;
+
+DROP INDEX IF EXISTS stocks@idx_stocks_rating_to_canonical;
+
+ALTER TABLE stocks DROP COLUMN IF EXISTS rating_to_score;
+ALTER TABLE stocks DROP COLUMN IF EXISTS rating_from_score;
+ALTER TABLE stocks DROP COLUMN IF EXISTS rating_to_canonical;
+ALTER TABLE stocks DROP COLUMN IF EXISTS rating_from_canonical;
@@ -0,0 +1,14 @@
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS rating_from_canonical STRING NOT NULL DEFAULT '';
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS rating_to_canonical STRING NOT NULL DEFAULT '';
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS rating_from_score FLOAT8 NOT NULL DEFAULT 0;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS rating_to_score FLOAT8 NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_stocks_rating_to_canonical ON stocks (rating_to_canonical);
+
+CREATE TABLE IF NOT EXISTS rating_mappings (
+  raw_rating STRING PRIMARY KEY,
+  canonical STRING NOT NULL,
+  score FLOAT8 NOT NULL DEFAULT 0,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
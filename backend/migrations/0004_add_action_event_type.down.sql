@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_event_type;
+
+ALTER TABLE stocks DROP COLUMN IF EXISTS event_type;
@@ -0,0 +1,3 @@
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS event_type STRING NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_stocks_event_type ON stocks (event_type);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS stocks@idx_stocks_target_change_pct;
+DROP INDEX IF EXISTS stocks@idx_stocks_target_to_value;
+
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_change_pct;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_currency;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_to_value;
+ALTER TABLE stocks DROP COLUMN IF EXISTS target_from_value;
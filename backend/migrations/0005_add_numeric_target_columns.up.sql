@@ -0,0 +1,7 @@
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_from_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_to_value FLOAT8;
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_currency STRING NOT NULL DEFAULT '';
+ALTER TABLE stocks ADD COLUMN IF NOT EXISTS target_change_pct FLOAT8;
+
+CREATE INDEX IF NOT EXISTS idx_stocks_target_to_value ON stocks (target_to_value);
+CREATE INDEX IF NOT EXISTS idx_stocks_target_change_pct ON stocks (target_change_pct);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tickers;
+DROP TABLE IF EXISTS companies;
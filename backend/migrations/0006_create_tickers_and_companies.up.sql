@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS companies (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  name STRING NOT NULL,
+  normalized_name STRING NOT NULL,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_companies_normalized_name ON companies (normalized_name);
+
+CREATE TABLE IF NOT EXISTS tickers (
+  ticker STRING PRIMARY KEY,
+  company_id BIGINT REFERENCES companies (id),
+  first_seen TIMESTAMPTZ NOT NULL,
+  last_seen TIMESTAMPTZ NOT NULL,
+  record_count INT8 NOT NULL DEFAULT 0,
+  latest_stock_id BIGINT NOT NULL DEFAULT 0,
+  latest_rating STRING NOT NULL DEFAULT '',
+  latest_rating_canonical STRING NOT NULL DEFAULT '',
+  latest_brokerage STRING NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_tickers_company_id ON tickers (company_id);
+CREATE INDEX IF NOT EXISTS idx_tickers_last_seen ON tickers (last_seen DESC);
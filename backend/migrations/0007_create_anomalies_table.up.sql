@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS anomalies (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  fingerprint STRING NOT NULL,
+  kind STRING NOT NULL,
+  ticker STRING NOT NULL,
+  severity STRING NOT NULL,
+  score FLOAT8 NOT NULL DEFAULT 0,
+  summary STRING NOT NULL DEFAULT '',
+  window_start TIMESTAMPTZ NOT NULL,
+  window_end TIMESTAMPTZ NOT NULL,
+  stock_ids JSONB NOT NULL DEFAULT '[]',
+  detected_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_anomalies_fingerprint ON anomalies (fingerprint);
+CREATE INDEX IF NOT EXISTS idx_anomalies_ticker ON anomalies (ticker);
+CREATE INDEX IF NOT EXISTS idx_anomalies_detected_at ON anomalies (detected_at DESC);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS watchlists (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  owner STRING NOT NULL,
+  name STRING NOT NULL,
+  notes STRING NOT NULL DEFAULT '',
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_watchlists_owner_name ON watchlists (owner, name);
+
+CREATE TABLE IF NOT EXISTS watchlist_tickers (
+  watchlist_id BIGINT NOT NULL REFERENCES watchlists (id) ON DELETE CASCADE,
+  ticker STRING NOT NULL,
+  added_at TIMESTAMPTZ NOT NULL,
+  PRIMARY KEY (watchlist_id, ticker)
+);
+
+CREATE INDEX IF NOT EXISTS idx_watchlist_tickers_ticker ON watchlist_tickers (ticker);
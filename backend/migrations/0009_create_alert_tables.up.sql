@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS alert_rules (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  owner STRING NOT NULL,
+  name STRING NOT NULL,
+  conditions JSONB NOT NULL DEFAULT '{}',
+  enabled BOOL NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_alert_rules_owner ON alert_rules (owner);
+CREATE INDEX IF NOT EXISTS idx_alert_rules_enabled ON alert_rules (enabled);
+
+CREATE TABLE IF NOT EXISTS alerts (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  rule_id BIGINT NOT NULL,
+  rule_name STRING NOT NULL,
+  owner STRING NOT NULL,
+  stock_id BIGINT NOT NULL,
+  ticker STRING NOT NULL,
+  summary STRING NOT NULL DEFAULT '',
+  fired_at TIMESTAMPTZ NOT NULL,
+  created_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS ux_alerts_rule_stock ON alerts (rule_id, stock_id);
+CREATE INDEX IF NOT EXISTS idx_alerts_owner_fired_at ON alerts (owner, fired_at DESC);
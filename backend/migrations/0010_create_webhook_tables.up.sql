@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS webhooks (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  owner STRING NOT NULL,
+  url STRING NOT NULL,
+  secret STRING NOT NULL,
+  event_types JSONB NOT NULL DEFAULT '[]',
+  enabled BOOL NOT NULL DEFAULT true,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_owner ON webhooks (owner);
+CREATE INDEX IF NOT EXISTS idx_webhooks_enabled ON webhooks (enabled);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+  id BIGINT PRIMARY KEY DEFAULT unique_rowid(),
+  webhook_id BIGINT NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+  event_id STRING NOT NULL,
+  event_type STRING NOT NULL,
+  payload STRING NOT NULL,
+  status STRING NOT NULL DEFAULT 'pending',
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMPTZ,
+  last_attempt_at TIMESTAMPTZ,
+  response_status INT NOT NULL DEFAULT 0,
+  last_error STRING NOT NULL DEFAULT '',
+  delivered_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ,
+  updated_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook ON webhook_deliveries (webhook_id, created_at DESC);
@@ -0,0 +1,3 @@
+ALTER TABLE anomalies ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+
+UPDATE anomalies SET last_seen_at = detected_at WHERE last_seen_at IS NULL;
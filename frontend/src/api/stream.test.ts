@@ -0,0 +1,54 @@
+import { afterEach, describe, expect, it, vi } from 'vitest'
+
+import { subscribeToSyncCompleted } from '@/api/stream'
+
+class EventSourceMock {
+  static instances: EventSourceMock[] = []
+
+  url: string
+  listeners: Record<string, (event: MessageEvent<string>) => void> = {}
+  close = vi.fn()
+
+  constructor(url: string) {
+    this.url = url
+    EventSourceMock.instances.push(this)
+  }
+
+  addEventListener(type: string, listener: (event: MessageEvent<string>) => void): void {
+    this.listeners[type] = listener
+  }
+
+  emit(type: string, data: string): void {
+    this.listeners[type]?.({ data } as MessageEvent<string>)
+  }
+}
+
+describe('api/stream', () => {
+  afterEach(() => {
+    EventSourceMock.instances = []
+    vi.unstubAllGlobals()
+  })
+
+  it('calls the handler with the summary of each completed sync', () => {
+    vi.stubGlobal('EventSource', EventSourceMock)
+    const onCompleted = vi.fn()
+
+    const unsubscribe = subscribeToSyncCompleted(onCompleted)
+    const source = EventSourceMock.instances[0]
+    expect(source.url).toMatch(/\/stream$/)
+
+    source.emit('sync.completed', JSON.stringify({ id: '1', type: 'sync.completed', data: { created: 2, updated: 1 } }))
+    source.emit('sync.completed', 'not json')
+    expect(onCompleted).toHaveBeenCalledTimes(1)
+    expect(onCompleted).toHaveBeenCalledWith({ created: 2, updated: 1 })
+
+    unsubscribe()
+    expect(source.close).toHaveBeenCalled()
+  })
+
+  it('is a no-op where EventSource is unavailable', () => {
+    vi.stubGlobal('EventSource', undefined)
+
+    expect(() => subscribeToSyncCompleted(vi.fn())()).not.toThrow()
+  })
+})
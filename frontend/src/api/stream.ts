@@ -0,0 +1,22 @@
+import { API_BASE_URL } from '@/api/http'
+import type { SyncSummary } from '@/types/domain'
+
+export type SyncCompletedHandler = (summary: SyncSummary) => void
+
+export function subscribeToSyncCompleted(onCompleted: SyncCompletedHandler): () => void {
+  if (typeof EventSource === 'undefined') {
+    return () => {}
+  }
+
+  const source = new EventSource(`${API_BASE_URL}/stream`)
+  source.addEventListener('sync.completed', (event) => {
+    try {
+      const { data } = JSON.parse((event as MessageEvent<string>).data) as { data: SyncSummary }
+      onCompleted(data)
+    } catch {
+      // Ignore malformed frames; the next sync will trigger a refresh.
+    }
+  })
+
+  return () => source.close()
+}
@@ -39,7 +39,17 @@ describe('stores/recommendations', () => {
       ],
       generated_at: '2026-02-01T00:00:00Z',
       count: 1,
-      criteria: {}
+      model: {
+        name: 'weighted-feature-scoring',
+        version: '2.0.0',
+        weights: [],
+        thresholds: { minimum_accepted_score: 0, maximum_bearish_score: 0, confidence: [] },
+        brokerage_weighting: false,
+        lookback_days: 90,
+        window_start: '2026-01-01T00:00:00Z',
+        records_evaluated: 0,
+        tickers_evaluated: 0
+      }
     })
 
     const store = useRecommendationsStore()
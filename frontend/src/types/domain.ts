@@ -72,11 +72,39 @@ export interface Recommendation {
   confidence: 'low' | 'medium' | 'high'
 }
 
+export interface FeatureWeight {
+  label: string
+  weight: number
+}
+
+export interface ConfidenceRule {
+  level: 'low' | 'medium' | 'high'
+  min_score: number
+  min_history: number
+  min_data_quality: number
+}
+
+export interface RecommendationModel {
+  name: string
+  version: string
+  weights: FeatureWeight[]
+  thresholds: {
+    minimum_accepted_score: number
+    maximum_bearish_score: number
+    confidence: ConfidenceRule[]
+  }
+  brokerage_weighting: boolean
+  lookback_days: number
+  window_start: string
+  records_evaluated: number
+  tickers_evaluated: number
+}
+
 export interface RecommendationsResponse {
   recommendations: Recommendation[]
   generated_at: string
   count: number
-  criteria: Record<string, number>
+  model: RecommendationModel
 }
 
 export interface SyncResponse {
@@ -86,3 +114,11 @@ export interface SyncResponse {
   total_fetched: number
   duration_ms: number
 }
+
+export interface SyncSummary {
+  created: number
+  updated: number
+  started_at: string
+  finished_at: string
+  duration_ms: number
+}